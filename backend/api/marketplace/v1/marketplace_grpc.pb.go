@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v4.25.3
+// source: api/marketplace/v1/marketplace.proto
+
+package marketplacev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	MarketplaceService_GetItem_FullMethodName          = "/marketplace.v1.MarketplaceService/GetItem"
+	MarketplaceService_ListItem_FullMethodName         = "/marketplace.v1.MarketplaceService/ListItem"
+	MarketplaceService_PurchaseItem_FullMethodName     = "/marketplace.v1.MarketplaceService/PurchaseItem"
+	MarketplaceService_WatchItemUpdates_FullMethodName = "/marketplace.v1.MarketplaceService/WatchItemUpdates"
+)
+
+// MarketplaceServiceClient is the client API for MarketplaceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MarketplaceService mirrors the HTTP item endpoints for internal callers.
+// Every call must carry a "userid" metadata entry, like the UserID header on HTTP.
+type MarketplaceServiceClient interface {
+	GetItem(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*GetItemResponse, error)
+	ListItem(ctx context.Context, in *ListItemRequest, opts ...grpc.CallOption) (*ListItemResponse, error)
+	PurchaseItem(ctx context.Context, in *PurchaseItemRequest, opts ...grpc.CallOption) (*PurchaseItemResponse, error)
+	// WatchItemUpdates streams state changes of the caller's items until the client cancels
+	WatchItemUpdates(ctx context.Context, in *WatchItemUpdatesRequest, opts ...grpc.CallOption) (MarketplaceService_WatchItemUpdatesClient, error)
+}
+
+type marketplaceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMarketplaceServiceClient(cc grpc.ClientConnInterface) MarketplaceServiceClient {
+	return &marketplaceServiceClient{cc}
+}
+
+func (c *marketplaceServiceClient) GetItem(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*GetItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetItemResponse)
+	err := c.cc.Invoke(ctx, MarketplaceService_GetItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) ListItem(ctx context.Context, in *ListItemRequest, opts ...grpc.CallOption) (*ListItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListItemResponse)
+	err := c.cc.Invoke(ctx, MarketplaceService_ListItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) PurchaseItem(ctx context.Context, in *PurchaseItemRequest, opts ...grpc.CallOption) (*PurchaseItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurchaseItemResponse)
+	err := c.cc.Invoke(ctx, MarketplaceService_PurchaseItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) WatchItemUpdates(ctx context.Context, in *WatchItemUpdatesRequest, opts ...grpc.CallOption) (MarketplaceService_WatchItemUpdatesClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MarketplaceService_ServiceDesc.Streams[0], MarketplaceService_WatchItemUpdates_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &marketplaceServiceWatchItemUpdatesClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MarketplaceService_WatchItemUpdatesClient interface {
+	Recv() (*ItemUpdate, error)
+	grpc.ClientStream
+}
+
+type marketplaceServiceWatchItemUpdatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *marketplaceServiceWatchItemUpdatesClient) Recv() (*ItemUpdate, error) {
+	m := new(ItemUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MarketplaceServiceServer is the server API for MarketplaceService service.
+// All implementations must embed UnimplementedMarketplaceServiceServer
+// for forward compatibility
+//
+// MarketplaceService mirrors the HTTP item endpoints for internal callers.
+// Every call must carry a "userid" metadata entry, like the UserID header on HTTP.
+type MarketplaceServiceServer interface {
+	GetItem(context.Context, *GetItemRequest) (*GetItemResponse, error)
+	ListItem(context.Context, *ListItemRequest) (*ListItemResponse, error)
+	PurchaseItem(context.Context, *PurchaseItemRequest) (*PurchaseItemResponse, error)
+	// WatchItemUpdates streams state changes of the caller's items until the client cancels
+	WatchItemUpdates(*WatchItemUpdatesRequest, MarketplaceService_WatchItemUpdatesServer) error
+	mustEmbedUnimplementedMarketplaceServiceServer()
+}
+
+// UnimplementedMarketplaceServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMarketplaceServiceServer struct {
+}
+
+func (UnimplementedMarketplaceServiceServer) GetItem(context.Context, *GetItemRequest) (*GetItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetItem not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) ListItem(context.Context, *ListItemRequest) (*ListItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListItem not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) PurchaseItem(context.Context, *PurchaseItemRequest) (*PurchaseItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurchaseItem not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) WatchItemUpdates(*WatchItemUpdatesRequest, MarketplaceService_WatchItemUpdatesServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchItemUpdates not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) mustEmbedUnimplementedMarketplaceServiceServer() {}
+
+// UnsafeMarketplaceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MarketplaceServiceServer will
+// result in compilation errors.
+type UnsafeMarketplaceServiceServer interface {
+	mustEmbedUnimplementedMarketplaceServiceServer()
+}
+
+func RegisterMarketplaceServiceServer(s grpc.ServiceRegistrar, srv MarketplaceServiceServer) {
+	s.RegisterService(&MarketplaceService_ServiceDesc, srv)
+}
+
+func _MarketplaceService_GetItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).GetItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_GetItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).GetItem(ctx, req.(*GetItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_ListItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).ListItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_ListItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).ListItem(ctx, req.(*ListItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_PurchaseItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurchaseItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).PurchaseItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_PurchaseItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).PurchaseItem(ctx, req.(*PurchaseItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_WatchItemUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchItemUpdatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketplaceServiceServer).WatchItemUpdates(m, &marketplaceServiceWatchItemUpdatesServer{ServerStream: stream})
+}
+
+type MarketplaceService_WatchItemUpdatesServer interface {
+	Send(*ItemUpdate) error
+	grpc.ServerStream
+}
+
+type marketplaceServiceWatchItemUpdatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *marketplaceServiceWatchItemUpdatesServer) Send(m *ItemUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// MarketplaceService_ServiceDesc is the grpc.ServiceDesc for MarketplaceService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MarketplaceService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "marketplace.v1.MarketplaceService",
+	HandlerType: (*MarketplaceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetItem",
+			Handler:    _MarketplaceService_GetItem_Handler,
+		},
+		{
+			MethodName: "ListItem",
+			Handler:    _MarketplaceService_ListItem_Handler,
+		},
+		{
+			MethodName: "PurchaseItem",
+			Handler:    _MarketplaceService_PurchaseItem_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchItemUpdates",
+			Handler:       _MarketplaceService_WatchItemUpdates_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/marketplace/v1/marketplace.proto",
+}
@@ -0,0 +1,344 @@
+// Command abigen generates typed Go wrappers around the Firefly client for a contract ABI.
+//
+// Usage (see the go:generate directive in internal/infra/firefly):
+//
+//	go run backend/cmd/abigen -abi contracts/Marketplace.abi -type Marketplace -api marketplace -pkg firefly -out marketplace_gen.go
+package main
+
+import (
+	"backend/contracts"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+func main() {
+	abiPath := flag.String("abi", "", "path to the contract ABI JSON")
+	typeName := flag.String("type", "", "Go type name of the generated binding, e.g. Marketplace")
+	apiName := flag.String("api", "", "name of the Firefly contract API, e.g. marketplace")
+	pkg := flag.String("pkg", "firefly", "package name of the generated file")
+	out := flag.String("out", "", "output file")
+	flag.Parse()
+
+	if *abiPath == "" || *typeName == "" || *apiName == "" || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	raw, err := os.ReadFile(*abiPath)
+	if err != nil {
+		log.Fatalf("Failed to read ABI (%s): %s", *abiPath, err.Error())
+	}
+	abi, err := contracts.ParseABI(raw)
+	if err != nil {
+		log.Fatalf("Failed to parse ABI (%s): %s", *abiPath, err.Error())
+	}
+
+	src, err := generate(abi, *typeName, *apiName, *pkg)
+	if err != nil {
+		log.Fatalf("Failed to generate binding: %s", err.Error())
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatalf("Failed to write (%s): %s", *out, err.Error())
+	}
+}
+
+type param struct {
+	ABIName string
+	Name    string
+	Field   string
+	GoType  string
+}
+
+type method struct {
+	ABIName string
+	Name    string
+	Query   bool
+	Inputs  []param
+	Outputs []param
+	// Results is the result list of the generated function
+	Results string
+	// Zero is the list of zero values returned alongside an error, including the trailing comma
+	Zero string
+}
+
+type event struct {
+	ABIName string
+	Name    string
+	Fields  []param
+}
+
+type templateData struct {
+	Package  string
+	Type     string
+	API      string
+	Methods  []method
+	Events   []event
+	NeedsBig bool
+}
+
+func generate(abi *contracts.ABI, typeName, apiName, pkg string) ([]byte, error) {
+	data := templateData{
+		Package: pkg,
+		Type:    typeName,
+		API:     apiName,
+	}
+
+	names := make([]string, 0, len(abi.Methods))
+	for name := range abi.Methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		m := abi.Methods[name]
+		gm := method{
+			ABIName: m.Name,
+			Name:    exported(m.Name),
+			Query:   m.IsQuery(),
+		}
+		for i, arg := range m.Inputs {
+			p, err := toParam(arg, i, "arg")
+			if err != nil {
+				return nil, fmt.Errorf("method %s: %w", m.Name, err)
+			}
+			gm.Inputs = append(gm.Inputs, p)
+		}
+		for i, arg := range m.Outputs {
+			p, err := toParam(arg, i, "output")
+			if err != nil {
+				return nil, fmt.Errorf("method %s: %w", m.Name, err)
+			}
+			gm.Outputs = append(gm.Outputs, p)
+		}
+		gm.Results, gm.Zero = results(typeName, gm)
+		data.Methods = append(data.Methods, gm)
+	}
+
+	names = names[:0]
+	for name := range abi.Events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		e := abi.Events[name]
+		ge := event{
+			ABIName: e.Name,
+			Name:    exported(e.Name),
+		}
+		for i, arg := range e.Inputs {
+			p, err := toParam(arg, i, "output")
+			if err != nil {
+				return nil, fmt.Errorf("event %s: %w", e.Name, err)
+			}
+			ge.Fields = append(ge.Fields, p)
+		}
+		data.Events = append(data.Events, ge)
+	}
+
+	for _, m := range data.Methods {
+		for _, p := range append(m.Inputs, m.Outputs...) {
+			data.NeedsBig = data.NeedsBig || p.GoType == "*big.Int"
+		}
+	}
+	for _, e := range data.Events {
+		for _, p := range e.Fields {
+			data.NeedsBig = data.NeedsBig || p.GoType == "*big.Int"
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := bindingTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("bindingTemplate.Execute: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format.Source: %w\n%s", err, buf.String())
+	}
+	return src, nil
+}
+
+func toParam(arg contracts.Argument, i int, fallback string) (param, error) {
+	goType, err := goType(arg.Type)
+	if err != nil {
+		return param{}, err
+	}
+	name := strings.TrimLeft(arg.Name, "_")
+	if name == "" {
+		name = fmt.Sprintf("%s%d", fallback, i)
+	}
+	local := unexported(name)
+	if token.IsKeyword(local) || local == "ctx" || local == "location" || local == "input" {
+		local += "_"
+	}
+	return param{
+		ABIName: arg.Name,
+		Name:    local,
+		Field:   exported(name),
+		GoType:  goType,
+	}, nil
+}
+
+func results(typeName string, m method) (string, string) {
+	switch {
+	case !m.Query:
+		return "(string, error)", `"", `
+	case len(m.Outputs) == 0:
+		return "error", ""
+	case len(m.Outputs) == 1:
+		t := m.Outputs[0].GoType
+		return fmt.Sprintf("(%s, error)", t), zeroValue(t) + ", "
+	}
+	return fmt.Sprintf("(*%s%sOutput, error)", typeName, m.Name), "nil, "
+}
+
+func zeroValue(goType string) string {
+	switch goType {
+	case "bool":
+		return "false"
+	case "string":
+		return `""`
+	case "contracts.Address":
+		return "contracts.Address{}"
+	}
+	return "nil"
+}
+
+func goType(solidityType string) (string, error) {
+	switch {
+	case solidityType == "address" || solidityType == "address payable":
+		return "contracts.Address", nil
+	case solidityType == "bool":
+		return "bool", nil
+	case solidityType == "string":
+		return "string", nil
+	case strings.HasPrefix(solidityType, "bytes"):
+		return "[]byte", nil
+	case strings.HasPrefix(solidityType, "uint"), strings.HasPrefix(solidityType, "int"):
+		return "*big.Int", nil
+	}
+	return "", fmt.Errorf("unsupported type (%s)", solidityType)
+}
+
+func exported(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func unexported(s string) string {
+	r := []rune(s)
+	// Keep acronyms such as NFT intact as a unit: NFTId -> nftId
+	i := 0
+	for i < len(r) && unicode.IsUpper(r[i]) {
+		if i > 0 && i+1 < len(r) && unicode.IsLower(r[i+1]) {
+			break
+		}
+		r[i] = unicode.ToLower(r[i])
+		i++
+	}
+	return string(r)
+}
+
+var bindingTemplate = template.Must(template.New("binding").Parse(`// Code generated by abigen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"backend/contracts"
+	"context"
+	"fmt"
+{{- if .NeedsBig}}
+	"math/big"
+{{- end}}
+)
+
+// {{.Type}} exposes every {{.Type}} contract function through the "{{.API}}" Firefly contract API.
+// Functions that change state are invoked as transactions and return the Firefly transaction ID,
+// read-only functions are queried and return their decoded outputs.
+type {{.Type}} struct {
+	c *Client
+}
+
+func (c *Client) {{.Type}}() *{{.Type}} {
+	return &{{.Type}}{c: c}
+}
+
+func {{.Type | printf "%sABI"}}() *contracts.ABI {
+	return contracts.Get{{.Type}}()
+}
+{{range $m := .Methods}}
+{{- if gt (len $m.Outputs) 1}}{{if $m.Query}}
+type {{$.Type}}{{$m.Name}}Output struct {
+{{- range $m.Outputs}}
+	{{.Field}} {{.GoType}}
+{{- end}}
+}
+{{end}}{{end}}
+// {{$m.Name}} {{if $m.Query}}queries{{else}}invokes{{end}} {{$m.ABIName}} on the contract deployed at location
+func (m *{{$.Type}}) {{$m.Name}}(ctx context.Context, location string{{range $m.Inputs}}, {{.Name}} {{.GoType}}{{end}}) {{$m.Results}} {
+	method := {{$.Type}}ABI().Methods["{{$m.ABIName}}"]
+	input, err := method.Inputs.PackNamed({{range $i, $p := $m.Inputs}}{{if $i}}, {{end}}{{$p.Name}}{{end}})
+	if err != nil {
+		return {{$m.Zero}}fmt.Errorf("{{$m.ABIName}} inputs: %w", err)
+	}
+{{- if not $m.Query}}
+	tx, err := m.c.invokeContract(ctx, "{{$.API}}", "{{$m.ABIName}}", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+{{- else if eq (len $m.Outputs) 0}}
+	if _, err := m.c.queryContract(ctx, "{{$.API}}", "{{$m.ABIName}}", location, input); err != nil {
+		return fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	return nil
+{{- else}}
+	output, err := m.c.queryContract(ctx, "{{$.API}}", "{{$m.ABIName}}", location, input)
+	if err != nil {
+		return {{$m.Zero}}fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return {{$m.Zero}}fmt.Errorf("{{$m.ABIName}} outputs: %w", err)
+	}
+{{- if eq (len $m.Outputs) 1}}
+	return values[0].({{(index $m.Outputs 0).GoType}}), nil
+{{- else}}
+	return &{{$.Type}}{{$m.Name}}Output{
+{{- range $i, $p := $m.Outputs}}
+		{{$p.Field}}: values[{{$i}}].({{$p.GoType}}),
+{{- end}}
+	}, nil
+{{- end}}
+{{- end}}
+}
+{{end}}
+{{- range $e := .Events}}
+// {{$.Type}}{{$e.Name}}Event is the decoded {{$e.ABIName}} event
+type {{$.Type}}{{$e.Name}}Event struct {
+{{- range $e.Fields}}
+	{{.Field}} {{.GoType}}
+{{- end}}
+}
+
+// Decode{{$.Type}}{{$e.Name}}Event decodes the output of a Firefly blockchain event for {{$e.ABIName}}
+func Decode{{$.Type}}{{$e.Name}}Event(output map[string]any) (*{{$.Type}}{{$e.Name}}Event, error) {
+	values, err := {{$.Type}}ABI().Events["{{$e.ABIName}}"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("{{$e.ABIName}}: %w", err)
+	}
+	return &{{$.Type}}{{$e.Name}}Event{
+{{- range $i, $p := $e.Fields}}
+		{{$p.Field}}: values[{{$i}}].({{$p.GoType}}),
+{{- end}}
+	}, nil
+}
+{{end}}`))
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
@@ -13,12 +14,106 @@ type Config struct {
 	FireflyBaseUrlUserTwo   string `envconfig:"FIREFLY_BASE_URL_USER_TWO" required:"true"`
 	FireflyBaseUrlUserThree string `envconfig:"FIREFLY_BASE_URL_USER_THREE" required:"true"`
 	MysqlPassword           string `envconfig:"MYSQL_PASSWORD" required:"true"`
+	// MarketplaceMode is either "per_listing", deploying one contract per listing,
+	// or "shared", sending every listing to the contract at SharedMarketplaceAddress
+	MarketplaceMode          string `envconfig:"MARKETPLACE_MODE" default:"per_listing"`
+	SharedMarketplaceAddress string `envconfig:"SHARED_MARKETPLACE_ADDRESS"`
+	// TokenPools are the names of the NFT pools to bootstrap, the first one is the default
+	TokenPools []string `envconfig:"TOKEN_POOLS" default:"kaleido"`
+	// PaymentPool is the fungible pool purchases are paid in, required by the shared marketplace contract
+	PaymentPool string `envconfig:"PAYMENT_POOL"`
+	// FeeSchedule is percentage:<basis points>, flat:<currency>=<amount>,... or tiered:<currency>:<up to>/...=<basis points>,...,*=<basis points>
+	FeeSchedule string `envconfig:"FEE_SCHEDULE" default:"percentage:0"`
+	// RoyaltyBasisPoints of the price go to the creator when someone else resells their item
+	RoyaltyBasisPoints int64 `envconfig:"ROYALTY_BPS" default:"0"`
+	// PriceChangeInterval is the minimum time between two price changes of a listing
+	PriceChangeInterval time.Duration `envconfig:"PRICE_CHANGE_INTERVAL" default:"10m"`
+	// AdminUserID arbitrates disputes. In shared mode, their signing key must be the contract's arbiter.
+	AdminUserID string `envconfig:"ADMIN_USER_ID"`
+	// AddressEncryptionKey is the base64 encoded AES-256 key shipping addresses are encrypted with
+	AddressEncryptionKey string `envconfig:"ADDRESS_ENCRYPTION_KEY" required:"true"`
+	// Carriers are the carrier adapters sellers can record shipments with
+	Carriers []string `envconfig:"CARRIERS" default:"fake"`
+	// DeliverySettleAfter is how long after the carrier reports delivery the buyer has to confirm or dispute,
+	// before the sale settles on its own
+	DeliverySettleAfter time.Duration `envconfig:"DELIVERY_SETTLE_AFTER" default:"72h"`
+	// DisputeWindow is how long after receipt the buyer can still dispute. The shared contract must have been
+	// deployed with it, in seconds.
+	DisputeWindow time.Duration `envconfig:"DISPUTE_WINDOW" default:"168h"`
+	// ProvenanceSigningKey is the base64 encoded 32 byte Ed25519 seed provenance exports are signed with
+	ProvenanceSigningKey string `envconfig:"PROVENANCE_SIGNING_KEY" required:"true"`
+	// VerifyRateLimit is how many verifications a client address can request per VerifyRateWindow
+	VerifyRateLimit  int           `envconfig:"VERIFY_RATE_LIMIT" default:"30"`
+	VerifyRateWindow time.Duration `envconfig:"VERIFY_RATE_WINDOW" default:"1m"`
+	// EstimateHalfLife is the age at which a past sale counts half as much towards a price estimate
+	EstimateHalfLife time.Duration `envconfig:"ESTIMATE_HALF_LIFE" default:"2160h"`
+	// ReputationHalfLife is the age at which a review counts half as much towards a seller's reputation
+	ReputationHalfLife time.Duration `envconfig:"REPUTATION_HALF_LIFE" default:"4320h"`
+	// MediaStorage is the blob storage adapter uploaded images are kept in
+	MediaStorage string `envconfig:"MEDIA_STORAGE" default:"local"`
+	// MediaDir is the directory the local adapter keeps images in
+	MediaDir string `envconfig:"MEDIA_DIR" default:"media"`
+	// MediaMaxSize is the most bytes an uploaded image can have
+	MediaMaxSize int64 `envconfig:"MEDIA_MAX_SIZE" default:"10485760"`
+	// Notifiers are the channels users can get their alert digests through. log is the default channel and is required.
+	Notifiers []string `envconfig:"NOTIFIERS" default:"log,webhook,email"`
+	// SMTPAddr is the host:port of the SMTP relay the email notifier sends through
+	SMTPAddr string `envconfig:"SMTP_ADDR" default:"localhost:2525"`
+	// SMTPFrom is the sender of alert emails
+	SMTPFrom string `envconfig:"SMTP_FROM" default:"alerts@marketplace.local"`
+	// SMTPFake runs a local SMTP server on SMTPAddr that logs alert emails instead of delivering them
+	SMTPFake bool `envconfig:"SMTP_FAKE" default:"true"`
+	// ImportMaxRows is the most rows a bulk listing import can have
+	ImportMaxRows int `envconfig:"IMPORT_MAX_ROWS" default:"1000"`
+	// ImportConcurrency is how many rows of an import are listed at once
+	ImportConcurrency int `envconfig:"IMPORT_CONCURRENCY" default:"4"`
+	// FakeCarrierDeliveryDelay is how long the fake carrier keeps a parcel in transit
+	FakeCarrierDeliveryDelay time.Duration `envconfig:"FAKE_CARRIER_DELIVERY_DELAY" default:"5m"`
 }
 
+const (
+	MarketplaceModePerListing = "per_listing"
+	MarketplaceModeShared     = "shared"
+)
+
 func NewConfig() (*Config, error) {
 	var c Config
 	if err := envconfig.Process("", &c); err != nil {
 		return nil, fmt.Errorf("envconfig.Process: %w", err)
 	}
+	switch c.MarketplaceMode {
+	case MarketplaceModePerListing:
+		// The per-listing Marketplace contract transfers the NFT on buyNFT and cannot hold a payment in escrow
+		if len(c.PaymentPool) > 0 {
+			return nil, fmt.Errorf("PAYMENT_POOL requires MARKETPLACE_MODE %s", MarketplaceModeShared)
+		}
+	case MarketplaceModeShared:
+		if len(c.SharedMarketplaceAddress) == 0 {
+			return nil, fmt.Errorf("SHARED_MARKETPLACE_ADDRESS is required when MARKETPLACE_MODE is %s", MarketplaceModeShared)
+		}
+		if len(c.PaymentPool) == 0 {
+			return nil, fmt.Errorf("PAYMENT_POOL is required when MARKETPLACE_MODE is %s", MarketplaceModeShared)
+		}
+	default:
+		return nil, fmt.Errorf("unknown MARKETPLACE_MODE (%s)", c.MarketplaceMode)
+	}
+	if c.VerifyRateLimit < 1 || c.VerifyRateWindow <= 0 {
+		return nil, fmt.Errorf("VERIFY_RATE_LIMIT and VERIFY_RATE_WINDOW must be positive")
+	}
+	if c.EstimateHalfLife <= 0 {
+		return nil, fmt.Errorf("ESTIMATE_HALF_LIFE must be positive")
+	}
+	if c.ReputationHalfLife <= 0 {
+		return nil, fmt.Errorf("REPUTATION_HALF_LIFE must be positive")
+	}
+	if c.MediaMaxSize <= 0 {
+		return nil, fmt.Errorf("MEDIA_MAX_SIZE must be positive")
+	}
+	if len(c.SMTPAddr) == 0 || len(c.SMTPFrom) == 0 {
+		return nil, fmt.Errorf("SMTP_ADDR and SMTP_FROM are required")
+	}
+	if c.ImportMaxRows < 1 || c.ImportConcurrency < 1 {
+		return nil, fmt.Errorf("IMPORT_MAX_ROWS and IMPORT_CONCURRENCY must be positive")
+	}
 	return &c, nil
 }
@@ -1,17 +1,43 @@
 package main
 
 import (
+	marketplacev1 "backend/api/marketplace/v1"
 	"backend/cmd/server/config"
-	_ "backend/contracts"
+	"backend/contracts"
+	"backend/internal/domain"
+	"backend/internal/infra/blob"
+	"backend/internal/infra/carrier"
+	"backend/internal/infra/encrypt"
 	"backend/internal/infra/firefly"
 	"backend/internal/infra/mysql"
+	"backend/internal/infra/notify"
+	"backend/internal/infra/publichttp"
+	"backend/internal/infra/sign"
 	"backend/internal/middleware"
+	"backend/internal/service/alert"
+	"backend/internal/service/dispute"
+	"backend/internal/service/estimate"
+	"backend/internal/service/event"
+	"backend/internal/service/fee"
+	"backend/internal/service/importer"
 	"backend/internal/service/item"
+	"backend/internal/service/media"
+	"backend/internal/service/offer"
+	"backend/internal/service/pool"
+	"backend/internal/service/provenance"
+	"backend/internal/service/review"
+	"backend/internal/service/shipment"
+	"backend/internal/service/verify"
+	"backend/internal/service/webhook"
+	grpc2 "backend/internal/transport/grpc"
 	http2 "backend/internal/transport/http"
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -20,6 +46,7 @@ import (
 
 	mysql2 "github.com/go-sql-driver/mysql"
 	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -38,6 +65,12 @@ func main() {
 }
 
 func Run(cfg *config.Config) int {
+	log.Println("Verifying embedded contract artifacts...")
+	if err := contracts.Verify(); err != nil {
+		log.Fatalf("Embedded contract artifacts are invalid: %s", err.Error())
+		return exitError
+	}
+
 	log.Println("Setting up DB...")
 	jst, err := time.LoadLocation("Asia/Tokyo")
 	if err != nil {
@@ -53,6 +86,8 @@ func Run(cfg *config.Config) int {
 		ParseTime: true,
 		Collation: "utf8mb4_unicode_ci",
 		Loc:       jst,
+		// Rows affected counts matched rows, so that an update that changes nothing still confirms the row's state
+		ClientFoundRows: true,
 	}
 	db, err := sql.Open("mysql", cc.FormatDSN())
 	if err != nil {
@@ -79,28 +114,223 @@ func Run(cfg *config.Config) int {
 		return exitError
 	}
 	fireflyClient := firefly.New(httpUrl1, httpUrl2, httpUrl3, httpClient)
-	itemService := item.New(fireflyClient, dbClient)
-	httpServer := http2.New(itemService)
+	webhookService := webhook.New(dbClient, publichttp.New(time.Second*10))
+	notifiers := make(map[string]alert.Notifier, len(cfg.Notifiers))
+	for _, name := range cfg.Notifiers {
+		switch name {
+		case notify.LogName:
+			notifiers[name] = notify.NewLog()
+		case notify.WebhookName:
+			notifiers[name] = notify.NewWebhook(time.Second * 10)
+		case notify.EmailName:
+			notifiers[name] = notify.NewEmail(cfg.SMTPAddr, cfg.SMTPFrom)
+		default:
+			log.Fatalf("Unknown notifier (%s) in NOTIFIERS", name)
+			return exitError
+		}
+	}
+	if _, ok := notifiers[domain.DefaultAlertChannel]; !ok {
+		log.Fatalf("NOTIFIERS must include the default channel (%s)", domain.DefaultAlertChannel)
+		return exitError
+	}
+	alertService := alert.New(dbClient, notifiers)
+	eventBroker := event.NewBroker(webhookService, alertService)
+	feeSchedule, err := fee.ParseSchedule(cfg.FeeSchedule)
+	if err != nil {
+		log.Fatalf("Failed to parse FEE_SCHEDULE: %s", err.Error())
+		return exitError
+	}
+	feeEngine, err := fee.New(feeSchedule, cfg.RoyaltyBasisPoints)
+	if err != nil {
+		log.Fatalf("Failed to set up fee engine: %s", err.Error())
+		return exitError
+	}
+	provenanceSeed, err := base64.StdEncoding.DecodeString(cfg.ProvenanceSigningKey)
+	if err != nil {
+		log.Fatalf("Failed to decode PROVENANCE_SIGNING_KEY: %s", err.Error())
+		return exitError
+	}
+	provenanceSigner, err := sign.New(provenanceSeed)
+	if err != nil {
+		log.Fatalf("Failed to set up PROVENANCE_SIGNING_KEY: %s", err.Error())
+		return exitError
+	}
+	var itemService *item.Service
+	var offerService *offer.Service
+	var provenanceService *provenance.Service
+	var sharedMarket *firefly.SharedMarket
+	if cfg.MarketplaceMode == config.MarketplaceModeShared {
+		log.Printf("Using shared marketplace contract at %s", cfg.SharedMarketplaceAddress)
+		sharedMarket = firefly.NewSharedMarket(fireflyClient, cfg.SharedMarketplaceAddress, cfg.PaymentPool, cfg.DisputeWindow)
+		if err := registerSharedMarket(context.Background(), dbClient, sharedMarket.Deployment()); err != nil {
+			log.Fatalf("Failed to register shared marketplace contract: %s", err.Error())
+			return exitError
+		}
+		itemService = item.New(sharedMarket, fireflyClient, dbClient, eventBroker, feeEngine, cfg.PriceChangeInterval)
+		offerService = offer.New(dbClient, itemService, sharedMarket, eventBroker)
+		provenanceService = provenance.New(dbClient, fireflyClient, sharedMarket, provenanceSigner)
+	} else {
+		perListingMarket := firefly.NewPerListingMarket(fireflyClient)
+		itemService = item.New(perListingMarket, fireflyClient, dbClient, eventBroker, feeEngine, cfg.PriceChangeInterval)
+		offerService = offer.New(dbClient, itemService, perListingMarket, eventBroker)
+		provenanceService = provenance.New(dbClient, fireflyClient, perListingMarket, provenanceSigner)
+	}
+	addressKey, err := base64.StdEncoding.DecodeString(cfg.AddressEncryptionKey)
+	if err != nil {
+		log.Fatalf("Failed to decode ADDRESS_ENCRYPTION_KEY: %s", err.Error())
+		return exitError
+	}
+	addressSealer, err := encrypt.New(addressKey)
+	if err != nil {
+		log.Fatalf("Failed to set up ADDRESS_ENCRYPTION_KEY: %s", err.Error())
+		return exitError
+	}
+	carriers := make(map[string]shipment.Carrier, len(cfg.Carriers))
+	for _, name := range cfg.Carriers {
+		switch name {
+		case carrier.FakeName:
+			carriers[name] = carrier.NewFake(cfg.FakeCarrierDeliveryDelay)
+		default:
+			log.Fatalf("Unknown carrier (%s) in CARRIERS", name)
+			return exitError
+		}
+	}
+	shipmentService := shipment.New(dbClient, itemService, carriers, addressSealer, eventBroker, cfg.DeliverySettleAfter)
+	var mediaStorage media.Storage
+	switch cfg.MediaStorage {
+	case blob.LocalName:
+		if mediaStorage, err = blob.NewLocal(cfg.MediaDir); err != nil {
+			log.Fatalf("Failed to set up MEDIA_DIR: %s", err.Error())
+			return exitError
+		}
+	default:
+		log.Fatalf("Unknown MEDIA_STORAGE (%s)", cfg.MediaStorage)
+		return exitError
+	}
+	importService := importer.New(dbClient, itemService, fireflyClient, cfg.ImportMaxRows, cfg.ImportConcurrency)
+	disputeService := dispute.New(dbClient, itemService, eventBroker, cfg.AdminUserID, cfg.DisputeWindow)
+	httpServer := http2.New(itemService, webhookService, offerService, shipmentService, disputeService, provenanceService, verify.New(dbClient, fireflyClient), estimate.New(dbClient, cfg.EstimateHalfLife), media.New(dbClient, mediaStorage, cfg.MediaMaxSize), review.New(dbClient, fireflyClient, cfg.ReputationHalfLife), alertService, importService)
+	grpcServer := grpc2.New(itemService, eventBroker)
+
+	log.Println("Bootstrapping NFT pools...")
+	if err := pool.New(fireflyClient, dbClient).Bootstrap(context.Background(), cfg.TokenPools, cfg.PaymentPool); err != nil {
+		log.Fatalf("Failed to bootstrap NFT pools via Firefly: %s", err.Error())
+		return exitError
+	}
 
-	log.Println("Creating NFT pool...")
-	if err := fireflyClient.CreatePool(context.Background()); err != nil {
-		log.Fatalf("Failed to create pool for NFT via Firefly: %s", err.Error())
+	log.Println("Registering Firefly contract API...")
+	contractAPI := firefly.ContractAPI{Name: "marketplace", ABI: contracts.GetMarketplace()}
+	if cfg.MarketplaceMode == config.MarketplaceModeShared {
+		contractAPI = firefly.ContractAPI{Name: "sharedmarketplace", ABI: contracts.GetSharedMarketplace(), Location: cfg.SharedMarketplaceAddress}
+	}
+	if err := fireflyClient.EnsureContractAPI(context.Background(), contractAPI); err != nil {
+		log.Fatalf("Failed to register Firefly contract API: %s", err.Error())
 		return exitError
 	}
+	if sharedMarket != nil {
+		log.Println("Registering Firefly contract listeners...")
+		if err := sharedMarket.EnsureListeners(context.Background()); err != nil {
+			log.Fatalf("Failed to register Firefly contract listeners: %s", err.Error())
+			return exitError
+		}
+		if err := sharedMarket.CheckDisputeWindow(context.Background()); err != nil {
+			log.Fatalf("Failed to check DISPUTE_WINDOW: %s", err.Error())
+			return exitError
+		}
+	}
 
 	log.Println("Setting up HTTP server...")
-	r := mux.NewRouter()
+	router := mux.NewRouter()
+	// Verification is for anyone holding an item, so it is rate limited instead of requiring a user
+	router.Handle("/v1/verify", middleware.RateLimit(cfg.VerifyRateLimit, cfg.VerifyRateWindow)(http.HandlerFunc(httpServer.Verify))).Methods("GET")
+	// Images are referenced by hash from listings and NFT metadata, which anyone can look at
+	router.HandleFunc("/v1/media/{hash}", httpServer.GetMedia).Methods("GET")
+	r := router.NewRoute().Subrouter()
 	r.Use(middleware.SetUserID)
 	r.HandleFunc("/items/list", httpServer.ListItem).Methods("POST")
 	r.HandleFunc("/items/buy", httpServer.PurchaseItem).Methods("POST")
 	r.HandleFunc("/items/get", httpServer.GetItem).Methods("GET")
+	r.HandleFunc("/v1/categories", httpServer.ListCategories).Methods("GET")
+	r.HandleFunc("/v1/media", httpServer.UploadMedia).Methods("POST")
+	// Registered before the /v1/items/{id} routes, which would otherwise take "import" as an item ID
+	r.HandleFunc("/v1/items/import", httpServer.ImportItems).Methods("POST")
+	r.HandleFunc("/v1/items/import", httpServer.ListImports).Methods("GET")
+	r.HandleFunc("/v1/items/import/{id}", httpServer.GetImport).Methods("GET")
+	r.HandleFunc("/v1/items/import/{id}/retry", httpServer.RetryImport).Methods("POST")
+	r.HandleFunc("/v1/items/{id}/contract", httpServer.GetItemContract).Methods("GET")
+	r.HandleFunc("/v1/items/{id}/ship", httpServer.ShipItem).Methods("POST")
+	r.HandleFunc("/v1/items/{id}/receive", httpServer.ReceiveItem).Methods("POST")
+	r.HandleFunc("/v1/items/{id}/cancel", httpServer.CancelItem).Methods("POST")
+	r.HandleFunc("/v1/items/{id}/listing", httpServer.DelistItem).Methods("DELETE")
+	// Per-listing contracts fix the price at deployment, so only the shared contract can change it
+	if cfg.MarketplaceMode == config.MarketplaceModeShared {
+		r.HandleFunc("/v1/items/{id}/price", httpServer.UpdatePrice).Methods("PATCH")
+	} else {
+		log.Println("Price changes are not served in per-listing mode, items must be listed again at the new price")
+	}
+	r.HandleFunc("/v1/items/{id}/price/history", httpServer.ListPriceChanges).Methods("GET")
+	r.HandleFunc("/v1/items/{id}/estimate", httpServer.GetEstimate).Methods("GET")
+	r.HandleFunc("/v1/items/{id}/offers", httpServer.MakeOffer).Methods("POST")
+	r.HandleFunc("/v1/items/{id}/offers", httpServer.ListOffers).Methods("GET")
+	r.HandleFunc("/v1/items/{id}/shipping-address", httpServer.SetShippingAddress).Methods("PUT")
+	r.HandleFunc("/v1/items/{id}/shipping-address", httpServer.GetShippingAddress).Methods("GET")
+	r.HandleFunc("/v1/items/{id}/shipment", httpServer.RecordShipment).Methods("POST")
+	r.HandleFunc("/v1/items/{id}/shipment", httpServer.GetShipment).Methods("GET")
+	r.HandleFunc("/v1/items/{id}/shipment/confirm", httpServer.ConfirmDelivery).Methods("POST")
+	r.HandleFunc("/v1/items/{id}/disputes", httpServer.OpenDispute).Methods("POST")
+	r.HandleFunc("/v1/items/{id}/reviews", httpServer.CreateReview).Methods("POST")
+	r.HandleFunc("/v1/reviews/{id}", httpServer.GetReview).Methods("GET")
+	r.HandleFunc("/v1/sellers/{id}/reviews", httpServer.ListSellerReviews).Methods("GET")
+	r.HandleFunc("/v1/sellers/{id}/reputation", httpServer.GetSellerReputation).Methods("GET")
+	r.HandleFunc("/v1/searches", httpServer.CreateSavedSearch).Methods("POST")
+	r.HandleFunc("/v1/searches", httpServer.ListSavedSearches).Methods("GET")
+	r.HandleFunc("/v1/searches/{id}", httpServer.DeleteSavedSearch).Methods("DELETE")
+	r.HandleFunc("/v1/watchlist", httpServer.ListWatchlist).Methods("GET")
+	r.HandleFunc("/v1/watchlist/{item_id}", httpServer.WatchItem).Methods("PUT")
+	r.HandleFunc("/v1/watchlist/{item_id}", httpServer.UnwatchItem).Methods("DELETE")
+	r.HandleFunc("/v1/alerts/settings", httpServer.GetAlertSettings).Methods("GET")
+	r.HandleFunc("/v1/alerts/settings", httpServer.UpdateAlertSettings).Methods("PUT")
+	r.HandleFunc("/v1/notifications", httpServer.ListNotifications).Methods("GET")
+	r.HandleFunc("/v1/disputes", httpServer.ListDisputes).Methods("GET")
+	r.HandleFunc("/v1/disputes/{id}", httpServer.GetDispute).Methods("GET")
+	r.HandleFunc("/v1/disputes/{id}/respond", httpServer.RespondToDispute).Methods("POST")
+	r.HandleFunc("/v1/disputes/{id}/resolve", httpServer.ResolveDispute).Methods("POST")
+	r.HandleFunc("/v1/nfts/{id}/provenance", httpServer.GetProvenance).Methods("GET")
+	r.HandleFunc("/v1/nfts/{id}/provenance/export", httpServer.ExportProvenance).Methods("GET")
+	r.HandleFunc("/v1/provenance/public-key", httpServer.GetProvenancePublicKey).Methods("GET")
+	r.HandleFunc("/v1/offers/{id}/counter", httpServer.CounterOffer).Methods("POST")
+	r.HandleFunc("/v1/offers/{id}/accept", httpServer.AcceptOffer).Methods("POST")
+	r.HandleFunc("/v1/offers/{id}/reject", httpServer.RejectOffer).Methods("POST")
+	r.HandleFunc("/v1/webhooks", httpServer.CreateWebhookSubscription).Methods("POST")
+	r.HandleFunc("/v1/webhooks", httpServer.ListWebhookSubscriptions).Methods("GET")
+	r.HandleFunc("/v1/webhooks/{id}", httpServer.DeleteWebhookSubscription).Methods("DELETE")
+	r.HandleFunc("/v1/webhooks/{id}/deliveries", httpServer.ListWebhookDeliveries).Methods("GET")
+	r.HandleFunc("/v1/webhooks/deliveries/{id}/redeliver", httpServer.RedeliverWebhook).Methods("POST")
 
 	srv := &http.Server{
 		Addr:         "0.0.0.0:8080",
 		WriteTimeout: time.Second * 15,
 		ReadTimeout:  time.Second * 15,
 		IdleTimeout:  time.Second * 60,
-		Handler:      r,
+		Handler:      router,
+	}
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go webhookService.Run(workerCtx)
+	go offerService.Run(workerCtx)
+	go shipmentService.Run(workerCtx)
+	go disputeService.Run(workerCtx)
+	go alertService.Run(workerCtx)
+	go importService.Run(workerCtx)
+	if cfg.SMTPFake {
+		smtpFake, err := notify.NewSMTPFake(cfg.SMTPAddr)
+		if err != nil {
+			log.Fatalf("Failed to start the SMTP fake: %s", err.Error())
+			return exitError
+		}
+		log.Printf("Logging alert emails with the SMTP fake on %s", smtpFake.Addr())
+		go smtpFake.Run(workerCtx)
 	}
 
 	go func() {
@@ -109,6 +339,23 @@ func Run(cfg *config.Config) int {
 		}
 	}()
 
+	log.Println("Setting up gRPC server...")
+	gs := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(middleware.UnaryUserID),
+		grpc.ChainStreamInterceptor(middleware.StreamUserID),
+	)
+	marketplacev1.RegisterMarketplaceServiceServer(gs, grpcServer)
+	lis, err := net.Listen("tcp", "0.0.0.0:9090")
+	if err != nil {
+		log.Fatalf("Failed to listen for gRPC server: %s", err.Error())
+		return exitError
+	}
+	go func() {
+		if err := gs.Serve(lis); err != nil {
+			log.Printf("Failed to serve gRPC server: %s", err.Error())
+		}
+	}()
+
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
 	<-c
@@ -117,6 +364,30 @@ func Run(cfg *config.Config) int {
 	defer cancel()
 
 	srv.Shutdown(ctx)
+	// Closing the broker ends open WatchItemUpdates streams, letting GracefulStop return
+	eventBroker.Close()
+	gs.GracefulStop()
 	log.Println("Gracefully shutting down...")
 	return exitOK
 }
+
+// registerSharedMarket records the shared contract in the contract registry. A record of its address with
+// another ABI or bytecode means the embedded artifact no longer describes the deployed contract, so the
+// server refuses to start rather than call it through the wrong interface.
+func registerSharedMarket(ctx context.Context, dbClient *mysql.Client, d *domain.ContractDeployment) error {
+	registered, err := dbClient.GetContractDeploymentByAddress(ctx, d.Address)
+	if errors.Is(err, domain.ErrNotFound) {
+		if err := dbClient.CreateContractDeployment(ctx, d); err != nil {
+			return fmt.Errorf("dbClient.CreateContractDeployment: %w", err)
+		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("dbClient.GetContractDeploymentByAddress: %w", err)
+	}
+	if registered.ContractName != d.ContractName || registered.ABIHash != d.ABIHash || registered.CodeHash != d.CodeHash {
+		return fmt.Errorf("contract (%s) is registered as %s with ABI hash %s and code hash %s, but the embedded %s has %s and %s; "+
+			"deploy marketplace_full.sol again and set SHARED_MARKETPLACE_ADDRESS to it",
+			d.Address, registered.ContractName, registered.ABIHash, registered.CodeHash, d.ContractName, d.ABIHash, d.CodeHash)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Argument is a single typed input or output of a method, constructor or event
+type Argument struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	InternalType string `json:"internalType"`
+	Indexed      bool   `json:"indexed,omitempty"`
+}
+
+type Arguments []Argument
+
+type Method struct {
+	Name            string
+	Inputs          Arguments
+	Outputs         Arguments
+	StateMutability string
+}
+
+// IsQuery reports whether the method only reads state, which decides
+// whether Firefly should query it or send a transaction to invoke it
+func (m *Method) IsQuery() bool {
+	return m.StateMutability == "view" || m.StateMutability == "pure"
+}
+
+// Sig returns the canonical signature, e.g. buyNFT()
+func (m *Method) Sig() string {
+	return m.Name + m.Inputs.sig()
+}
+
+// Selector returns the 4 byte function selector that prefixes call data
+func (m *Method) Selector() []byte {
+	return keccak256([]byte(m.Sig()))[:4]
+}
+
+type Event struct {
+	Name      string
+	Inputs    Arguments
+	Anonymous bool
+}
+
+// Sig returns the canonical signature, e.g. NFTListed(address,uint256)
+func (e *Event) Sig() string {
+	return e.Name + e.Inputs.sig()
+}
+
+// Topic returns the 32 byte hash identifying the event in logs
+func (e *Event) Topic() []byte {
+	return keccak256([]byte(e.Sig()))
+}
+
+func (a Arguments) sig() string {
+	types := make([]string, len(a))
+	for i, arg := range a {
+		types[i] = strings.TrimSuffix(arg.Type, " payable")
+	}
+	return "(" + strings.Join(types, ",") + ")"
+}
+
+func keccak256(b []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(b)
+	return h.Sum(nil)
+}
+
+// ABI is the typed form of a contract's JSON ABI
+type ABI struct {
+	Constructor *Method
+	Methods     map[string]*Method
+	Events      map[string]*Event
+}
+
+type abiEntry struct {
+	Type            string    `json:"type"`
+	Name            string    `json:"name"`
+	Inputs          Arguments `json:"inputs"`
+	Outputs         Arguments `json:"outputs"`
+	StateMutability string    `json:"stateMutability"`
+	Anonymous       bool      `json:"anonymous"`
+}
+
+func ParseABI(raw []byte) (*ABI, error) {
+	var entries []abiEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal ABI: %w", err)
+	}
+
+	abi := &ABI{
+		Methods: make(map[string]*Method),
+		Events:  make(map[string]*Event),
+	}
+	for _, e := range entries {
+		for _, arg := range append(e.Inputs, e.Outputs...) {
+			if _, err := parseType(arg.Type); err != nil {
+				return nil, fmt.Errorf("%s %s: argument (%s): %w", e.Type, e.Name, arg.Name, err)
+			}
+		}
+		switch e.Type {
+		case "constructor":
+			abi.Constructor = &Method{
+				Inputs:          e.Inputs,
+				StateMutability: e.StateMutability,
+			}
+		case "function":
+			if _, ok := abi.Methods[e.Name]; ok {
+				return nil, fmt.Errorf("overloaded function (%s) is not supported", e.Name)
+			}
+			abi.Methods[e.Name] = &Method{
+				Name:            e.Name,
+				Inputs:          e.Inputs,
+				Outputs:         e.Outputs,
+				StateMutability: e.StateMutability,
+			}
+		case "event":
+			abi.Events[e.Name] = &Event{
+				Name:      e.Name,
+				Inputs:    e.Inputs,
+				Anonymous: e.Anonymous,
+			}
+		case "fallback", "receive", "error":
+			// Not callable through Firefly's named interface
+		default:
+			return nil, fmt.Errorf("unknown ABI entry type (%s)", e.Type)
+		}
+	}
+	// Solidity generates an implicit constructor without inputs
+	if abi.Constructor == nil {
+		abi.Constructor = &Method{StateMutability: "nonpayable"}
+	}
+	return abi, nil
+}
+
+// Pack validates args against the argument types and converts them to the
+// JSON values Firefly expects, in declaration order
+func (a Arguments) Pack(args ...any) ([]any, error) {
+	if len(args) != len(a) {
+		return nil, fmt.Errorf("expected %d arguments, got %d", len(a), len(args))
+	}
+	packed := make([]any, len(a))
+	for i, arg := range a {
+		t, err := parseType(arg.Type)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s): %w", i, arg.Name, err)
+		}
+		v, err := t.encode(args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s %s): %w", i, arg.Type, arg.Name, err)
+		}
+		packed[i] = v
+	}
+	return packed, nil
+}
+
+// PackNamed is Pack for Firefly's named input form, keyed by argument name
+func (a Arguments) PackNamed(args ...any) (map[string]any, error) {
+	packed, err := a.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	named := make(map[string]any, len(a))
+	for i, arg := range a {
+		named[a.key(i, arg, "input")] = packed[i]
+	}
+	return named, nil
+}
+
+// Unpack decodes JSON values returned by Firefly, keyed by argument name, into Go values.
+// Unnamed arguments are keyed "output", "output1", ... as Firefly does for return values.
+func (a Arguments) Unpack(values map[string]any) ([]any, error) {
+	unpacked := make([]any, len(a))
+	for i, arg := range a {
+		key := a.key(i, arg, "output")
+		raw, ok := values[key]
+		if !ok {
+			return nil, fmt.Errorf("missing value for argument %d (%s)", i, key)
+		}
+		t, err := parseType(arg.Type)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s): %w", i, key, err)
+		}
+		v, err := t.decode(raw)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s %s): %w", i, arg.Type, key, err)
+		}
+		unpacked[i] = v
+	}
+	return unpacked, nil
+}
+
+func (a Arguments) key(i int, arg Argument, prefix string) string {
+	if arg.Name != "" {
+		return arg.Name
+	}
+	if i == 0 {
+		return prefix
+	}
+	return prefix + strconv.Itoa(i)
+}
+
+// Decode converts the output of a Firefly blockchain event into Go values keyed by argument name
+func (e *Event) Decode(output map[string]any) (map[string]any, error) {
+	values, err := e.Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("event %s: %w", e.Name, err)
+	}
+	decoded := make(map[string]any, len(values))
+	for i, arg := range e.Inputs {
+		decoded[e.Inputs.key(i, arg, "output")] = values[i]
+	}
+	return decoded, nil
+}
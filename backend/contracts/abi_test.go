@@ -0,0 +1,471 @@
+package contracts
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testAddress = "0x5fbdb2315678afecb367f032d93f642f64180aa3"
+
+func mustAddress(t *testing.T, s string) Address {
+	t.Helper()
+	a, err := ParseAddress(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func mustBig(t *testing.T, s string) *big.Int {
+	t.Helper()
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("invalid integer (%s)", s)
+	}
+	return n
+}
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    solidityType
+		wantErr bool
+	}{
+		{in: "address", want: solidityType{kind: kindAddress, name: "address"}},
+		{in: "address payable", want: solidityType{kind: kindAddress, name: "address payable"}},
+		{in: "bool", want: solidityType{kind: kindBool, name: "bool"}},
+		{in: "string", want: solidityType{kind: kindString, name: "string"}},
+		{in: "bytes", want: solidityType{kind: kindBytes, name: "bytes"}},
+		{in: "bytes1", want: solidityType{kind: kindFixedBytes, size: 1, name: "bytes1"}},
+		{in: "bytes32", want: solidityType{kind: kindFixedBytes, size: 32, name: "bytes32"}},
+		{in: "uint", want: solidityType{kind: kindUint, size: 256, name: "uint"}},
+		{in: "uint8", want: solidityType{kind: kindUint, size: 8, name: "uint8"}},
+		{in: "uint256", want: solidityType{kind: kindUint, size: 256, name: "uint256"}},
+		{in: "int", want: solidityType{kind: kindInt, size: 256, name: "int"}},
+		{in: "int64", want: solidityType{kind: kindInt, size: 64, name: "int64"}},
+		{in: "bytes0", wantErr: true},
+		{in: "bytes33", wantErr: true},
+		{in: "uint7", wantErr: true},
+		{in: "uint264", wantErr: true},
+		{in: "int0", wantErr: true},
+		{in: "uint256[]", wantErr: true},
+		{in: "tuple", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseType(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseType(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseType(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPack(t *testing.T) {
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	overUint256 := new(big.Int).Lsh(big.NewInt(1), 256)
+	tests := []struct {
+		name    string
+		args    Arguments
+		in      []any
+		want    []any
+		wantErr string
+	}{
+		{
+			name: "address from string",
+			args: Arguments{{Name: "to", Type: "address"}},
+			in:   []any{strings.ToUpper(testAddress[2:])},
+			want: []any{testAddress},
+		},
+		{
+			name: "address from Address",
+			args: Arguments{{Name: "to", Type: "address"}},
+			in:   []any{mustAddress(t, testAddress)},
+			want: []any{testAddress},
+		},
+		{
+			name:    "address too short",
+			args:    Arguments{{Name: "to", Type: "address"}},
+			in:      []any{"0x1234"},
+			wantErr: "must be 40 hex characters",
+		},
+		{
+			name:    "address of wrong type",
+			args:    Arguments{{Name: "to", Type: "address"}},
+			in:      []any{42},
+			wantErr: "cannot use int as address",
+		},
+		{
+			name: "uint256 max",
+			args: Arguments{{Name: "price", Type: "uint256"}},
+			in:   []any{maxUint256},
+			want: []any{maxUint256.String()},
+		},
+		{
+			name:    "uint256 overflow",
+			args:    Arguments{{Name: "price", Type: "uint256"}},
+			in:      []any{overUint256},
+			wantErr: "overflows uint256",
+		},
+		{
+			name:    "uint256 negative",
+			args:    Arguments{{Name: "price", Type: "uint256"}},
+			in:      []any{-1},
+			wantErr: "cannot be negative",
+		},
+		{
+			name:    "uint8 overflow",
+			args:    Arguments{{Name: "status", Type: "uint8"}},
+			in:      []any{256},
+			wantErr: "overflows uint8",
+		},
+		{
+			name: "int8 negative bound",
+			args: Arguments{{Name: "delta", Type: "int8"}},
+			in:   []any{-128},
+			want: []any{"-128"},
+		},
+		{
+			name:    "int8 positive overflow",
+			args:    Arguments{{Name: "delta", Type: "int8"}},
+			in:      []any{128},
+			wantErr: "overflows int8",
+		},
+		{
+			name: "uint256 from decimal and hex strings",
+			args: Arguments{{Name: "a", Type: "uint256"}, {Name: "b", Type: "uint256"}},
+			in:   []any{"1000", "0xff"},
+			want: []any{"1000", "255"},
+		},
+		{
+			name:    "uint256 from inexact float",
+			args:    Arguments{{Name: "price", Type: "uint256"}},
+			in:      []any{1.5},
+			wantErr: "cannot be represented exactly",
+		},
+		{
+			name: "bool",
+			args: Arguments{{Name: "refund", Type: "bool"}},
+			in:   []any{true},
+			want: []any{true},
+		},
+		{
+			name:    "bool from string",
+			args:    Arguments{{Name: "refund", Type: "bool"}},
+			in:      []any{"true"},
+			wantErr: "cannot use string as bool",
+		},
+		{
+			name: "bytes",
+			args: Arguments{{Name: "data", Type: "bytes"}},
+			in:   []any{[]byte{0xde, 0xad, 0xbe, 0xef}},
+			want: []any{"0xdeadbeef"},
+		},
+		{
+			name: "empty bytes",
+			args: Arguments{{Name: "data", Type: "bytes"}},
+			in:   []any{[]byte{}},
+			want: []any{"0x"},
+		},
+		{
+			name: "fixed bytes",
+			args: Arguments{{Name: "tag", Type: "bytes4"}},
+			in:   []any{"0x01020304"},
+			want: []any{"0x01020304"},
+		},
+		{
+			name:    "fixed bytes of wrong length",
+			args:    Arguments{{Name: "tag", Type: "bytes4"}},
+			in:      []any{[]byte{1, 2, 3}},
+			wantErr: "bytes4 requires exactly 4 bytes, got 3",
+		},
+		{
+			name:    "bytes of invalid hex",
+			args:    Arguments{{Name: "data", Type: "bytes"}},
+			in:      []any{"0xzz"},
+			wantErr: "not valid hex",
+		},
+		{
+			name: "string",
+			args: Arguments{{Name: "uri", Type: "string"}},
+			in:   []any{"ipfs://x"},
+			want: []any{"ipfs://x"},
+		},
+		{
+			name:    "too few arguments",
+			args:    Arguments{{Name: "tokenId", Type: "uint256"}, {Name: "price", Type: "uint256"}},
+			in:      []any{1},
+			wantErr: "expected 2 arguments, got 1",
+		},
+		{
+			name:    "too many arguments",
+			args:    Arguments{{Name: "tokenId", Type: "uint256"}},
+			in:      []any{1, 2},
+			wantErr: "expected 1 arguments, got 2",
+		},
+		{
+			name:    "arguments in the wrong order",
+			args:    Arguments{{Name: "_nft", Type: "address"}, {Name: "_nftId", Type: "uint256"}},
+			in:      []any{1, testAddress},
+			wantErr: "argument 0 (address _nft)",
+		},
+		{
+			name: "constructor of the per-listing contract",
+			args: GetMarketplace().Constructor.Inputs,
+			in:   []any{testAddress, "7", big.NewInt(1500)},
+			want: []any{testAddress, "7", "1500"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.args.Pack(tt.in...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Pack() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Pack() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Pack() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackNamed(t *testing.T) {
+	args := Arguments{{Name: "tokenId", Type: "uint256"}, {Type: "bool"}}
+	got, err := args.PackNamed(3, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"tokenId": "3", "input1": false}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PackNamed() = %#v, want %#v", got, want)
+	}
+}
+
+func TestUnpack(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    Arguments
+		values  string
+		want    []any
+		wantErr string
+	}{
+		{
+			name:   "unnamed output",
+			args:   Arguments{{Type: "uint256"}},
+			values: `{"output": "1500"}`,
+			want:   []any{big.NewInt(1500)},
+		},
+		{
+			name:   "unnamed outputs are numbered after the first",
+			args:   Arguments{{Type: "address"}, {Type: "bool"}, {Type: "uint8"}},
+			values: `{"output": "` + testAddress + `", "output1": true, "output2": "2"}`,
+			want:   []any{mustAddress(t, testAddress), true, big.NewInt(2)},
+		},
+		{
+			name:   "named outputs",
+			args:   Arguments{{Name: "seller", Type: "address"}, {Name: "price", Type: "uint256"}},
+			values: `{"price": "115792089237316195423570985008687907853269984665640564039457584007913129639935", "seller": "` + testAddress + `"}`,
+			want:   []any{mustAddress(t, testAddress), mustBig(t, "115792089237316195423570985008687907853269984665640564039457584007913129639935")},
+		},
+		{
+			name:   "integer as JSON number",
+			args:   Arguments{{Type: "uint256"}},
+			values: `{"output": 42}`,
+			want:   []any{big.NewInt(42)},
+		},
+		{
+			name:   "bool as string",
+			args:   Arguments{{Type: "bool"}},
+			values: `{"output": "false"}`,
+			want:   []any{false},
+		},
+		{
+			name:   "bytes and fixed bytes",
+			args:   Arguments{{Name: "data", Type: "bytes"}, {Name: "tag", Type: "bytes2"}},
+			values: `{"data": "0xcafe", "tag": "0x0102"}`,
+			want:   []any{[]byte{0xca, 0xfe}, []byte{0x01, 0x02}},
+		},
+		{
+			name:   "string",
+			args:   Arguments{{Name: "uri", Type: "string"}},
+			values: `{"uri": "ipfs://x"}`,
+			want:   []any{"ipfs://x"},
+		},
+		{
+			name:    "missing named output",
+			args:    Arguments{{Name: "seller", Type: "address"}},
+			values:  `{"output": "` + testAddress + `"}`,
+			wantErr: "missing value for argument 0 (seller)",
+		},
+		{
+			name:    "missing second unnamed output",
+			args:    Arguments{{Type: "uint256"}, {Type: "uint256"}},
+			values:  `{"output": "1"}`,
+			wantErr: "missing value for argument 1 (output1)",
+		},
+		{
+			name:    "negative uint",
+			args:    Arguments{{Type: "uint256"}},
+			values:  `{"output": "-1"}`,
+			wantErr: "cannot be negative",
+		},
+		{
+			name:    "fixed bytes of wrong length",
+			args:    Arguments{{Name: "tag", Type: "bytes2"}},
+			values:  `{"tag": "0x010203"}`,
+			wantErr: "bytes2 requires exactly 2 bytes, got 3",
+		},
+		{
+			name:    "address of wrong type",
+			args:    Arguments{{Type: "address"}},
+			values:  `{"output": 1}`,
+			wantErr: "expected address string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var values map[string]any
+			if err := json.Unmarshal([]byte(tt.values), &values); err != nil {
+				t.Fatal(err)
+			}
+			got, err := tt.args.Unpack(values)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Unpack() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unpack() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unpack() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fireflyEvent is the shape of a blockchain event as Firefly returns it from /blockchainevents
+type fireflyEvent struct {
+	Name   string         `json:"name"`
+	Output map[string]any `json:"output"`
+}
+
+func TestEventDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		abi     *ABI
+		payload string
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name: "per-listing NFTBought",
+			abi:  GetMarketplace(),
+			payload: `{
+				"id": "4e7b1d2c-6b53-4d5c-9a53-0cf1f1c6a5c1",
+				"source": "ethereum",
+				"namespace": "default",
+				"name": "NFTBought",
+				"listener": "b1b9b0a3-5f62-4c1b-8f59-5b4f3d2a7b10",
+				"protocolId": "000000000042/000000/000001",
+				"output": {
+					"buyer": "0x2b5ad5c4795c026514f8317c7a215e218dccd6cf",
+					"seller": "` + testAddress + `",
+					"nftId": "12",
+					"price": "1500"
+				},
+				"info": {"blockNumber": "42", "transactionHash": "0x9fc76417374aa880d4449a1f7f31ec597f00b1f6f3dd2d66f4c9c6c445836d8b"},
+				"timestamp": "2024-05-01T09:30:00.000000000Z"
+			}`,
+			want: map[string]any{
+				"buyer":  mustAddress(t, "0x2b5ad5c4795c026514f8317c7a215e218dccd6cf"),
+				"seller": mustAddress(t, testAddress),
+				"nftId":  big.NewInt(12),
+				"price":  big.NewInt(1500),
+			},
+		},
+		{
+			name: "shared PriceChanged",
+			abi:  GetSharedMarketplace(),
+			payload: `{
+				"name": "PriceChanged",
+				"output": {"nftId": "3", "oldPrice": "2000", "newPrice": "1800"},
+				"timestamp": "2024-05-02T10:00:00Z"
+			}`,
+			want: map[string]any{
+				"nftId":    big.NewInt(3),
+				"oldPrice": big.NewInt(2000),
+				"newPrice": big.NewInt(1800),
+			},
+		},
+		{
+			name: "shared DisputeResolved",
+			abi:  GetSharedMarketplace(),
+			payload: `{
+				"name": "DisputeResolved",
+				"output": {"nftId": "3", "refunded": true}
+			}`,
+			want: map[string]any{
+				"nftId":    big.NewInt(3),
+				"refunded": true,
+			},
+		},
+		{
+			name: "missing field",
+			abi:  GetSharedMarketplace(),
+			payload: `{
+				"name": "NFTListed",
+				"output": {"seller": "` + testAddress + `", "nftId": "3"}
+			}`,
+			wantErr: "event NFTListed: missing value for argument 2 (price)",
+		},
+		{
+			name: "malformed address",
+			abi:  GetMarketplace(),
+			payload: `{
+				"name": "NFTListed",
+				"output": {"seller": "0xnothex", "nftId": "3"}
+			}`,
+			wantErr: "event NFTListed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e fireflyEvent
+			if err := json.Unmarshal([]byte(tt.payload), &e); err != nil {
+				t.Fatal(err)
+			}
+			event, ok := tt.abi.Events[e.Name]
+			if !ok {
+				t.Fatalf("ABI has no event (%s)", e.Name)
+			}
+			got, err := event.Decode(e.Output)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Decode() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package contracts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Artifact identifies the exact contract version the backend deploys or talks to
+type Artifact struct {
+	Name string
+	// ABIHash is the SHA-256 of the canonical JSON ABI
+	ABIHash string
+	// CodeHash is the SHA-256 of the creation bytecode, empty when the bytecode is not embedded
+	CodeHash string
+	// CompilerVersion is read from the solc metadata appended to the bytecode
+	CompilerVersion string
+}
+
+var (
+	mpArtifact  *Artifact
+	smpArtifact *Artifact
+)
+
+// Verify checks that the embedded artifacts are present and consistent with each other:
+// the bytecode must be valid hex and dispatch every function and emit every event of the ABI
+func Verify() error {
+	var errs []error
+	if err := verifyBin(mpTypedABI, mpBin); err != nil {
+		errs = append(errs, fmt.Errorf("Marketplace: %w", err))
+	}
+	if len(bytes.TrimSpace(smpABIRaw)) == 0 || len(smpTypedABI.Methods) == 0 {
+		errs = append(errs, fmt.Errorf("SharedMarketplace: embedded ABI is empty"))
+	}
+	return errors.Join(errs...)
+}
+
+// GetMarketplaceArtifact describes the embedded Marketplace contract deployed for every listing
+func GetMarketplaceArtifact() *Artifact {
+	return mpArtifact
+}
+
+// GetSharedMarketplaceArtifact describes the SharedMarketplace contract deployed by the operator
+func GetSharedMarketplaceArtifact() *Artifact {
+	return smpArtifact
+}
+
+func newArtifact(name string, abiRaw []byte, bin string) (*Artifact, error) {
+	var v any
+	if err := json.Unmarshal(abiRaw, &v); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal ABI of %s: %w", name, err)
+	}
+	// Re-marshalling sorts object keys, so formatting changes do not change the hash
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal ABI of %s: %w", name, err)
+	}
+	abiSum := sha256.Sum256(canonical)
+
+	a := &Artifact{
+		Name:    name,
+		ABIHash: hex.EncodeToString(abiSum[:]),
+	}
+	code, err := decodeBin(bin)
+	if err != nil || len(code) == 0 {
+		// Verify reports missing or malformed bytecode; the artifact is still usable for its ABI
+		return a, nil
+	}
+	codeSum := sha256.Sum256(code)
+	a.CodeHash = hex.EncodeToString(codeSum[:])
+	a.CompilerVersion = solcVersion(code)
+	return a, nil
+}
+
+func verifyBin(abi *ABI, bin string) error {
+	code, err := decodeBin(bin)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("embedded bytecode is empty, run make solc")
+	}
+
+	var missing []string
+	for _, m := range abi.Methods {
+		// PUSH4 <selector> in the function dispatcher
+		if !bytes.Contains(code, append([]byte{0x63}, m.Selector()...)) {
+			missing = append(missing, m.Sig())
+		}
+	}
+	for _, e := range abi.Events {
+		// PUSH32 <topic> before LOG
+		if !e.Anonymous && !bytes.Contains(code, append([]byte{0x7f}, e.Topic()...)) {
+			missing = append(missing, e.Sig())
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("bytecode does not match the ABI, missing %s; recompile with make solc", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func decodeBin(bin string) ([]byte, error) {
+	code, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(bin), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("embedded bytecode is not valid hex: %w", err)
+	}
+	return code, nil
+}
+
+// solcVersion extracts the compiler version from the CBOR metadata solc appends to the bytecode,
+// which encodes it as the key "solc" followed by a 3 byte string: major, minor, patch
+func solcVersion(code []byte) string {
+	marker := []byte{0x64, 's', 'o', 'l', 'c', 0x43}
+	i := bytes.LastIndex(code, marker)
+	if i < 0 || i+len(marker)+3 > len(code) {
+		return ""
+	}
+	v := code[i+len(marker) : i+len(marker)+3]
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
@@ -10,9 +10,18 @@ var mpABIRaw []byte
 
 var mpABI SmartContractABI
 
+var mpTypedABI *ABI
+
 //go:embed Marketplace.bin
 var mpBin string
 
+// SharedMarketplace is deployed once by the operator, so only its ABI is embedded
+//
+//go:embed SharedMarketplace.abi
+var smpABIRaw []byte
+
+var smpTypedABI *ABI
+
 type SmartContractABI []struct {
 	Inputs []struct {
 		InternalType string `json:"internalType"`
@@ -36,12 +45,41 @@ func init() {
 		panic(err)
 	}
 	mpABI = abis
+
+	typed, err := ParseABI(mpABIRaw)
+	if err != nil {
+		panic(err)
+	}
+	mpTypedABI = typed
+
+	shared, err := ParseABI(smpABIRaw)
+	if err != nil {
+		panic(err)
+	}
+	smpTypedABI = shared
+
+	if mpArtifact, err = newArtifact("Marketplace", mpABIRaw, mpBin); err != nil {
+		panic(err)
+	}
+	if smpArtifact, err = newArtifact("SharedMarketplace", smpABIRaw, ""); err != nil {
+		panic(err)
+	}
 }
 
 func GetMarketplaceABI() SmartContractABI {
 	return mpABI
 }
 
+// GetMarketplace returns the typed Marketplace ABI used to encode inputs and decode outputs
+func GetMarketplace() *ABI {
+	return mpTypedABI
+}
+
+// GetSharedMarketplace returns the typed SharedMarketplace ABI
+func GetSharedMarketplace() *ABI {
+	return smpTypedABI
+}
+
 func GetMarketplaceBin() string {
 	return mpBin
 }
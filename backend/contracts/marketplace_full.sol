@@ -1,92 +1,222 @@
 // SPDX-License-Identifier: Apache-2.0
 pragma solidity ^0.8.10;
 
-import "https://github.com/OpenZeppelin/openzeppelin-contracts/blob/master/contracts/token/ERC721/extensions/ERC721Full.sol";
 import "./marketplace.sol";
 
-contract Marketplace is ERC721Full {
-    event NFTNew(uint256 indexed nftId);
-    event NFTListed(address indexed seller, uint256 indexed nftId);
+interface IERC20 {
+    function balanceOf(address account) external view returns (uint256);
+    function transfer(address to, uint256 amount) external returns (bool);
+    function transferFrom(address from, address to, uint256 amount) external returns (bool);
+}
+
+// SharedMarketplace is a single marketplace contract shared by every listing of the NFT pool.
+// Instead of deploying one Marketplace per listing, sale state is tracked per token.
+// Sellers must approve this contract as an operator of their token before listing it.
+//
+// Purchases are paid in the ERC20 payment token. The price is held in escrow by this contract
+// from buy until it is released to the seller after receipt, see below.
+// Cancelling a bought item refunds the buyer. Buyers must approve the price as allowance first.
+// The whole price goes to the seller: platform fees and creator royalties are settled off chain.
+//
+// Buyers can also offer less than the price. An offer is held in escrow by this contract until the buyer
+// withdraws it or the seller accepts it, which sells the token to that buyer alone at the offered price.
+//
+// Buyers can dispute a bought or shipped item, and for disputeWindow seconds after receiving it. The NFT moves
+// to the buyer on receipt, but the payment stays in escrow until the window has passed and anyone releases it.
+// A dispute freezes the escrow until the arbiter resolves it, either refunding the buyer or completing the swap.
+// A buyer disputing after receipt hands the NFT back to the contract, which holds it until then.
+contract SharedMarketplace {
+    enum Status { Owned, Listed, Bought, Shipped, Disputed, Received }
+
+    event NFTListed(address indexed seller, uint256 indexed nftId, uint256 price);
     event NFTBought(address indexed buyer, address indexed seller, uint256 indexed nftId, uint256 price);
     event NFTShipped(address indexed seller, uint256 indexed nftId);
     event NFTReceived(address indexed buyer, uint256 indexed nftId);
     event NFTCancel(uint256 indexed nftId);
+    event PaymentReleased(address indexed seller, uint256 indexed nftId, uint256 amount);
+    event PaymentRefunded(address indexed buyer, uint256 indexed nftId, uint256 amount);
+    event PriceChanged(uint256 indexed nftId, uint256 oldPrice, uint256 newPrice);
+    event NFTDisputed(address indexed buyer, uint256 indexed nftId);
+    event DisputeResolved(uint256 indexed nftId, bool refunded);
+    event OfferMade(address indexed buyer, uint256 indexed nftId, uint256 amount);
+    event OfferWithdrawn(address indexed buyer, uint256 indexed nftId, uint256 amount);
 
-    mapping (uint256 => bool) statuses;
-    mapping (uint256 => uint256) prices;
-    mapping (uint256 => address payable) buyers; // buyer is payable incase of refund
+    IERC721 public nft;
+    IERC20 public payment;
+    address public arbiter;
+    // disputeWindow is how many seconds after receipt the buyer can still dispute
+    uint256 public disputeWindow;
 
-    constructor() ERC721("Marketplace NFT", "MPNFT") {}
+    mapping (uint256 => Status) public statuses;
+    mapping (uint256 => uint256) public prices;
+    mapping (uint256 => address) public sellers;
+    mapping (uint256 => address) public buyers;
+    // offers are the amounts buyers hold in escrow for a token, by buyer
+    mapping (uint256 => mapping (address => uint256)) public offers;
+    // receivedAt is when the buyer received a token whose payment is still in escrow, zero otherwise
+    mapping (uint256 => uint256) public receivedAt;
 
-    // When minting new token, do extra setup for our marketplace nft
-    function _mint(address to, uint256 tokenId) internal override(ERC721Full) {
-        super._mint(to, tokenId);
-        statuses.push(tokenId, "owned");
-        buyers.push(tokenId, address(0));
-        prices.push(tokenId, 0);
-        emit NFTNew(tokenId);
+    constructor(address _nft, address _payment, address _arbiter, uint256 _disputeWindow) {
+        nft = IERC721(_nft);
+        payment = IERC20(_payment);
+        arbiter = _arbiter;
+        disputeWindow = _disputeWindow;
     }
 
-    function _transferFrom(address from, address to, uint256 tokenId) internal override(ERC721Full) {
-        // If item is transferred reset sale status
-        super._transferFrom(from, to, tokenId);
-        statuses[tokenId] = "owned";
-        buyers[tokenId] = address(0);
-        prices[tokenId] = 0;
+    function list(uint256 tokenId, uint256 price) external {
+        require(nft.ownerOf(tokenId) == msg.sender, "Only NFT owner can list an item");
+        require(statuses[tokenId] == Status.Owned, "NFT must be in owned status");
+        statuses[tokenId] = Status.Listed;
+        prices[tokenId] = price;
+        sellers[tokenId] = msg.sender;
+        emit NFTListed(msg.sender, tokenId, price);
+    }
+
+    function setPrice(uint256 tokenId, uint256 price) external {
+        require(sellers[tokenId] == msg.sender, "Only seller can set the price");
+        require(statuses[tokenId] == Status.Listed, "NFT must be in listed status");
+        uint256 oldPrice = prices[tokenId];
+        prices[tokenId] = price;
+        emit PriceChanged(tokenId, oldPrice, price);
+    }
+
+    function buy(uint256 tokenId) external {
+        require(sellers[tokenId] != msg.sender, "Owner cannot buy own token");
+        require(statuses[tokenId] == Status.Listed, "NFT must be in listed status");
+        require(nft.ownerOf(tokenId) == sellers[tokenId], "seller no longer owns nft");
+        statuses[tokenId] = Status.Bought;
+        buyers[tokenId] = msg.sender;
+        require(payment.transferFrom(msg.sender, address(this), prices[tokenId]), "payment failed");
+        emit NFTBought(msg.sender, sellers[tokenId], tokenId, prices[tokenId]);
+    }
+
+    // offer escrows amount as the caller's offer for a listed token, taking or refunding the difference
+    // to an offer they already hold
+    function offer(uint256 tokenId, uint256 amount) external {
+        require(sellers[tokenId] != msg.sender, "Owner cannot offer on own token");
+        require(statuses[tokenId] == Status.Listed, "NFT must be in listed status");
+        require(amount > 0, "Offer must be above zero");
+        uint256 held = offers[tokenId][msg.sender];
+        offers[tokenId][msg.sender] = amount;
+        if (amount > held) {
+            require(payment.transferFrom(msg.sender, address(this), amount - held), "payment failed");
+        } else if (held > amount) {
+            require(payment.transfer(msg.sender, held - amount), "payment refund failed");
+        }
+        emit OfferMade(msg.sender, tokenId, amount);
     }
 
-    function list(uint256 tokenId) external {
-        require(this.ownerOf(tokenId) == msg.sender, "Only NFT owner can list an item");
-        require(statuses[tokenId] == "owned", "NFT must be in owned status");
-        statuses[tokenId] = "listed";
-        emit NFTListed(msg.sender, tokenId);
+    // withdrawOffer refunds the caller's offer, whatever has become of the token since
+    function withdrawOffer(uint256 tokenId) external {
+        uint256 amount = offers[tokenId][msg.sender];
+        require(amount > 0, "No offer to withdraw");
+        delete offers[tokenId][msg.sender];
+        require(payment.transfer(msg.sender, amount), "payment refund failed");
+        emit OfferWithdrawn(msg.sender, tokenId, amount);
     }
 
-    function buy(uint256 tokenId) payable external {
-        require(this.ownerOf(tokenId) != msg.sender, "Owner cannot buy own token");
-        require(statuses[tokenId] == "listed", "NFT must be in listed status");
-        statuses[tokenId] = "bought";
-        buyers[tokenId] = payable(msg.sender);
-        prices[tokenId] = msg.value;
-        emit NFTBought(msg.sender, this.ownerOf(tokenId), tokenId, msg.value);
+    // acceptOffer buys the token for buyer with the offer they escrowed. Unlike setPrice followed by buy,
+    // nobody else can buy the token at the offered price.
+    function acceptOffer(uint256 tokenId, address buyer, uint256 price) external {
+        require(sellers[tokenId] == msg.sender, "Only seller can accept an offer");
+        require(statuses[tokenId] == Status.Listed, "NFT must be in listed status");
+        require(price > 0 && offers[tokenId][buyer] == price, "Buyer has not offered the price");
+        require(nft.ownerOf(tokenId) == msg.sender, "seller no longer owns nft");
+        delete offers[tokenId][buyer];
+        statuses[tokenId] = Status.Bought;
+        buyers[tokenId] = buyer;
+        prices[tokenId] = price;
+        emit NFTBought(buyer, msg.sender, tokenId, price);
     }
 
     function shipped(uint256 tokenId) external {
-        require(this.ownerOf(tokenId) == msg.sender, "Only seller can set item as shipped");
-        require(statuses[tokenId] == "bought", "NFT must be in bought status");
-        statuses[tokenId] = "shipped";
+        require(sellers[tokenId] == msg.sender, "Only seller can set item as shipped");
+        require(statuses[tokenId] == Status.Bought, "NFT must be in bought status");
+        statuses[tokenId] = Status.Shipped;
         emit NFTShipped(msg.sender, tokenId);
     }
 
+    // received hands the NFT to the buyer. The payment stays in escrow until release.
     function received(uint256 tokenId) external {
         require(buyers[tokenId] == msg.sender, "Only buyer can call received");
-        require(statuses[tokenId] == "shipped", "NFT must be in bought shipped");
+        require(statuses[tokenId] == Status.Shipped, "NFT must be in shipped status");
 
-        address seller = this.ownerOf(tokenId);
-        // Send money to token owner
-        bool ok = seller.call{value: prices[tokenId]}("");
-        require(ok, "Failed to pay seller");
-
-        // Transfer owner to buyer (_transfer override resets marketplace fields)
-        this._transfer(seller, buyers[tokenId], tokenId);
+        statuses[tokenId] = Status.Received;
+        receivedAt[tokenId] = block.timestamp;
+        nft.transferFrom(sellers[tokenId], msg.sender, tokenId);
         emit NFTReceived(msg.sender, tokenId);
     }
 
+    // release pays the seller once the buyer can no longer dispute. Anyone may call it.
+    function release(uint256 tokenId) external {
+        require(statuses[tokenId] == Status.Received, "NFT must be in received status");
+        require(block.timestamp >= receivedAt[tokenId] + disputeWindow, "Dispute window is still open");
+
+        address seller = sellers[tokenId];
+        uint256 price = prices[tokenId];
+        _reset(tokenId);
+        require(payment.transfer(seller, price), "payment release failed");
+        emit PaymentReleased(seller, tokenId, price);
+    }
+
     function cancel(uint256 tokenId) external {
-        require(statuses[tokenId] != "shipped", "NFT cannot be shipped");
-        require(statuses[tokenId] != "owned", "NFT cannot be shipped");
-        statuses[tokenId] = "owned";
-        buyers[tokenId] = address(0);
-        price = prices[tokenId];
-        if (price > 0) {
-            bool ok = buyers[tokenId].call{value: price}("");
-            require(ok, "Failed to refund buyer");
+        require(statuses[tokenId] == Status.Listed || statuses[tokenId] == Status.Bought, "NFT must be listed or bought");
+        require(sellers[tokenId] == msg.sender || buyers[tokenId] == msg.sender, "Only seller or buyer can cancel");
+
+        bool bought = statuses[tokenId] == Status.Bought;
+        address buyer = buyers[tokenId];
+        uint256 price = prices[tokenId];
+        _reset(tokenId);
+        if (bought) {
+            require(payment.transfer(buyer, price), "payment refund failed");
+            emit PaymentRefunded(buyer, tokenId, price);
         }
-        statuses[tokenId] = "owned";
-        buyers[tokenId] = address(0);
-        prices[tokenId] = 0;
         emit NFTCancel(tokenId);
     }
 
-}
+    function dispute(uint256 tokenId) external {
+        require(buyers[tokenId] == msg.sender, "Only buyer can dispute");
+        if (statuses[tokenId] == Status.Received) {
+            require(block.timestamp < receivedAt[tokenId] + disputeWindow, "Dispute window has closed");
+            // The buyer must have approved the contract, which holds the NFT until the dispute is resolved
+            nft.transferFrom(msg.sender, address(this), tokenId);
+        } else {
+            require(statuses[tokenId] == Status.Bought || statuses[tokenId] == Status.Shipped, "NFT must be in bought, shipped or received status");
+        }
+        statuses[tokenId] = Status.Disputed;
+        emit NFTDisputed(msg.sender, tokenId);
+    }
+
+    function resolve(uint256 tokenId, bool refund) external {
+        require(arbiter == msg.sender, "Only arbiter can resolve a dispute");
+        require(statuses[tokenId] == Status.Disputed, "NFT must be in disputed status");
 
+        address seller = sellers[tokenId];
+        address buyer = buyers[tokenId];
+        uint256 price = prices[tokenId];
+        // A dispute opened after receipt left the NFT with the contract
+        address holder = receivedAt[tokenId] != 0 ? address(this) : seller;
+        _reset(tokenId);
+        if (refund) {
+            if (holder != seller) {
+                nft.transferFrom(holder, seller, tokenId);
+            }
+            require(payment.transfer(buyer, price), "payment refund failed");
+            emit PaymentRefunded(buyer, tokenId, price);
+        } else {
+            nft.transferFrom(holder, buyer, tokenId);
+            require(payment.transfer(seller, price), "payment release failed");
+            emit NFTReceived(buyer, tokenId);
+            emit PaymentReleased(seller, tokenId, price);
+        }
+        emit DisputeResolved(tokenId, refund);
+    }
+
+    function _reset(uint256 tokenId) internal {
+        statuses[tokenId] = Status.Owned;
+        prices[tokenId] = 0;
+        sellers[tokenId] = address(0);
+        buyers[tokenId] = address(0);
+        receivedAt[tokenId] = 0;
+    }
+}
@@ -0,0 +1,274 @@
+package contracts
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Address is a 20 byte Ethereum account or contract address
+type Address [20]byte
+
+func ParseAddress(s string) (Address, error) {
+	var a Address
+	h := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(h) != 2*len(a) {
+		return a, fmt.Errorf("address (%s) must be %d hex characters", s, 2*len(a))
+	}
+	if _, err := hex.Decode(a[:], []byte(h)); err != nil {
+		return a, fmt.Errorf("address (%s) is not valid hex: %w", s, err)
+	}
+	return a, nil
+}
+
+func (a Address) Hex() string {
+	return "0x" + hex.EncodeToString(a[:])
+}
+
+func (a Address) String() string {
+	return a.Hex()
+}
+
+type kind int
+
+const (
+	kindAddress kind = iota
+	kindUint
+	kindInt
+	kindBool
+	kindBytes
+	kindFixedBytes
+	kindString
+)
+
+// solidityType is a parsed elementary Solidity type such as uint256 or bytes32
+type solidityType struct {
+	kind kind
+	// size is the bit size for integers and the byte length for fixed bytes
+	size int
+	name string
+}
+
+func parseType(t string) (solidityType, error) {
+	switch {
+	case t == "address" || t == "address payable":
+		return solidityType{kind: kindAddress, name: t}, nil
+	case t == "bool":
+		return solidityType{kind: kindBool, name: t}, nil
+	case t == "string":
+		return solidityType{kind: kindString, name: t}, nil
+	case t == "bytes":
+		return solidityType{kind: kindBytes, name: t}, nil
+	case strings.HasPrefix(t, "bytes"):
+		n, err := strconv.Atoi(strings.TrimPrefix(t, "bytes"))
+		if err != nil || n < 1 || n > 32 {
+			return solidityType{}, fmt.Errorf("invalid fixed bytes type (%s)", t)
+		}
+		return solidityType{kind: kindFixedBytes, size: n, name: t}, nil
+	case strings.HasPrefix(t, "uint"), strings.HasPrefix(t, "int"):
+		k, bits := kindUint, strings.TrimPrefix(t, "uint")
+		if !strings.HasPrefix(t, "uint") {
+			k, bits = kindInt, strings.TrimPrefix(t, "int")
+		}
+		size := 256
+		if bits != "" {
+			n, err := strconv.Atoi(bits)
+			if err != nil || n < 8 || n > 256 || n%8 != 0 {
+				return solidityType{}, fmt.Errorf("invalid integer type (%s)", t)
+			}
+			size = n
+		}
+		return solidityType{kind: k, size: size, name: t}, nil
+	}
+	return solidityType{}, fmt.Errorf("unsupported type (%s)", t)
+}
+
+// encode validates v against the type and returns the JSON value Firefly expects
+func (t solidityType) encode(v any) (any, error) {
+	switch t.kind {
+	case kindAddress:
+		a, err := toAddress(v)
+		if err != nil {
+			return nil, err
+		}
+		return a.Hex(), nil
+	case kindUint, kindInt:
+		n, err := toBigInt(v)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.checkRange(n); err != nil {
+			return nil, err
+		}
+		// Firefly accepts integers as decimal strings, avoiding float64 precision loss
+		return n.String(), nil
+	case kindBool:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("cannot use %T as bool", v)
+		}
+		return b, nil
+	case kindBytes, kindFixedBytes:
+		b, err := toBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		if t.kind == kindFixedBytes && len(b) != t.size {
+			return nil, fmt.Errorf("%s requires exactly %d bytes, got %d", t.name, t.size, len(b))
+		}
+		return "0x" + hex.EncodeToString(b), nil
+	case kindString:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot use %T as string", v)
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("unsupported type (%s)", t.name)
+}
+
+// decode converts a JSON value returned by Firefly into a Go value:
+// Address, *big.Int, bool, []byte or string
+func (t solidityType) decode(v any) (any, error) {
+	switch t.kind {
+	case kindAddress:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected address string, got %T", v)
+		}
+		return ParseAddress(s)
+	case kindUint, kindInt:
+		n, err := toBigInt(v)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.checkRange(n); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case kindBool:
+		switch b := v.(type) {
+		case bool:
+			return b, nil
+		case string:
+			return strconv.ParseBool(b)
+		}
+		return nil, fmt.Errorf("expected bool, got %T", v)
+	case kindBytes, kindFixedBytes:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected hex string, got %T", v)
+		}
+		b, err := toBytes(s)
+		if err != nil {
+			return nil, err
+		}
+		if t.kind == kindFixedBytes && len(b) != t.size {
+			return nil, fmt.Errorf("%s requires exactly %d bytes, got %d", t.name, t.size, len(b))
+		}
+		return b, nil
+	case kindString:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %T", v)
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("unsupported type (%s)", t.name)
+}
+
+func (t solidityType) checkRange(n *big.Int) error {
+	if t.kind == kindUint {
+		if n.Sign() < 0 {
+			return fmt.Errorf("%s cannot be negative (%s)", t.name, n)
+		}
+		if n.BitLen() > t.size {
+			return fmt.Errorf("%s overflows %s", n, t.name)
+		}
+		return nil
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.size-1))
+	if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return fmt.Errorf("%s overflows %s", n, t.name)
+	}
+	return nil
+}
+
+func toAddress(v any) (Address, error) {
+	switch a := v.(type) {
+	case Address:
+		return a, nil
+	case [20]byte:
+		return a, nil
+	case string:
+		return ParseAddress(a)
+	}
+	return Address{}, fmt.Errorf("cannot use %T as address", v)
+}
+
+func toBigInt(v any) (*big.Int, error) {
+	switch n := v.(type) {
+	case *big.Int:
+		if n == nil {
+			return nil, fmt.Errorf("nil *big.Int")
+		}
+		return new(big.Int).Set(n), nil
+	case big.Int:
+		return new(big.Int).Set(&n), nil
+	case int:
+		return big.NewInt(int64(n)), nil
+	case int8:
+		return big.NewInt(int64(n)), nil
+	case int16:
+		return big.NewInt(int64(n)), nil
+	case int32:
+		return big.NewInt(int64(n)), nil
+	case int64:
+		return big.NewInt(n), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(n)), nil
+	case uint8:
+		return new(big.Int).SetUint64(uint64(n)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(n)), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(n)), nil
+	case uint64:
+		return new(big.Int).SetUint64(n), nil
+	case json.Number:
+		return toBigInt(string(n))
+	case float64:
+		// encoding/json decodes numbers into float64, which is only exact up to 2^53
+		if n != float64(int64(n)) || n > 1<<53 || n < -(1<<53) {
+			return nil, fmt.Errorf("number (%v) cannot be represented exactly, use a string", n)
+		}
+		return big.NewInt(int64(n)), nil
+	case string:
+		i, ok := new(big.Int).SetString(n, 0)
+		if !ok {
+			return nil, fmt.Errorf("(%s) is not an integer", n)
+		}
+		return i, nil
+	}
+	return nil, fmt.Errorf("cannot use %T as integer", v)
+}
+
+func toBytes(v any) ([]byte, error) {
+	switch b := v.(type) {
+	case []byte:
+		return b, nil
+	case Address:
+		return b[:], nil
+	case string:
+		h := strings.TrimPrefix(strings.TrimPrefix(b, "0x"), "0X")
+		out, err := hex.DecodeString(h)
+		if err != nil {
+			return nil, fmt.Errorf("(%s) is not valid hex: %w", b, err)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("cannot use %T as bytes", v)
+}
@@ -13,11 +13,284 @@ CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.listing (
     id varchar(255) NOT NULL PRIMARY KEY,
     item_name varchar(255) NOT NULL,
     item_state varchar(255) NOT NULL,
-    item_price INT NOT NULL,
+    item_price DECIMAL(78, 0) NOT NULL,
+    item_currency varchar(16) NOT NULL,
     nft_id varchar(255) NOT NULL,
     smart_contract_address varchar(255) NOT NULL,
+    seller_id varchar(255) NOT NULL DEFAULT '',
+    buyer_id varchar(255) NOT NULL DEFAULT '',
+    creator_id varchar(255) NOT NULL DEFAULT '',
+    pool_name varchar(255) NOT NULL DEFAULT '',
+    category varchar(64) NOT NULL DEFAULT '',
+    item_condition varchar(32) NOT NULL DEFAULT '',
+    attributes JSON,
+    images JSON,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX (category)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.webhook_subscription (
+    id varchar(255) NOT NULL PRIMARY KEY,
+    user_id varchar(255) NOT NULL,
+    url varchar(2048) NOT NULL,
+    secret varchar(255) NOT NULL,
+    event_types JSON NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_webhook_subscription_user_id (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.webhook_delivery (
+    id varchar(255) NOT NULL PRIMARY KEY,
+    subscription_id varchar(255) NOT NULL,
+    event_type varchar(255) NOT NULL,
+    payload BLOB NOT NULL,
+    state varchar(255) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_webhook_delivery_due (state, next_attempt_at),
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscription (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.contract_registry (
+    address varchar(255) NOT NULL PRIMARY KEY,
+    contract_name varchar(255) NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    abi_hash varchar(64) NOT NULL,
+    compiler_version varchar(255) NOT NULL,
+    deploy_tx_id varchar(255) NOT NULL,
+    deployed_by varchar(255) NOT NULL,
+    pool_name varchar(255) NOT NULL DEFAULT '',
+    nft_id varchar(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX (nft_id)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.sale (
+    id varchar(255) NOT NULL PRIMARY KEY,
+    item_id varchar(255) NOT NULL,
+    seller_id varchar(255) NOT NULL,
+    buyer_id varchar(255) NOT NULL,
+    creator_id varchar(255) NOT NULL,
+    currency varchar(16) NOT NULL,
+    price DECIMAL(78, 0) NOT NULL,
+    platform_fee DECIMAL(78, 0) NOT NULL,
+    royalty DECIMAL(78, 0) NOT NULL,
+    seller_proceeds DECIMAL(78, 0) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP NULL,
+    refunded_at TIMESTAMP NULL,
+    released_at TIMESTAMP NULL,
+    INDEX (item_id),
+    INDEX (created_at)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.price_change (
+    id varchar(255) NOT NULL PRIMARY KEY,
+    item_id varchar(255) NOT NULL,
+    currency varchar(16) NOT NULL,
+    old_price DECIMAL(78, 0) NOT NULL,
+    new_price DECIMAL(78, 0) NOT NULL,
+    changed_by varchar(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    INDEX (item_id, created_at)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.offer (
+    id varchar(255) NOT NULL PRIMARY KEY,
+    item_id varchar(255) NOT NULL,
+    buyer_id varchar(255) NOT NULL,
+    seller_id varchar(255) NOT NULL,
+    proposed_by varchar(255) NOT NULL,
+    amount DECIMAL(78, 0) NOT NULL,
+    currency varchar(16) NOT NULL,
+    state varchar(32) NOT NULL,
+    parent_id varchar(255) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    INDEX (item_id, state),
+    INDEX (buyer_id, state)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.shipment (
+    id varchar(255) NOT NULL PRIMARY KEY,
+    item_id varchar(255) NOT NULL,
+    seller_id varchar(255) NOT NULL,
+    buyer_id varchar(255) NOT NULL,
+    carrier varchar(64) NOT NULL,
+    tracking_number varchar(255) NOT NULL,
+    status varchar(32) NOT NULL,
+    delivered_at TIMESTAMP NULL,
+    confirmed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    INDEX (item_id, created_at),
+    INDEX (status)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.shipping_address (
+    item_id varchar(255) NOT NULL,
+    buyer_id varchar(255) NOT NULL,
+    ciphertext VARBINARY(4096) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (item_id, buyer_id)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.dispute (
+    id varchar(255) NOT NULL PRIMARY KEY,
+    item_id varchar(255) NOT NULL,
+    buyer_id varchar(255) NOT NULL,
+    seller_id varchar(255) NOT NULL,
+    reason TEXT NOT NULL,
+    state varchar(32) NOT NULL,
+    resolved_by varchar(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    INDEX (item_id),
+    INDEX (state, created_at)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.dispute_entry (
+    id varchar(255) NOT NULL PRIMARY KEY,
+    dispute_id varchar(255) NOT NULL,
+    actor_id varchar(255) NOT NULL,
+    action varchar(32) NOT NULL,
+    note TEXT NOT NULL,
+    evidence JSON NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL,
+    INDEX (dispute_id, created_at),
+    FOREIGN KEY (dispute_id) REFERENCES dispute (id)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.token_pool (
+    name varchar(255) NOT NULL PRIMARY KEY,
+    id varchar(255) NOT NULL,
+    type varchar(255) NOT NULL,
+    symbol varchar(16) NOT NULL,
+    locator varchar(1024) NOT NULL,
+    address varchar(255) NOT NULL,
+    connector varchar(255) NOT NULL,
+    state varchar(255) NOT NULL,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.nft_creator (
+    pool_name varchar(255) NOT NULL,
+    nft_id varchar(255) NOT NULL,
+    creator_id varchar(255) NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL,
+    PRIMARY KEY (pool_name, nft_id)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.media (
+    hash char(64) NOT NULL PRIMARY KEY,
+    content_type varchar(32) NOT NULL,
+    size BIGINT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    thumbnail_hash char(64) NOT NULL,
+    uploader_id varchar(255) NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL,
+    INDEX (thumbnail_hash)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.review (
+    id varchar(255) NOT NULL PRIMARY KEY,
+    sale_id varchar(255) NOT NULL UNIQUE,
+    item_id varchar(255) NOT NULL,
+    seller_id varchar(255) NOT NULL,
+    buyer_id varchar(255) NOT NULL,
+    rating TINYINT NOT NULL,
+    text TEXT NOT NULL,
+    hash char(64) NOT NULL,
+    anchor_id varchar(255) NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL,
+    INDEX (seller_id, created_at)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.saved_search (
+    id varchar(255) NOT NULL PRIMARY KEY,
+    user_id varchar(255) NOT NULL,
+    name varchar(255) NOT NULL,
+    query varchar(255) NOT NULL DEFAULT '',
+    category varchar(64) NOT NULL DEFAULT '',
+    min_condition varchar(32) NOT NULL DEFAULT '',
+    max_price DECIMAL(78, 0) NULL,
+    max_currency varchar(16) NOT NULL DEFAULT '',
+    attributes JSON,
+    created_at TIMESTAMP(6) NOT NULL,
+    INDEX (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.search_match (
+    search_id varchar(255) NOT NULL,
+    item_id varchar(255) NOT NULL,
+    PRIMARY KEY (search_id, item_id)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.watched_item (
+    user_id varchar(255) NOT NULL,
+    item_id varchar(255) NOT NULL,
+    price DECIMAL(78, 0) NOT NULL,
+    currency varchar(16) NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL,
+    PRIMARY KEY (user_id, item_id),
+    INDEX (item_id)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.notification (
+    id varchar(255) NOT NULL PRIMARY KEY,
+    user_id varchar(255) NOT NULL,
+    kind varchar(32) NOT NULL,
+    item_id varchar(255) NOT NULL,
+    item_name varchar(255) NOT NULL,
+    price DECIMAL(78, 0) NOT NULL,
+    currency varchar(16) NOT NULL,
+    search_id varchar(255) NOT NULL DEFAULT '',
+    previous_price DECIMAL(78, 0) NULL,
+    created_at TIMESTAMP(6) NOT NULL,
+    sent_at TIMESTAMP(6) NULL,
+    INDEX (user_id, sent_at)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.alert_settings (
+    user_id varchar(255) NOT NULL PRIMARY KEY,
+    frequency varchar(32) NOT NULL,
+    channel varchar(32) NOT NULL,
+    address varchar(255) NOT NULL DEFAULT '',
+    last_digest_at TIMESTAMP(6) NULL
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.alert_job (
+    id varchar(255) NOT NULL PRIMARY KEY,
+    item_id varchar(255) NOT NULL,
+    event_type varchar(64) NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL,
+    INDEX (created_at)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.import_job (
+    id varchar(255) NOT NULL PRIMARY KEY,
+    seller_id varchar(255) NOT NULL,
+    format varchar(16) NOT NULL,
+    state varchar(32) NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL,
+    updated_at TIMESTAMP(6) NOT NULL,
+    INDEX (seller_id, created_at),
+    INDEX (state, created_at)
+);
+
+CREATE TABLE IF NOT EXISTS $MYSQL_DATABASE.import_row (
+    job_id varchar(255) NOT NULL,
+    line INT NOT NULL,
+    state varchar(32) NOT NULL,
+    item JSON,
+    error TEXT NOT NULL,
+    PRIMARY KEY (job_id, line),
+    INDEX (job_id, state)
 );"
 
 echo "** Finished creating DB and root user"
\ No newline at end of file
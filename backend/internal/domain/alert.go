@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxSearchName  = 100
+	maxSearchQuery = 200
+)
+
+// SavedSearch is a search a user wants to hear about new matches of. Every criterion that is set must match.
+type SavedSearch struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+	// Query is words that must all appear in the item's name, in any case
+	Query string `json:"query,omitempty"`
+	// Category matches the category and its subcategories
+	Category string `json:"category,omitempty"`
+	// MinCondition matches items graded as good or better
+	MinCondition Condition `json:"min_condition,omitempty"`
+	// MaxPrice matches items priced at most this much, in its currency only
+	MaxPrice   *Money            `json:"max_price,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+func (s *SavedSearch) Validate() error {
+	if len(strings.TrimSpace(s.Name)) == 0 || utf8.RuneCountInString(s.Name) > maxSearchName {
+		return fmt.Errorf("search name must be between 1 and %d characters: %w", maxSearchName, ErrInvalidArgument)
+	}
+	if utf8.RuneCountInString(s.Query) > maxSearchQuery {
+		return fmt.Errorf("search query must be at most %d characters: %w", maxSearchQuery, ErrInvalidArgument)
+	}
+	if len(s.MinCondition) > 0 && !s.MinCondition.Valid() {
+		return fmt.Errorf("condition (%s) must be one of %v: %w", s.MinCondition, Conditions, ErrInvalidArgument)
+	}
+	if s.MaxPrice != nil && len(s.MaxPrice.Currency()) == 0 {
+		return fmt.Errorf("max price has no currency: %w", ErrInvalidArgument)
+	}
+	if len(strings.Fields(s.Query)) == 0 && len(s.Category) == 0 && s.MaxPrice == nil && len(s.Attributes) == 0 {
+		return fmt.Errorf("a search needs a query, category, max price or attributes: %w", ErrInvalidArgument)
+	}
+	if len(s.Category) == 0 {
+		if len(s.Attributes) > 0 {
+			return fmt.Errorf("attributes need a category: %w", ErrInvalidArgument)
+		}
+		return nil
+	}
+	c, err := CategoryByID(s.Category)
+	if err != nil {
+		return err
+	}
+	// A search may leave out required attributes, but the ones it has must fit the schema
+	for name, value := range s.Attributes {
+		i := slices.IndexFunc(c.Attributes, func(a AttributeSchema) bool { return a.Name == name })
+		if i < 0 {
+			return fmt.Errorf("category (%s) has no attribute (%s): %w", c.ID, name, ErrInvalidArgument)
+		}
+		if err := c.Attributes[i].validate(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Matches reports whether a listed item fits the search
+func (s *SavedSearch) Matches(item *Item) bool {
+	if item.State != ItemStateListed {
+		return false
+	}
+	name := strings.ToLower(item.Name)
+	for _, word := range strings.Fields(strings.ToLower(s.Query)) {
+		if !strings.Contains(name, word) {
+			return false
+		}
+	}
+	if len(s.Category) > 0 {
+		c, ok := categories[item.Category]
+		if !ok || !c.InCategory(s.Category) {
+			return false
+		}
+	}
+	if len(s.MinCondition) > 0 && !item.Condition.AtLeast(s.MinCondition) {
+		return false
+	}
+	if s.MaxPrice != nil && (item.Price.Currency() != s.MaxPrice.Currency() || item.Price.Cmp(*s.MaxPrice) > 0) {
+		return false
+	}
+	for name, value := range s.Attributes {
+		if item.Attributes[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// WatchedItem is an item a user wants to hear about when its price drops
+type WatchedItem struct {
+	UserID string `json:"user_id"`
+	ItemID string `json:"item_id"`
+	// Price is the price the user last heard of, which a drop is measured from
+	Price     Money     `json:"price"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type NotificationKind string
+
+const (
+	// NotificationKindMatch is a listing that matches a saved search
+	NotificationKindMatch NotificationKind = "match"
+	// NotificationKindPriceDrop is a watched item whose price went down
+	NotificationKindPriceDrop NotificationKind = "price_drop"
+)
+
+// Notification is something a user is told about in their next digest
+type Notification struct {
+	ID       string           `json:"id"`
+	UserID   string           `json:"user_id"`
+	Kind     NotificationKind `json:"kind"`
+	ItemID   string           `json:"item_id"`
+	ItemName string           `json:"item_name"`
+	Price    Money            `json:"price"`
+	// SearchID is the matched search of a match
+	SearchID string `json:"search_id,omitempty"`
+	// PreviousPrice is the price a price drop went down from
+	PreviousPrice *Money     `json:"previous_price,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// DigestFrequency is how often a user is sent their notifications
+type DigestFrequency string
+
+const (
+	// DigestFrequencyImmediate sends notifications as soon as the matcher finds them
+	DigestFrequencyImmediate DigestFrequency = "immediate"
+	DigestFrequencyHourly    DigestFrequency = "hourly"
+	DigestFrequencyDaily     DigestFrequency = "daily"
+	// DigestFrequencyNever keeps notifications in the user's list without sending them
+	DigestFrequencyNever DigestFrequency = "never"
+)
+
+// Interval is the least time between two digests, 0 for immediate and -1 for never
+func (f DigestFrequency) Interval() time.Duration {
+	switch f {
+	case DigestFrequencyImmediate:
+		return 0
+	case DigestFrequencyHourly:
+		return time.Hour
+	case DigestFrequencyDaily:
+		return 24 * time.Hour
+	}
+	return -1
+}
+
+func (f DigestFrequency) Valid() bool {
+	switch f {
+	case DigestFrequencyImmediate, DigestFrequencyHourly, DigestFrequencyDaily, DigestFrequencyNever:
+		return true
+	}
+	return false
+}
+
+// AlertSettings are how and how often a user wants their notifications
+type AlertSettings struct {
+	UserID    string          `json:"user_id"`
+	Frequency DigestFrequency `json:"frequency"`
+	// Channel is the notifier digests are sent through, such as log, webhook or email
+	Channel string `json:"channel"`
+	// Address is where the channel delivers to, the URL of a webhook or an email address
+	Address string `json:"address,omitempty"`
+	// LastDigestAt is when the last digest was sent, nil before the first one
+	LastDigestAt *time.Time `json:"last_digest_at,omitempty"`
+}
+
+// DigestDue reports whether a digest can be sent at now
+func (s *AlertSettings) DigestDue(now time.Time) bool {
+	interval := s.Frequency.Interval()
+	if interval < 0 {
+		return false
+	}
+	return s.LastDigestAt == nil || !now.Before(s.LastDigestAt.Add(interval))
+}
+
+// Digest is the notifications sent to a user at once
+type Digest struct {
+	UserID        string          `json:"user_id"`
+	Notifications []*Notification `json:"notifications"`
+}
+
+// DefaultAlertChannel is the channel of users who have not chosen one
+const DefaultAlertChannel = "log"
+
+// AlertJob is a listing change queued for the matcher
+type AlertJob struct {
+	ID        string
+	ItemID    string
+	EventType EventType
+	CreatedAt time.Time
+}
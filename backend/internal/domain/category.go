@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Condition grades the wear of a second-hand item
+type Condition string
+
+const (
+	// ConditionNew items are unused, with their tags or seals
+	ConditionNew Condition = "new"
+	// ConditionLikeNew items have been used but show no wear
+	ConditionLikeNew  Condition = "like_new"
+	ConditionVeryGood Condition = "very_good"
+	ConditionGood     Condition = "good"
+	ConditionFair     Condition = "fair"
+	// ConditionPoor items are worn or damaged, which the listing should describe
+	ConditionPoor Condition = "poor"
+)
+
+// Conditions is the grade scale, best first
+var Conditions = []Condition{ConditionNew, ConditionLikeNew, ConditionVeryGood, ConditionGood, ConditionFair, ConditionPoor}
+
+func (c Condition) Valid() bool {
+	return slices.Contains(Conditions, c)
+}
+
+// AtLeast reports whether c is as good as other or better. Ungraded items are not as good as any grade.
+func (c Condition) AtLeast(other Condition) bool {
+	i, j := slices.Index(Conditions, c), slices.Index(Conditions, other)
+	return i >= 0 && i <= j
+}
+
+type AttributeType string
+
+const (
+	AttributeTypeText    AttributeType = "text"
+	AttributeTypeInteger AttributeType = "integer"
+	// AttributeTypeEnum values must be one of the attribute's options
+	AttributeTypeEnum AttributeType = "enum"
+)
+
+// maxAttributeLength is the most bytes an attribute value is stored with
+const maxAttributeLength = 255
+
+// AttributeSchema describes an attribute items of a category can have
+type AttributeSchema struct {
+	Name     string        `json:"name"`
+	Type     AttributeType `json:"type"`
+	Required bool          `json:"required"`
+	Options  []string      `json:"options,omitempty"`
+	// Min and Max bound integer attributes when either is set
+	Min int64 `json:"min,omitempty"`
+	Max int64 `json:"max,omitempty"`
+}
+
+func (a *AttributeSchema) validate(value string) error {
+	if len(value) == 0 || len(value) > maxAttributeLength || !utf8.ValidString(value) {
+		return fmt.Errorf("attribute (%s) must be between 1 and %d bytes of text: %w", a.Name, maxAttributeLength, ErrInvalidArgument)
+	}
+	switch a.Type {
+	case AttributeTypeInteger:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("attribute (%s) must be an integer: %w", a.Name, ErrInvalidArgument)
+		}
+		if (a.Min != 0 || a.Max != 0) && (n < a.Min || n > a.Max) {
+			return fmt.Errorf("attribute (%s) must be between %d and %d: %w", a.Name, a.Min, a.Max, ErrInvalidArgument)
+		}
+	case AttributeTypeEnum:
+		if !slices.Contains(a.Options, value) {
+			return fmt.Errorf("attribute (%s) must be one of %v: %w", a.Name, a.Options, ErrInvalidArgument)
+		}
+	}
+	return nil
+}
+
+// Category is a node of the category taxonomy
+type Category struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ParentID string `json:"parent_id,omitempty"`
+	// Attributes are those inherited from the category's parents followed by its own
+	Attributes []AttributeSchema `json:"attributes"`
+}
+
+var (
+	brand    = AttributeSchema{Name: "brand", Type: AttributeTypeText, Required: true}
+	model    = AttributeSchema{Name: "model", Type: AttributeTypeText, Required: true}
+	colour   = AttributeSchema{Name: "colour", Type: AttributeTypeText}
+	material = AttributeSchema{Name: "material", Type: AttributeTypeText}
+)
+
+// taxonomy lists every category after its parent, with only the attributes it adds to its parent's
+var taxonomy = []Category{
+	{ID: "fashion", Name: "Fashion", Attributes: []AttributeSchema{brand, colour, material}},
+	{ID: "clothing", Name: "Clothing", ParentID: "fashion", Attributes: []AttributeSchema{
+		{Name: "size", Type: AttributeTypeEnum, Required: true, Options: []string{"XXS", "XS", "S", "M", "L", "XL", "XXL"}},
+	}},
+	{ID: "shoes", Name: "Shoes", ParentID: "fashion", Attributes: []AttributeSchema{
+		{Name: "size_eu", Type: AttributeTypeInteger, Required: true, Min: 15, Max: 52},
+	}},
+	{ID: "bags", Name: "Bags", ParentID: "fashion", Attributes: []AttributeSchema{
+		{Name: "model", Type: AttributeTypeText},
+	}},
+	{ID: "watches", Name: "Watches", Attributes: []AttributeSchema{brand, model,
+		{Name: "movement", Type: AttributeTypeEnum, Options: []string{"automatic", "manual", "quartz"}},
+		{Name: "year", Type: AttributeTypeInteger, Min: 1800, Max: 2100},
+	}},
+	{ID: "electronics", Name: "Electronics", Attributes: []AttributeSchema{brand, model}},
+	{ID: "phones", Name: "Phones", ParentID: "electronics", Attributes: []AttributeSchema{
+		{Name: "storage_gb", Type: AttributeTypeInteger, Min: 1, Max: 16384},
+	}},
+	{ID: "computers", Name: "Computers", ParentID: "electronics", Attributes: []AttributeSchema{
+		{Name: "storage_gb", Type: AttributeTypeInteger, Min: 1, Max: 1048576},
+		{Name: "memory_gb", Type: AttributeTypeInteger, Min: 1, Max: 16384},
+	}},
+	{ID: "other", Name: "Other"},
+}
+
+// categories are the taxonomy's categories by ID, with their inherited attributes resolved
+var categories = resolveTaxonomy(taxonomy)
+
+func resolveTaxonomy(tree []Category) map[string]*Category {
+	resolved := make(map[string]*Category, len(tree))
+	for _, c := range tree {
+		var attrs []AttributeSchema
+		if len(c.ParentID) > 0 {
+			parent, ok := resolved[c.ParentID]
+			if !ok {
+				panic(fmt.Sprintf("category (%s) is listed before its parent (%s)", c.ID, c.ParentID))
+			}
+			// A category may redefine an inherited attribute, such as making it optional
+			for _, a := range parent.Attributes {
+				if !slices.ContainsFunc(c.Attributes, func(own AttributeSchema) bool { return own.Name == a.Name }) {
+					attrs = append(attrs, a)
+				}
+			}
+		}
+		resolved[c.ID] = &Category{
+			ID:         c.ID,
+			Name:       c.Name,
+			ParentID:   c.ParentID,
+			Attributes: append(attrs, c.Attributes...),
+		}
+	}
+	return resolved
+}
+
+// Categories returns the whole taxonomy, parents before their children
+func Categories() []*Category {
+	list := make([]*Category, len(taxonomy))
+	for i, c := range taxonomy {
+		list[i] = categories[c.ID]
+	}
+	return list
+}
+
+func CategoryByID(id string) (*Category, error) {
+	c, ok := categories[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown category (%s): %w", id, ErrInvalidArgument)
+	}
+	return c, nil
+}
+
+// InCategory reports whether the category is id or one of its descendants
+func (c *Category) InCategory(id string) bool {
+	for cur := c; cur != nil; cur = categories[cur.ParentID] {
+		if cur.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAttributes checks attrs against the category's schema. Attributes outside of it are rejected.
+func (c *Category) ValidateAttributes(attrs map[string]string) error {
+	for name := range attrs {
+		if !slices.ContainsFunc(c.Attributes, func(a AttributeSchema) bool { return a.Name == name }) {
+			return fmt.Errorf("category (%s) has no attribute (%s): %w", c.ID, name, ErrInvalidArgument)
+		}
+	}
+	for _, a := range c.Attributes {
+		value, ok := attrs[a.Name]
+		if !ok {
+			if a.Required {
+				return fmt.Errorf("attribute (%s) is required in category (%s): %w", a.Name, c.ID, ErrInvalidArgument)
+			}
+			continue
+		}
+		if err := a.validate(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateDescription checks the item's condition, and its attributes against the schema of its category.
+// Items listed before categories existed have neither, so both stay optional.
+func (item *Item) ValidateDescription() error {
+	if len(item.Condition) > 0 && !item.Condition.Valid() {
+		return fmt.Errorf("condition (%s) must be one of %v: %w", item.Condition, Conditions, ErrInvalidArgument)
+	}
+	if len(item.Category) == 0 {
+		if len(item.Attributes) > 0 {
+			return fmt.Errorf("attributes need a category: %w", ErrInvalidArgument)
+		}
+		return nil
+	}
+	c, err := CategoryByID(item.Category)
+	if err != nil {
+		return err
+	}
+	return c.ValidateAttributes(item.Attributes)
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// ContractDeployment records which contract version backs a smart_contract_address
+type ContractDeployment struct {
+	Address         string `json:"address"`
+	ContractName    string `json:"contract_name"`
+	CodeHash        string `json:"code_hash"`
+	ABIHash         string `json:"abi_hash"`
+	CompilerVersion string `json:"compiler_version"`
+	DeployTxID      string `json:"deploy_tx_id,omitempty"`
+	DeployedBy      string `json:"deployed_by,omitempty"`
+	// PoolName and NFTID are the token a contract deployed per listing sells, empty for the shared contract
+	PoolName  string    `json:"pool_name,omitempty"`
+	NFTID     string    `json:"nft_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+type DisputeState string
+
+const (
+	// DisputeStateOpen disputes wait for the seller to respond
+	DisputeStateOpen DisputeState = "open"
+	// DisputeStateResponded disputes wait for an admin to arbitrate
+	DisputeStateResponded DisputeState = "responded"
+	// DisputeStateRefunded disputes were resolved for the buyer, who got the price back while the seller kept the NFT
+	DisputeStateRefunded DisputeState = "refunded"
+	// DisputeStateReleased disputes were resolved for the seller, finalizing the sale
+	DisputeStateReleased DisputeState = "released"
+)
+
+// DisputeOutcome is an admin's ruling on a dispute
+type DisputeOutcome string
+
+const (
+	DisputeOutcomeRefund  DisputeOutcome = "refund"
+	DisputeOutcomeRelease DisputeOutcome = "release"
+)
+
+func (o DisputeOutcome) Valid() bool {
+	return o == DisputeOutcomeRefund || o == DisputeOutcomeRelease
+}
+
+// Dispute is a buyer's return request on a sale they have not received yet
+type Dispute struct {
+	ID       string       `json:"id"`
+	ItemID   string       `json:"item_id"`
+	BuyerID  string       `json:"buyer_id"`
+	SellerID string       `json:"seller_id"`
+	Reason   string       `json:"reason"`
+	State    DisputeState `json:"state"`
+	// ResolvedBy is the admin who arbitrated the dispute
+	ResolvedBy string    `json:"resolved_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	// Trail is every step taken on the dispute, oldest first
+	Trail []*DisputeEntry `json:"trail,omitempty"`
+}
+
+type DisputeAction string
+
+const (
+	DisputeActionOpened    DisputeAction = "opened"
+	DisputeActionResponded DisputeAction = "responded"
+	DisputeActionResolved  DisputeAction = "resolved"
+)
+
+// DisputeEntry is one step of a dispute's audit trail. Entries are only ever appended.
+type DisputeEntry struct {
+	ID        string        `json:"id"`
+	DisputeID string        `json:"dispute_id"`
+	ActorID   string        `json:"actor_id"`
+	Action    DisputeAction `json:"action"`
+	Note      string        `json:"note"`
+	// Evidence are links to photos, documents or messages backing the note
+	Evidence  []string  `json:"evidence"`
+	CreatedAt time.Time `json:"created_at"`
+}
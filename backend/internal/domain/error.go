@@ -2,4 +2,15 @@ package domain
 
 import "errors"
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrForbidden       = errors.New("forbidden")
+	ErrConflict        = errors.New("conflict")
+	ErrUnsupported     = errors.New("unsupported")
+	// ErrInsufficientFunds rejects a payment before anything is sent to the chain
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrRateLimited       = errors.New("rate limited")
+	// ErrInvalidTransition rejects moving an item to a state its current state does not lead to
+	ErrInvalidTransition = errors.New("invalid transition")
+)
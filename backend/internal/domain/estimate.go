@@ -0,0 +1,31 @@
+package domain
+
+// EstimateConfidence is how much an estimate can be relied on
+type EstimateConfidence string
+
+const (
+	EstimateConfidenceLow    EstimateConfidence = "low"
+	EstimateConfidenceMedium EstimateConfidence = "medium"
+	EstimateConfidenceHigh   EstimateConfidence = "high"
+)
+
+// ComparableSale is a sale with the condition and attributes of the item it sold, which tell how
+// much it says about the price of a similar item
+type ComparableSale struct {
+	Sale       *Sale
+	Condition  Condition
+	Attributes map[string]string
+}
+
+// PriceEstimate is the fair price range of an item predicted from past sales
+type PriceEstimate struct {
+	ItemID string `json:"item_id,omitempty"`
+	// Low and High bound the middle half of comparable sales, Suggested is their median
+	Low        Money              `json:"low"`
+	Suggested  Money              `json:"suggested"`
+	High       Money              `json:"high"`
+	Confidence EstimateConfidence `json:"confidence"`
+	// OwnSales and SimilarSales are how many sales of the item itself and of similar items the estimate is based on
+	OwnSales     int `json:"own_sales"`
+	SimilarSales int `json:"similar_sales"`
+}
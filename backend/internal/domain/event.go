@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventItemListed    EventType = "item.listed"
+	EventItemSold      EventType = "item.sold"
+	EventItemShipped   EventType = "item.shipped"
+	EventItemReceived  EventType = "item.received"
+	EventItemCancelled EventType = "item.cancelled"
+	// EventItemPriceChanged is published when the seller changes the price of an active listing
+	EventItemPriceChanged EventType = "item.price_changed"
+	EventItemDisputed     EventType = "item.disputed"
+	EventItemRefunded     EventType = "item.refunded"
+	EventOfferMade        EventType = "offer.made"
+	EventOfferCountered   EventType = "offer.countered"
+	EventOfferAccepted    EventType = "offer.accepted"
+	EventOfferRejected    EventType = "offer.rejected"
+	// EventShipmentUpdated is published when a shipment is recorded, its carrier reports a new status, or the buyer confirms it
+	EventShipmentUpdated  EventType = "shipment.updated"
+	EventDisputeOpened    EventType = "dispute.opened"
+	EventDisputeResponded EventType = "dispute.responded"
+	EventDisputeResolved  EventType = "dispute.resolved"
+)
+
+func (t EventType) Valid() bool {
+	switch t {
+	case EventItemListed, EventItemSold, EventItemShipped, EventItemReceived, EventItemCancelled, EventItemPriceChanged, EventItemDisputed, EventItemRefunded,
+		EventOfferMade, EventOfferCountered, EventOfferAccepted, EventOfferRejected, EventShipmentUpdated,
+		EventDisputeOpened, EventDisputeResponded, EventDisputeResolved:
+		return true
+	}
+	return false
+}
+
+// Event is the envelope delivered to webhook and stream subscribers when an item changes state
+type Event struct {
+	ID        string    `json:"id"`
+	Type      EventType `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// EventParties returns the users an event's data concerns, who are the only ones it may be delivered to
+func EventParties(data any) []string {
+	var parties []string
+	switch d := data.(type) {
+	case *Item:
+		parties = []string{d.SellerID, d.BuyerID, d.CreatorID}
+	case *Offer:
+		parties = []string{d.SellerID, d.BuyerID}
+	case *Shipment:
+		parties = []string{d.SellerID, d.BuyerID}
+	case *Dispute:
+		parties = []string{d.SellerID, d.BuyerID}
+	}
+	return slices.DeleteFunc(parties, func(uid string) bool { return len(uid) == 0 })
+}
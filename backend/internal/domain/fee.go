@@ -0,0 +1,95 @@
+package domain
+
+import "time"
+
+// BasisPoints are hundredths of a percent, 250 is 2.5%
+const BasisPoints = 10000
+
+type FeeKind string
+
+const (
+	// FeeKindPercentage charges BasisPoints of the price
+	FeeKindPercentage FeeKind = "percentage"
+	// FeeKindFlat charges the Flat amount in the price's currency regardless of the price
+	FeeKindFlat FeeKind = "flat"
+	// FeeKindTiered charges the BasisPoints of the first tier the price fits in
+	FeeKindTiered FeeKind = "tiered"
+)
+
+// FeeTier applies to prices up to and including the bound in UpTo for their currency, or to any price when
+// UpTo is empty. Every currency has its own bounds, as minor units of one currency mean nothing in another,
+// and a price in a currency the tier has no bound for falls through to the next tier.
+type FeeTier struct {
+	UpTo        map[string]Money `json:"up_to,omitempty"`
+	BasisPoints int64            `json:"basis_points"`
+}
+
+// applies reports whether the tier's rate is charged on price
+func (t FeeTier) applies(price Money) bool {
+	if len(t.UpTo) == 0 {
+		return true
+	}
+	upTo, ok := t.UpTo[price.Currency()]
+	return ok && price.Cmp(upTo) <= 0
+}
+
+// FeeSchedule is the platform fee charged on every sale
+type FeeSchedule struct {
+	Kind        FeeKind `json:"kind"`
+	BasisPoints int64   `json:"basis_points,omitempty"`
+	// Flat is the fee by currency, as minor units of one currency mean nothing in another.
+	// Sales in a currency without a flat fee are charged none.
+	Flat  map[string]Money `json:"flat,omitempty"`
+	Tiers []FeeTier        `json:"tiers,omitempty"`
+}
+
+// Fee returns the platform fee for a price, in the same currency and never more than the price itself
+func (s *FeeSchedule) Fee(price Money) Money {
+	fee := ZeroMoney(price.Currency())
+	switch s.Kind {
+	case FeeKindPercentage:
+		fee = price.MulBasisPoints(s.BasisPoints)
+	case FeeKindFlat:
+		if flat, ok := s.Flat[price.Currency()]; ok {
+			fee = flat
+		}
+	case FeeKindTiered:
+		for _, t := range s.Tiers {
+			if t.applies(price) {
+				fee = price.MulBasisPoints(t.BasisPoints)
+				break
+			}
+		}
+	}
+	return fee.Min(price)
+}
+
+// Sale records how the price of a purchase is split between the platform, the creator and the seller.
+// The split is not enforced on chain: the shared contract pays the whole price to the seller, per-listing
+// contracts move no payment at all, and the platform fee and royalty are recorded for the platform to collect
+// and pay out off chain.
+type Sale struct {
+	ID       string `json:"id"`
+	ItemID   string `json:"item_id"`
+	SellerID string `json:"seller_id"`
+	BuyerID  string `json:"buyer_id"`
+	// CreatorID is the user who minted or first listed the NFT, who earns a royalty when someone else resells it
+	CreatorID      string    `json:"creator_id"`
+	Price          Money     `json:"price"`
+	PlatformFee    Money     `json:"platform_fee"`
+	Royalty        Money     `json:"royalty"`
+	SellerProceeds Money     `json:"seller_proceeds"`
+	CreatedAt      time.Time `json:"created_at"`
+	// CompletedAt is when the sale was settled: on receipt with the shared contract, and on purchase with a
+	// per-listing one, which transfers the NFT right away. Nil until then.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// RefundedAt is when the price went back to the buyer, because the sale was cancelled or a dispute refunded it
+	RefundedAt *time.Time `json:"refunded_at,omitempty"`
+	// ReleasedAt is when the seller was paid out of escrow, once the buyer could no longer dispute
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+}
+
+// Settled reports whether the sale went through: it was completed and the buyer did not get the price back
+func (s *Sale) Settled() bool {
+	return s.CompletedAt != nil && s.RefundedAt == nil
+}
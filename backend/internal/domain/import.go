@@ -0,0 +1,83 @@
+package domain
+
+import "time"
+
+// ImportFormat is the file format of a bulk listing import
+type ImportFormat string
+
+const (
+	// ImportFormatCSV has a header row naming the columns, see the README for them
+	ImportFormatCSV ImportFormat = "csv"
+	// ImportFormatJSONL has one item per line, as /items/list takes it
+	ImportFormatJSONL ImportFormat = "jsonl"
+)
+
+func (f ImportFormat) Valid() bool {
+	return f == ImportFormatCSV || f == ImportFormatJSONL
+}
+
+type ImportJobState string
+
+const (
+	// ImportJobStatePending jobs have rows waiting to be listed
+	ImportJobStatePending ImportJobState = "pending"
+	// ImportJobStateCompleted jobs have no pending rows left, though some may have failed
+	ImportJobStateCompleted ImportJobState = "completed"
+)
+
+type ImportRowState string
+
+const (
+	// ImportRowStateInvalid rows failed validation on upload and are never listed
+	ImportRowStateInvalid ImportRowState = "invalid"
+	ImportRowStatePending ImportRowState = "pending"
+	ImportRowStateListed  ImportRowState = "listed"
+	// ImportRowStateFailed rows could not be listed, and are listed again when the job is retried
+	ImportRowStateFailed ImportRowState = "failed"
+)
+
+// ImportJob is a file of items a seller lists at once. Its rows are listed in the background, and the counts
+// say how many rows are in each state.
+type ImportJob struct {
+	ID        string         `json:"id"`
+	SellerID  string         `json:"seller_id"`
+	Format    ImportFormat   `json:"format"`
+	State     ImportJobState `json:"state"`
+	Total     int            `json:"total"`
+	Pending   int            `json:"pending"`
+	Listed    int            `json:"listed"`
+	Failed    int            `json:"failed"`
+	Invalid   int            `json:"invalid"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	// Rows are only filled in when a single job is asked for
+	Rows []*ImportRow `json:"rows,omitempty"`
+}
+
+// ImportRow is an item of an import job
+type ImportRow struct {
+	JobID string `json:"-"`
+	// Line is the row's line in the file, counting from 1 and including a CSV header
+	Line  int            `json:"line"`
+	State ImportRowState `json:"state"`
+	// Item is nil when the row could not be parsed
+	Item  *Item  `json:"item,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Count tallies the rows by state into the job's counts
+func (j *ImportJob) Count(rows []*ImportRow) {
+	j.Total, j.Pending, j.Listed, j.Failed, j.Invalid = len(rows), 0, 0, 0, 0
+	for _, r := range rows {
+		switch r.State {
+		case ImportRowStatePending:
+			j.Pending++
+		case ImportRowStateListed:
+			j.Listed++
+		case ImportRowStateFailed:
+			j.Failed++
+		case ImportRowStateInvalid:
+			j.Invalid++
+		}
+	}
+}
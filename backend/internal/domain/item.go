@@ -1,18 +1,78 @@
 package domain
 
+import (
+	"fmt"
+	"strconv"
+)
+
+// ItemState is where an item is in its sale. Only the transitions in transition.go move an item between states.
 type ItemState int64
 
 const (
 	ItemStateUnspecified ItemState = iota
 	ItemStateListed
 	ItemStateSold
+	ItemStateShipped
+	ItemStateReceived
+	ItemStateCancelled
+	// ItemStateDisputed sales are frozen until an admin resolves the buyer's dispute
+	ItemStateDisputed
+	// ItemStateRefunded sales were reversed by a dispute, the buyer got the price back and the seller kept the NFT
+	ItemStateRefunded
 )
 
+var itemStateNames = map[ItemState]string{
+	ItemStateUnspecified: "unspecified",
+	ItemStateListed:      "listed",
+	ItemStateSold:        "sold",
+	ItemStateShipped:     "shipped",
+	ItemStateReceived:    "received",
+	ItemStateCancelled:   "cancelled",
+	ItemStateDisputed:    "disputed",
+	ItemStateRefunded:    "refunded",
+}
+
+// String returns the name the state is stored under
+func (s ItemState) String() string {
+	if name, ok := itemStateNames[s]; ok {
+		return name
+	}
+	return "ItemState(" + strconv.FormatInt(int64(s), 10) + ")"
+}
+
+// ParseItemState reads a stored state. Rows written before states were stored by name hold the number instead.
+func ParseItemState(s string) (ItemState, error) {
+	for state, name := range itemStateNames {
+		if name == s {
+			return state, nil
+		}
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if _, ok := itemStateNames[ItemState(n)]; ok {
+			return ItemState(n), nil
+		}
+	}
+	return ItemStateUnspecified, fmt.Errorf("unknown item state (%s): %w", s, ErrInvalidArgument)
+}
+
 type Item struct {
 	ID                   string `json:"item_id"`
 	Name                 string `json:"item_name"`
 	State                ItemState
-	Price                int64  `json:"item_price"`
+	Price                Money  `json:"item_price"`
 	NFTID                string `json:"nft_id"`
 	SmartContractAddress string `json:"smart_contract_address"`
+	SellerID             string `json:"seller_id"`
+	BuyerID              string `json:"buyer_id,omitempty"`
+	// CreatorID is the user who minted or first listed the item's NFT, kept across resales and relistings
+	CreatorID string `json:"creator_id,omitempty"`
+	// PoolName is the token pool holding the item's NFT, empty for the default pool
+	PoolName string `json:"pool_name,omitempty"`
+	// Category is the ID of the item's category, whose schema Attributes follow
+	Category   string            `json:"category,omitempty"`
+	Condition  Condition         `json:"condition,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Images are the hashes of the item's uploaded photos, in the order they are shown.
+	// They cannot change once the item's NFT is minted, as its token URI carries their hashes.
+	Images []string `json:"images,omitempty"`
 }
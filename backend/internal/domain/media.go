@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// maxItemImages is how many images a listing can show
+const maxItemImages = 10
+
+// Media is an uploaded image. It is stored under the SHA-256 of its bytes, taken after its metadata was stripped,
+// so anyone holding the hash can tell whether the image they are served is the one that was uploaded.
+type Media struct {
+	Hash        string `json:"hash"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	// ThumbnailHash is the hash of the scaled down copy, which is stored in the same format
+	ThumbnailHash string    `json:"thumbnail_hash"`
+	UploaderID    string    `json:"uploader_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ValidateMediaHash checks that hash is a hex encoded SHA-256, as media is stored under
+func ValidateMediaHash(hash string) error {
+	if b, err := hex.DecodeString(hash); err != nil || len(b) != 32 || hex.EncodeToString(b) != hash {
+		return fmt.Errorf("media hash (%s) must be a lowercase hex encoded SHA-256: %w", hash, ErrInvalidArgument)
+	}
+	return nil
+}
+
+// ValidateImages checks the hashes of the item's images. Whether they were uploaded is up to the caller.
+func (item *Item) ValidateImages() error {
+	if len(item.Images) > maxItemImages {
+		return fmt.Errorf("an item can have at most %d images: %w", maxItemImages, ErrInvalidArgument)
+	}
+	for i, hash := range item.Images {
+		if err := ValidateMediaHash(hash); err != nil {
+			return err
+		}
+		for _, other := range item.Images[:i] {
+			if other == hash {
+				return fmt.Errorf("image (%s) is given twice: %w", hash, ErrInvalidArgument)
+			}
+		}
+	}
+	return nil
+}
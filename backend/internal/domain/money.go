@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+)
+
+// maxUint256 is the largest amount a contract can hold in a uint256
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// currencyCode matches ISO 4217 codes such as USD as well as token symbols such as KLD or USDC
+var currencyCode = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,15}$`)
+
+// Money is an exact amount in the minor units of a currency: cents for USD, or the base units (wei) of a token.
+// Amounts are never negative and always fit in a uint256, so they can be sent to a contract as is.
+// The zero value is a zero amount without a currency.
+type Money struct {
+	amount   *big.Int
+	currency string
+}
+
+// NewMoney validates amount, in minor units, and the currency code
+func NewMoney(amount *big.Int, currency string) (Money, error) {
+	if !currencyCode.MatchString(currency) {
+		return Money{}, fmt.Errorf("currency (%s) is not an upper case ISO 4217 code or token symbol: %w", currency, ErrInvalidArgument)
+	}
+	if amount == nil || amount.Sign() < 0 || amount.Cmp(maxUint256) > 0 {
+		return Money{}, fmt.Errorf("amount (%v) must be between 0 and 2^256-1: %w", amount, ErrInvalidArgument)
+	}
+	return Money{amount: new(big.Int).Set(amount), currency: currency}, nil
+}
+
+// ZeroMoney returns nothing in the given currency
+func ZeroMoney(currency string) Money {
+	return Money{amount: new(big.Int), currency: currency}
+}
+
+// ParseMoney reads a decimal string of minor units, as stored in the database
+func ParseMoney(amount, currency string) (Money, error) {
+	n, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return Money{}, fmt.Errorf("amount (%s) is not an integer number of minor units: %w", amount, ErrInvalidArgument)
+	}
+	return NewMoney(n, currency)
+}
+
+// Amount returns a copy of the amount in minor units
+func (m Money) Amount() *big.Int {
+	if m.amount == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(m.amount)
+}
+
+func (m Money) Currency() string {
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == nil || m.amount.Sign() == 0
+}
+
+// Uint256 returns the amount as the integer a contract expects
+func (m Money) Uint256() *big.Int {
+	return m.Amount()
+}
+
+// Cmp compares the amounts of two values in the same currency
+func (m Money) Cmp(o Money) int {
+	return m.Amount().Cmp(o.Amount())
+}
+
+// Add returns m + o, which must be in the same currency
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return NewMoney(new(big.Int).Add(m.Amount(), o.Amount()), m.currency)
+}
+
+// Sub returns m - o, which must be in the same currency and not exceed m
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return NewMoney(new(big.Int).Sub(m.Amount(), o.Amount()), m.currency)
+}
+
+// MulBasisPoints returns bps hundredths of a percent of m, rounded down to a whole minor unit
+func (m Money) MulBasisPoints(bps int64) Money {
+	n := new(big.Int).Mul(m.Amount(), big.NewInt(bps))
+	return Money{amount: n.Quo(n, big.NewInt(BasisPoints)), currency: m.currency}
+}
+
+// Min returns the smaller of two values in the same currency
+func (m Money) Min(o Money) Money {
+	if o.Cmp(m) < 0 {
+		return o
+	}
+	return m
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.currency != o.currency {
+		return fmt.Errorf("cannot combine %s with %s: %w", m.currency, o.currency, ErrInvalidArgument)
+	}
+	return nil
+}
+
+func (m Money) String() string {
+	return m.Amount().String() + " " + m.currency
+}
+
+// moneyJSON keeps the amount a string, as JSON numbers lose precision beyond 2^53
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Amount().String(), Currency: m.currency})
+}
+
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var v moneyJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("money must be {\"amount\": \"<minor units>\", \"currency\": \"<code>\"}: %w", ErrInvalidArgument)
+	}
+	parsed, err := ParseMoney(v.Amount, v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func mustParseMoney(t *testing.T, amount, currency string) Money {
+	t.Helper()
+	m, err := ParseMoney(amount, currency)
+	if err != nil {
+		t.Fatalf("ParseMoney(%q, %q): %v", amount, currency, err)
+	}
+	return m
+}
+
+func TestNewMoneyBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  *big.Int
+		wantErr bool
+	}{
+		{name: "zero", amount: big.NewInt(0)},
+		{name: "one", amount: big.NewInt(1)},
+		{name: "max uint256", amount: new(big.Int).Set(maxUint256)},
+		{name: "above uint256", amount: new(big.Int).Lsh(big.NewInt(1), 256), wantErr: true},
+		{name: "negative", amount: big.NewInt(-1), wantErr: true},
+		{name: "nil", amount: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMoney(tt.amount, "USD")
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Errorf("NewMoney(%v) = %v, want %v", tt.amount, err, ErrInvalidArgument)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewMoney(%v): %v", tt.amount, err)
+			}
+			if m.Uint256().Cmp(tt.amount) != 0 {
+				t.Errorf("Uint256() = %s, want %s", m.Uint256(), tt.amount)
+			}
+		})
+	}
+}
+
+func TestNewMoneyDoesNotAlias(t *testing.T) {
+	n := big.NewInt(100)
+	m, err := NewMoney(n, "USD")
+	if err != nil {
+		t.Fatalf("NewMoney: %v", err)
+	}
+	n.SetInt64(1)
+	m.Amount().SetInt64(2)
+	if m.Amount().Int64() != 100 {
+		t.Errorf("amount = %s after changing the given and returned big.Int, want 100", m.Amount())
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     string
+		wantErr  bool
+	}{
+		{amount: "0", currency: "USD", want: "0"},
+		{amount: "1999", currency: "USD", want: "1999"},
+		{amount: maxUint256.String(), currency: "KLD", want: maxUint256.String()},
+		{amount: "-1", currency: "USD", wantErr: true},
+		{amount: "-0.5", currency: "USD", wantErr: true},
+		{amount: "19.99", currency: "USD", wantErr: true},
+		{amount: "1e3", currency: "USD", wantErr: true},
+		{amount: "", currency: "USD", wantErr: true},
+		{amount: "ten", currency: "USD", wantErr: true},
+		{amount: new(big.Int).Lsh(big.NewInt(1), 256).String(), currency: "KLD", wantErr: true},
+		{amount: "100", currency: "usd", wantErr: true},
+		{amount: "100", currency: "U", wantErr: true},
+		{amount: "100", currency: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.amount+" "+tt.currency, func(t *testing.T) {
+			m, err := ParseMoney(tt.amount, tt.currency)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Errorf("ParseMoney(%q, %q) = %v, want %v", tt.amount, tt.currency, err, ErrInvalidArgument)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMoney(%q, %q): %v", tt.amount, tt.currency, err)
+			}
+			if m.Amount().String() != tt.want || m.Currency() != tt.currency {
+				t.Errorf("ParseMoney(%q, %q) = %s, want %s %s", tt.amount, tt.currency, m, tt.want, tt.currency)
+			}
+		})
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	for _, m := range []Money{
+		mustParseMoney(t, "0", "USD"),
+		mustParseMoney(t, "1999", "USD"),
+		// Beyond 2^53, where JSON numbers would lose precision
+		mustParseMoney(t, "9007199254740993", "KLD"),
+		mustParseMoney(t, maxUint256.String(), "KLD"),
+	} {
+		t.Run(m.String(), func(t *testing.T) {
+			b, err := json.Marshal(m)
+			if err != nil {
+				t.Fatalf("json.Marshal: %v", err)
+			}
+			want := `{"amount":"` + m.Amount().String() + `","currency":"` + m.Currency() + `"}`
+			if string(b) != want {
+				t.Errorf("json.Marshal = %s, want %s", b, want)
+			}
+			var got Money
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("json.Unmarshal(%s): %v", b, err)
+			}
+			if got.Currency() != m.Currency() || got.Cmp(m) != 0 {
+				t.Errorf("round trip of %s = %s", m, got)
+			}
+		})
+	}
+}
+
+func TestMoneyUnmarshalJSONRejects(t *testing.T) {
+	for _, b := range []string{
+		`{"amount":1999,"currency":"USD"}`,
+		`{"amount":"-1","currency":"USD"}`,
+		`{"amount":"19.99","currency":"USD"}`,
+		`{"amount":"` + new(big.Int).Lsh(big.NewInt(1), 256).String() + `","currency":"KLD"}`,
+		`{"amount":"100"}`,
+		`"100 USD"`,
+	} {
+		t.Run(b, func(t *testing.T) {
+			var m Money
+			if err := json.Unmarshal([]byte(b), &m); !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("json.Unmarshal(%s) = %v, want %v", b, err, ErrInvalidArgument)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	usd := func(amount string) Money { return mustParseMoney(t, amount, "USD") }
+	top := mustParseMoney(t, maxUint256.String(), "USD")
+
+	if got, err := usd("150").Add(usd("50")); err != nil || got.Cmp(usd("200")) != 0 || got.Currency() != "USD" {
+		t.Errorf("150 + 50 = %s, %v, want 200 USD", got, err)
+	}
+	if got, err := usd("150").Sub(usd("50")); err != nil || got.Cmp(usd("100")) != 0 {
+		t.Errorf("150 - 50 = %s, %v, want 100 USD", got, err)
+	}
+	if got, err := usd("50").Sub(usd("50")); err != nil || !got.IsZero() {
+		t.Errorf("50 - 50 = %s, %v, want 0 USD", got, err)
+	}
+	if _, err := usd("50").Sub(usd("51")); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("50 - 51 = %v, want %v", err, ErrInvalidArgument)
+	}
+	if _, err := top.Add(usd("1")); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("2^256-1 + 1 = %v, want %v", err, ErrInvalidArgument)
+	}
+	if got := usd("10001").MulBasisPoints(250); got.Cmp(usd("250")) != 0 {
+		t.Errorf("250 bps of 10001 = %s, want 250 USD", got)
+	}
+	if got := top.MulBasisPoints(BasisPoints); got.Cmp(top) != 0 {
+		t.Errorf("100%% of 2^256-1 = %s, want 2^256-1", got)
+	}
+	if got := usd("100").Min(usd("40")); got.Cmp(usd("40")) != 0 {
+		t.Errorf("min of 100 and 40 = %s, want 40 USD", got)
+	}
+}
+
+func TestMoneyArithmeticAcrossCurrencies(t *testing.T) {
+	usd, kld := mustParseMoney(t, "100", "USD"), mustParseMoney(t, "100", "KLD")
+	if got, err := usd.Add(kld); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("100 USD + 100 KLD = %s, %v, want %v", got, err, ErrInvalidArgument)
+	}
+	if got, err := usd.Sub(kld); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("100 USD - 100 KLD = %s, %v, want %v", got, err, ErrInvalidArgument)
+	}
+	// The zero value has no currency, so it does not add up with an amount that has one
+	if got, err := (Money{}).Add(usd); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("zero value + 100 USD = %s, %v, want %v", got, err, ErrInvalidArgument)
+	}
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+type OfferState string
+
+const (
+	// OfferStatePending offers wait for the other party to accept, reject or counter them
+	OfferStatePending   OfferState = "pending"
+	OfferStateCountered OfferState = "countered"
+	OfferStateAccepted  OfferState = "accepted"
+	OfferStateRejected  OfferState = "rejected"
+	OfferStateExpired   OfferState = "expired"
+)
+
+// Offer is a price proposed for a listed item. A buyer opens the negotiation below the list price,
+// and each counter-offer replaces the pending offer with a new one proposed by the other party.
+type Offer struct {
+	ID       string `json:"id"`
+	ItemID   string `json:"item_id"`
+	BuyerID  string `json:"buyer_id"`
+	SellerID string `json:"seller_id"`
+	// ProposedBy is the buyer or the seller, the other party is the one who may respond
+	ProposedBy string     `json:"proposed_by"`
+	Amount     Money      `json:"amount"`
+	State      OfferState `json:"state"`
+	// ParentID is the offer this one counters, empty for the buyer's opening offer
+	ParentID  string    `json:"parent_id,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Respondent returns the party who may accept, reject or counter the offer
+func (o *Offer) Respondent() string {
+	if o.ProposedBy == o.BuyerID {
+		return o.SellerID
+	}
+	return o.BuyerID
+}
+
+// Open reports whether the offer still awaits a response at now
+func (o *Offer) Open(now time.Time) bool {
+	return o.State == OfferStatePending && now.Before(o.ExpiresAt)
+}
@@ -0,0 +1,26 @@
+package domain
+
+// TokenPoolType is the Firefly pool type
+type TokenPoolType string
+
+const (
+	// TokenPoolTypeNonFungible pools hold the NFTs of items
+	TokenPoolTypeNonFungible TokenPoolType = "nonfungible"
+	// TokenPoolTypeFungible pools hold the currency purchases are paid in
+	TokenPoolTypeFungible TokenPoolType = "fungible"
+)
+
+// TokenPool is a Firefly token pool that items can be minted into, or that purchases are paid in
+type TokenPool struct {
+	ID   string        `json:"id"`
+	Name string        `json:"name"`
+	Type TokenPoolType `json:"type"`
+	// Symbol is the token symbol of a fungible pool, which prices paid in it must be in
+	Symbol string `json:"symbol,omitempty"`
+	// Locator is the connector specific reference Firefly resolved the pool to
+	Locator string `json:"locator"`
+	// Address is the ERC721 or ERC20 contract address backing the pool
+	Address   string `json:"address"`
+	Connector string `json:"connector"`
+	State     string `json:"state"`
+}
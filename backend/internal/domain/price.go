@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// PriceChange is one entry of a listing's price history
+type PriceChange struct {
+	ID        string    `json:"id"`
+	ItemID    string    `json:"item_id"`
+	OldPrice  Money     `json:"old_price"`
+	NewPrice  Money     `json:"new_price"`
+	ChangedBy string    `json:"changed_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// ProvenanceKind is what happened to an NFT in a provenance entry
+type ProvenanceKind string
+
+const (
+	ProvenanceKindMint     ProvenanceKind = "mint"
+	ProvenanceKindListing  ProvenanceKind = "listing"
+	ProvenanceKindSale     ProvenanceKind = "sale"
+	ProvenanceKindTransfer ProvenanceKind = "transfer"
+	ProvenanceKindBurn     ProvenanceKind = "burn"
+)
+
+// ProvenanceEntry is one on-chain step in the life of an NFT
+type ProvenanceEntry struct {
+	Kind        ProvenanceKind `json:"kind"`
+	TxHash      string         `json:"tx_hash"`
+	BlockNumber uint64         `json:"block_number"`
+	// Timestamp is the time of the block the transaction was mined in
+	Timestamp time.Time `json:"timestamp"`
+	// From and To are the addresses the NFT moved between, or the seller and buyer of a listing or sale
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Price is the amount of a listing or sale in the payment token's base units
+	Price string `json:"price,omitempty"`
+}
+
+// ProvenanceItem is the off-chain record of the physical item an NFT stands for
+type ProvenanceItem struct {
+	ItemID    string `json:"item_id"`
+	Name      string `json:"item_name"`
+	CreatorID string `json:"creator_id"`
+}
+
+// Provenance is the ordered timeline of an NFT, oldest entry first
+type Provenance struct {
+	Pool string `json:"pool"`
+	// Contract is the address of the ERC721 contract backing the pool
+	Contract    string             `json:"contract"`
+	TokenIndex  string             `json:"token_index"`
+	Items       []ProvenanceItem   `json:"items"`
+	Entries     []*ProvenanceEntry `json:"entries"`
+	GeneratedAt time.Time          `json:"generated_at"`
+}
+
+// SignedProvenance is a provenance export anyone holding the public key can verify.
+// The signature is over Payload exactly as sent, which is the JSON encoded Provenance.
+type SignedProvenance struct {
+	Algorithm string `json:"algorithm"`
+	Payload   []byte `json:"payload"`
+	Signature []byte `json:"signature"`
+	PublicKey []byte `json:"public_key"`
+}
+
+// PublicKey verifies the signatures of provenance exports
+type PublicKey struct {
+	Algorithm string `json:"algorithm"`
+	Key       []byte `json:"public_key"`
+}
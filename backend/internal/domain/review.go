@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+	// maxReviewText is the most characters a review's text can have
+	maxReviewText = 2000
+)
+
+// Review is a buyer's rating of the seller of a completed purchase. Its content stays in the database,
+// and only its hash is anchored on chain.
+type Review struct {
+	ID       string `json:"id"`
+	SaleID   string `json:"sale_id"`
+	ItemID   string `json:"item_id"`
+	SellerID string `json:"seller_id"`
+	BuyerID  string `json:"buyer_id"`
+	Rating   int    `json:"rating"`
+	Text     string `json:"text"`
+	// Hash is the ContentHash the review was anchored with
+	Hash string `json:"hash"`
+	// AnchorID is the Firefly broadcast message that carries Hash
+	AnchorID  string    `json:"anchor_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r *Review) Validate() error {
+	if r.Rating < MinRating || r.Rating > MaxRating {
+		return fmt.Errorf("rating must be between %d and %d: %w", MinRating, MaxRating, ErrInvalidArgument)
+	}
+	if !utf8.ValidString(r.Text) || utf8.RuneCountInString(r.Text) > maxReviewText {
+		return fmt.Errorf("review text must be at most %d characters: %w", maxReviewText, ErrInvalidArgument)
+	}
+	if len(strings.TrimSpace(r.Text)) != len(r.Text) {
+		return fmt.Errorf("review text must not start or end with spaces: %w", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// reviewContent is what a review's hash covers. The ID is left out, as the review is anchored before it is stored.
+type reviewContent struct {
+	SaleID    string `json:"sale_id"`
+	ItemID    string `json:"item_id"`
+	SellerID  string `json:"seller_id"`
+	BuyerID   string `json:"buyer_id"`
+	Rating    int    `json:"rating"`
+	Text      string `json:"text"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ContentHash is the hex encoded SHA-256 of the JSON encoded review. CreatedAt must already be
+// truncated to the microseconds it is stored with, or the hash of the stored review will differ.
+func (r *Review) ContentHash() string {
+	b, _ := json.Marshal(reviewContent{
+		SaleID:    r.SaleID,
+		ItemID:    r.ItemID,
+		SellerID:  r.SellerID,
+		BuyerID:   r.BuyerID,
+		Rating:    r.Rating,
+		Text:      r.Text,
+		CreatedAt: r.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// ReviewAnchor is the record broadcast through Firefly for every review
+type ReviewAnchor struct {
+	SaleID string `json:"sale_id"`
+	Hash   string `json:"hash"`
+}
+
+// CheckedReview is a review with whether its content still hashes to what was anchored for it
+type CheckedReview struct {
+	*Review
+	// Intact is false when the stored review no longer hashes to its anchored hash
+	Intact bool `json:"intact"`
+}
+
+// Reputation sums up the reviews of a seller
+type Reputation struct {
+	SellerID string `json:"seller_id"`
+	// Score is the mean rating with every review counting half as much per half-life of age, 0 without reviews
+	Score   float64 `json:"score"`
+	Reviews int     `json:"reviews"`
+	// Ratings counts the reviews per rating, from 1 to 5 stars
+	Ratings [MaxRating]int `json:"ratings"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type ShipmentStatus string
+
+const (
+	ShipmentStatusInTransit ShipmentStatus = "in_transit"
+	// ShipmentStatusException is reported by the carrier when a parcel is delayed, damaged or lost
+	ShipmentStatusException ShipmentStatus = "exception"
+	ShipmentStatusDelivered ShipmentStatus = "delivered"
+	// ShipmentStatusConfirmed shipments have been confirmed by the buyer, which settles the sale
+	ShipmentStatusConfirmed ShipmentStatus = "confirmed"
+)
+
+// Shipment tracks a sold item from the seller handing it to a carrier until the buyer confirms receipt
+type Shipment struct {
+	ID             string         `json:"id"`
+	ItemID         string         `json:"item_id"`
+	SellerID       string         `json:"seller_id"`
+	BuyerID        string         `json:"buyer_id"`
+	Carrier        string         `json:"carrier"`
+	TrackingNumber string         `json:"tracking_number"`
+	Status         ShipmentStatus `json:"status"`
+	// DeliveredAt is when the carrier reported delivery
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TrackingUpdate is the latest status a carrier reports for a tracking number
+type TrackingUpdate struct {
+	Status      ShipmentStatus
+	DeliveredAt time.Time
+}
+
+// ShippingAddress is where the buyer wants a sold item delivered. It is personal data,
+// so it is only ever stored encrypted and shown to the seller of the sale.
+type ShippingAddress struct {
+	Name       string `json:"name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// maxAddressField keeps an encrypted address within its column
+const maxAddressField = 255
+
+// Validate checks that the fields a carrier needs are present, and that no field is oversized
+func (a *ShippingAddress) Validate() error {
+	for _, v := range []string{a.Name, a.Line1, a.Line2, a.City, a.Region, a.PostalCode, a.Country} {
+		if len(v) > maxAddressField {
+			return fmt.Errorf("shipping address fields must be at most %d bytes: %w", maxAddressField, ErrInvalidArgument)
+		}
+	}
+	required := []struct{ field, value string }{
+		{"name", a.Name}, {"line1", a.Line1}, {"city", a.City}, {"postal_code", a.PostalCode}, {"country", a.Country},
+	}
+	for _, r := range required {
+		if len(strings.TrimSpace(r.value)) == 0 {
+			return fmt.Errorf("shipping address has no %s: %w", r.field, ErrInvalidArgument)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ItemAction is something done to an item that moves it between states
+type ItemAction string
+
+const (
+	ItemActionList ItemAction = "list"
+	// ItemActionReprice keeps a listing listed at another price
+	ItemActionReprice  ItemAction = "reprice"
+	ItemActionPurchase ItemAction = "purchase"
+	ItemActionShip     ItemAction = "ship"
+	ItemActionReceive  ItemAction = "receive"
+	ItemActionCancel   ItemAction = "cancel"
+	ItemActionDelist   ItemAction = "delist"
+	ItemActionDispute  ItemAction = "dispute"
+	// ItemActionRefund and ItemActionRelease resolve a dispute. Only admins may resolve disputes,
+	// which the dispute service checks, as admins are not a property of the item.
+	ItemActionRefund  ItemAction = "refund"
+	ItemActionRelease ItemAction = "release"
+)
+
+// guard reports whether actor may take an action on item. who describes the users it lets through.
+type guard struct {
+	who   string
+	allow func(item *Item, actor string) bool
+}
+
+var (
+	// ownerOnly lets whoever holds the NFT list it: the buyer of a received item, otherwise the seller.
+	// Anyone may list a new item.
+	ownerOnly = guard{"the owner", func(item *Item, actor string) bool {
+		owner := item.SellerID
+		if item.State == ItemStateReceived {
+			owner = item.BuyerID
+		}
+		return len(owner) == 0 || owner == actor
+	}}
+	sellerOnly  = guard{"the seller", func(item *Item, actor string) bool { return item.SellerID == actor }}
+	buyerOnly   = guard{"the buyer", func(item *Item, actor string) bool { return item.BuyerID == actor }}
+	notSeller   = guard{"someone other than the seller", func(item *Item, actor string) bool { return item.SellerID != actor }}
+	partiesOnly = guard{"the seller or buyer", func(item *Item, actor string) bool { return item.SellerID == actor || item.BuyerID == actor }}
+)
+
+type itemTransition struct {
+	from  []ItemState
+	to    ItemState
+	event EventType
+	// guard is nil when the caller authorizes the action itself
+	guard *guard
+}
+
+// itemTransitions is the item state machine. An action not listed for a state is an invalid transition.
+var itemTransitions = map[ItemAction]itemTransition{
+	ItemActionList: {
+		from:  []ItemState{ItemStateUnspecified, ItemStateCancelled, ItemStateReceived, ItemStateRefunded},
+		to:    ItemStateListed,
+		event: EventItemListed,
+		guard: &ownerOnly,
+	},
+	ItemActionReprice: {
+		from:  []ItemState{ItemStateListed},
+		to:    ItemStateListed,
+		event: EventItemPriceChanged,
+		guard: &sellerOnly,
+	},
+	ItemActionPurchase: {
+		from:  []ItemState{ItemStateListed},
+		to:    ItemStateSold,
+		event: EventItemSold,
+		guard: &notSeller,
+	},
+	ItemActionShip: {
+		from:  []ItemState{ItemStateSold},
+		to:    ItemStateShipped,
+		event: EventItemShipped,
+		guard: &sellerOnly,
+	},
+	ItemActionReceive: {
+		from:  []ItemState{ItemStateShipped},
+		to:    ItemStateReceived,
+		event: EventItemReceived,
+		guard: &buyerOnly,
+	},
+	ItemActionCancel: {
+		from:  []ItemState{ItemStateListed, ItemStateSold},
+		to:    ItemStateCancelled,
+		event: EventItemCancelled,
+		guard: &partiesOnly,
+	},
+	ItemActionDelist: {
+		from:  []ItemState{ItemStateListed},
+		to:    ItemStateCancelled,
+		event: EventItemCancelled,
+		guard: &sellerOnly,
+	},
+	// Received items can only be disputed within the dispute window, which the item service checks
+	ItemActionDispute: {
+		from:  []ItemState{ItemStateSold, ItemStateShipped, ItemStateReceived},
+		to:    ItemStateDisputed,
+		event: EventItemDisputed,
+		guard: &buyerOnly,
+	},
+	ItemActionRefund: {
+		from:  []ItemState{ItemStateDisputed},
+		to:    ItemStateRefunded,
+		event: EventItemRefunded,
+	},
+	ItemActionRelease: {
+		from:  []ItemState{ItemStateDisputed},
+		to:    ItemStateReceived,
+		event: EventItemReceived,
+	},
+}
+
+// Transition is an allowed move of an item from one state to another
+type Transition struct {
+	Action ItemAction
+	From   ItemState
+	To     ItemState
+	// Event is published once the transition has been applied
+	Event EventType
+}
+
+// Transition checks that actor may take action on the item in its current state. The transition is
+// returned without being applied, so that the caller can first do the work it stands for, such as
+// calling the contract, and only Apply it once that has succeeded.
+func (item *Item) Transition(action ItemAction, actor string) (*Transition, error) {
+	t, ok := itemTransitions[action]
+	if !ok {
+		return nil, fmt.Errorf("unknown item action (%s): %w", action, ErrInvalidTransition)
+	}
+	if !slices.Contains(t.from, item.State) {
+		return nil, fmt.Errorf("item (%s) cannot %s when %s: %w", item.ID, action, item.State, ErrInvalidTransition)
+	}
+	if t.guard != nil && !t.guard.allow(item, actor) {
+		return nil, fmt.Errorf("only %s can %s item (%s): %w", t.guard.who, action, item.ID, ErrForbidden)
+	}
+	return &Transition{Action: action, From: item.State, To: t.to, Event: t.event}, nil
+}
+
+// Apply moves item to the target state. The item must still be in the state the transition was checked in.
+func (t *Transition) Apply(item *Item) error {
+	if item.State != t.From {
+		return fmt.Errorf("item (%s) moved from %s to %s before it could %s: %w", item.ID, t.From, item.State, t.Action, ErrInvalidTransition)
+	}
+	item.State = t.To
+	return nil
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+	"testing/quick"
+)
+
+var testUsers = []string{"", "alice", "bob", "carol"}
+
+// transitionCase is an item in any state, including unknown ones, and an actor taking any action on it
+type transitionCase struct {
+	Item   Item
+	Action ItemAction
+	Actor  string
+}
+
+func (transitionCase) Generate(r *rand.Rand, _ int) reflect.Value {
+	actions := []ItemAction{"", "bogus"}
+	for action := range itemTransitions {
+		actions = append(actions, action)
+	}
+	slices.Sort(actions)
+	c := transitionCase{
+		Item: Item{
+			ID:       "item",
+			State:    randomState(r),
+			SellerID: testUsers[r.Intn(len(testUsers))],
+			BuyerID:  testUsers[r.Intn(len(testUsers))],
+		},
+		Action: actions[r.Intn(len(actions))],
+		Actor:  testUsers[r.Intn(len(testUsers))],
+	}
+	return reflect.ValueOf(c)
+}
+
+// randomState returns a known state most of the time, and otherwise one just outside the known range
+func randomState(r *rand.Rand) ItemState {
+	return ItemState(r.Intn(len(itemStateNames)+2) - 1)
+}
+
+func TestTransitionProperty(t *testing.T) {
+	f := func(c transitionCase) bool {
+		before := c.Item
+		tr, err := c.Item.Transition(c.Action, c.Actor)
+		if !reflect.DeepEqual(c.Item, before) {
+			t.Logf("Transition changed the item: %+v", c)
+			return false
+		}
+		if err != nil {
+			if tr != nil {
+				t.Logf("Transition returned both %+v and %v", tr, err)
+				return false
+			}
+			if !errors.Is(err, ErrInvalidTransition) && !errors.Is(err, ErrForbidden) {
+				t.Logf("Transition(%s, %q) on %s: unexpected error %v", c.Action, c.Actor, c.Item.State, err)
+				return false
+			}
+			// Only a guard forbids, so the action must be valid in the state
+			if want, ok := itemTransitions[c.Action]; errors.Is(err, ErrForbidden) && (!ok || !slices.Contains(want.from, c.Item.State)) {
+				t.Logf("Transition(%s, %q) on %s: forbidden instead of invalid", c.Action, c.Actor, c.Item.State)
+				return false
+			}
+			return true
+		}
+		want, ok := itemTransitions[c.Action]
+		if !ok || !slices.Contains(want.from, c.Item.State) {
+			t.Logf("Transition(%s, %q) allowed from %s", c.Action, c.Actor, c.Item.State)
+			return false
+		}
+		if want.guard != nil && !want.guard.allow(&c.Item, c.Actor) {
+			t.Logf("Transition(%s, %q) let through an actor its guard rejects: %+v", c.Action, c.Actor, c.Item)
+			return false
+		}
+		if _, known := itemStateNames[tr.To]; !known || tr.To != want.to || tr.From != c.Item.State || tr.Event != want.event {
+			t.Logf("Transition(%s, %q) on %s = %+v", c.Action, c.Actor, c.Item.State, tr)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestApplyRefusesMovedItemProperty(t *testing.T) {
+	f := func(c transitionCase, seed int64) bool {
+		tr, err := c.Item.Transition(c.Action, c.Actor)
+		if err != nil {
+			return true
+		}
+		r := rand.New(rand.NewSource(seed))
+		moved := c.Item
+		for moved.State == tr.From {
+			moved.State = randomState(r)
+		}
+		state := moved.State
+		if err := tr.Apply(&moved); !errors.Is(err, ErrInvalidTransition) || moved.State != state {
+			t.Logf("Apply of %+v on an item moved to %s: err %v, state %s", tr, state, err, moved.State)
+			return false
+		}
+
+		// The item the transition was checked on still takes it
+		if err := tr.Apply(&c.Item); err != nil || c.Item.State != tr.To {
+			t.Logf("Apply of %+v: err %v, state %s", tr, err, c.Item.State)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseItemStateProperty(t *testing.T) {
+	f := func(n int64) bool {
+		s := ItemState(n)
+		_, known := itemStateNames[s]
+
+		byName, err := ParseItemState(s.String())
+		if known != (err == nil) || (known && byName != s) {
+			t.Logf("ParseItemState(%q) = %v, %v", s.String(), byName, err)
+			return false
+		}
+		if err != nil && !errors.Is(err, ErrInvalidArgument) {
+			t.Logf("ParseItemState(%q): unexpected error %v", s.String(), err)
+			return false
+		}
+
+		// Rows written before states were stored by name hold the number
+		byNumber, err := ParseItemState(strconv.FormatInt(n, 10))
+		if known != (err == nil) || (known && byNumber != s) {
+			t.Logf("ParseItemState(%q) = %v, %v", strconv.FormatInt(n, 10), byNumber, err)
+			return false
+		}
+		return true
+	}
+	// Random int64s are almost never known states, so the known range is checked as well
+	values := func(args []reflect.Value, r *rand.Rand) {
+		n := r.Int63()
+		switch r.Intn(3) {
+		case 0:
+			n = -n
+		case 1:
+			n = int64(randomState(r))
+		}
+		args[0] = reflect.ValueOf(n)
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 2000, Values: values}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// VerificationFlag is a disagreement between the chain and the marketplace's records found by a verification
+type VerificationFlag string
+
+const (
+	// VerificationFlagNotMinted tokens have no holder on chain
+	VerificationFlagNotMinted VerificationFlag = "not_minted"
+	// VerificationFlagNoRecord tokens have no item in the marketplace
+	VerificationFlagNoRecord VerificationFlag = "no_record"
+	// VerificationFlagDuplicateRecord tokens back more than one item
+	VerificationFlagDuplicateRecord VerificationFlag = "duplicate_record"
+	// VerificationFlagOwnerMismatch tokens are held by someone the item's state says should not hold them
+	VerificationFlagOwnerMismatch VerificationFlag = "owner_mismatch"
+	// VerificationFlagMetadataMismatch tokens have a URI that does not carry the hash of the item's record
+	VerificationFlagMetadataMismatch VerificationFlag = "metadata_mismatch"
+)
+
+// TokenHolder is who holds an NFT on chain
+type TokenHolder struct {
+	Owner string
+	// URI is the token URI set at mint, empty when none was
+	URI string
+}
+
+// ItemMetadata is the part of an item's record that identifies the physical item, which its NFT vouches for.
+// It leaves out the token index, which the pool only assigns once the NFT is minted with the metadata's URI.
+type ItemMetadata struct {
+	ItemID    string `json:"item_id"`
+	Name      string `json:"item_name"`
+	CreatorID string `json:"creator_id"`
+	Pool      string `json:"pool"`
+	// Images are the hashes of the item's photos, so that swapping a photo changes the metadata's hash
+	Images []string `json:"images,omitempty"`
+}
+
+// Metadata returns the metadata of the item with its NFT in the named pool
+func (item *Item) Metadata(pool string) ItemMetadata {
+	return ItemMetadata{
+		ItemID:    item.ID,
+		Name:      item.Name,
+		CreatorID: item.CreatorID,
+		Pool:      pool,
+		Images:    item.Images,
+	}
+}
+
+// Hash is the hex encoded SHA-256 of the JSON encoded metadata
+func (m ItemMetadata) Hash() string {
+	b, _ := json.Marshal(m)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// URI is the token URI an NFT is minted with for the metadata, which carries its hash
+func (m ItemMetadata) URI() string {
+	return "urn:sha256:" + m.Hash()
+}
+
+// Verification is the public answer to whether an NFT is what the marketplace says it is
+type Verification struct {
+	Pool       string `json:"pool"`
+	Contract   string `json:"contract"`
+	TokenIndex string `json:"token_index"`
+	// Owner is the address holding the NFT on chain
+	Owner    string `json:"owner,omitempty"`
+	TokenURI string `json:"token_uri,omitempty"`
+	ItemID   string `json:"item_id,omitempty"`
+	ItemName string `json:"item_name,omitempty"`
+	// MetadataHash is the hash of the item's record, which a token URI minted for it must contain
+	MetadataHash string             `json:"metadata_hash,omitempty"`
+	Matches      bool               `json:"matches"`
+	Flags        []VerificationFlag `json:"flags"`
+	CheckedAt    time.Time          `json:"checked_at"`
+}
+
+// ParseVerificationCode reads the pool and token index from the payload of a QR code printed on an item.
+// The payload is the verification URL itself, with pool and token query parameters, or a bare token index.
+func ParseVerificationCode(payload string) (pool, tokenIndex string, err error) {
+	payload = strings.TrimSpace(payload)
+	if u, err := url.Parse(payload); err == nil && len(u.Scheme) > 0 {
+		pool, tokenIndex = u.Query().Get("pool"), u.Query().Get("token")
+	} else {
+		tokenIndex = payload
+	}
+	if n, ok := new(big.Int).SetString(tokenIndex, 10); !ok || n.Sign() < 0 {
+		return "", "", fmt.Errorf("code (%s) has no token index: %w", payload, ErrInvalidArgument)
+	}
+	return pool, tokenIndex, nil
+}
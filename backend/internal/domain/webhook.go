@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+type WebhookSubscription struct {
+	ID         string      `json:"id"`
+	UserID     string      `json:"user_id"`
+	URL        string      `json:"url"`
+	Secret     string      `json:"secret,omitempty"`
+	EventTypes []EventType `json:"event_types"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type WebhookDeliveryState string
+
+const (
+	WebhookDeliveryStatePending    WebhookDeliveryState = "pending"
+	WebhookDeliveryStateDelivered  WebhookDeliveryState = "delivered"
+	WebhookDeliveryStateDeadLetter WebhookDeliveryState = "dead_letter"
+)
+
+type WebhookDelivery struct {
+	ID             string               `json:"id"`
+	SubscriptionID string               `json:"subscription_id"`
+	EventType      EventType            `json:"event_type"`
+	Payload        []byte               `json:"-"`
+	State          WebhookDeliveryState `json:"state"`
+	Attempts       int                  `json:"attempts"`
+	NextAttemptAt  time.Time            `json:"next_attempt_at"`
+	LastError      string               `json:"last_error,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+}
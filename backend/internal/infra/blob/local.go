@@ -0,0 +1,79 @@
+package blob
+
+import (
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalName is the storage name the local filesystem adapter is selected with
+const LocalName = "local"
+
+// Local keeps blobs as files in a directory, spread over subdirectories by the first bytes of their key
+type Local struct {
+	dir string
+}
+
+func NewLocal(dir string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll (%s): %w", dir, err)
+	}
+	return &Local{dir: dir}, nil
+}
+
+// Put writes data under key. Keys are content hashes, so a blob that is already stored is left as it is.
+func (l *Local) Put(ctx context.Context, key string, data []byte) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("os.MkdirAll (%s): %w", filepath.Dir(path), err)
+	}
+	// Written aside and renamed, so that a blob is never read half written
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("os.CreateTemp in (%s): %w", filepath.Dir(path), err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write blob (%s): %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close blob (%s): %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("os.Rename to (%s): %w", path, err)
+	}
+	return nil
+}
+
+func (l *Local) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("blob (%s): %w", key, domain.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("os.ReadFile (%s): %w", path, err)
+	}
+	return data, nil
+}
+
+// path rejects keys that could point outside of the directory
+func (l *Local) path(key string) (string, error) {
+	if err := domain.ValidateMediaHash(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(l.dir, key[:2], key[2:4], key), nil
+}
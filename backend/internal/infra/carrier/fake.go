@@ -0,0 +1,51 @@
+package carrier
+
+import (
+	"backend/internal/domain"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeName is the carrier name the fake adapter is registered under
+const FakeName = "fake"
+
+// Fake is a local carrier for development and tests. A parcel is in transit from the first time
+// it is tracked until deliverAfter has passed, and then delivered. Tracking numbers ending in
+// "-LOST" stay in exception instead.
+type Fake struct {
+	deliverAfter time.Duration
+	now          func() time.Time
+
+	mu        sync.Mutex
+	firstSeen map[string]time.Time
+}
+
+func NewFake(deliverAfter time.Duration) *Fake {
+	return &Fake{
+		deliverAfter: deliverAfter,
+		now:          time.Now,
+		firstSeen:    make(map[string]time.Time),
+	}
+}
+
+func (f *Fake) Track(ctx context.Context, trackingNumber string) (*domain.TrackingUpdate, error) {
+	if strings.HasSuffix(strings.ToUpper(trackingNumber), "-LOST") {
+		return &domain.TrackingUpdate{Status: domain.ShipmentStatusException}, nil
+	}
+
+	f.mu.Lock()
+	seen, ok := f.firstSeen[trackingNumber]
+	if !ok {
+		seen = f.now()
+		f.firstSeen[trackingNumber] = seen
+	}
+	f.mu.Unlock()
+
+	deliveredAt := seen.Add(f.deliverAfter)
+	if f.now().Before(deliveredAt) {
+		return &domain.TrackingUpdate{Status: domain.ShipmentStatusInTransit}, nil
+	}
+	return &domain.TrackingUpdate{Status: domain.ShipmentStatusDelivered, DeliveredAt: deliveredAt.UTC()}, nil
+}
@@ -0,0 +1,95 @@
+package carrier
+
+import (
+	"backend/internal/domain"
+	"context"
+	"testing"
+	"time"
+)
+
+// clock is a time the test moves forward by hand
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func newTestFake(deliverAfter time.Duration) (*Fake, *clock) {
+	c := &clock{t: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	f := NewFake(deliverAfter)
+	f.now = c.now
+	return f, c
+}
+
+func mustTrack(t *testing.T, f *Fake, trackingNumber string) *domain.TrackingUpdate {
+	t.Helper()
+	update, err := f.Track(context.Background(), trackingNumber)
+	if err != nil {
+		t.Fatalf("Track(%q): %v", trackingNumber, err)
+	}
+	return update
+}
+
+func TestFakeDelivers(t *testing.T) {
+	f, c := newTestFake(time.Hour)
+	firstSeen := c.t
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		want    domain.TrackingUpdate
+	}{
+		{name: "first tracked", elapsed: 0, want: domain.TrackingUpdate{Status: domain.ShipmentStatusInTransit}},
+		{name: "before the delay", elapsed: time.Hour - time.Second, want: domain.TrackingUpdate{Status: domain.ShipmentStatusInTransit}},
+		{name: "at the delay", elapsed: time.Hour, want: domain.TrackingUpdate{Status: domain.ShipmentStatusDelivered, DeliveredAt: firstSeen.Add(time.Hour)}},
+		{name: "long after", elapsed: 48 * time.Hour, want: domain.TrackingUpdate{Status: domain.ShipmentStatusDelivered, DeliveredAt: firstSeen.Add(time.Hour)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.t = firstSeen.Add(tt.elapsed)
+			if got := mustTrack(t, f, "TRACK-1"); *got != tt.want {
+				t.Errorf("Track after %s = %+v, want %+v", tt.elapsed, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFakeTracksParcelsApart(t *testing.T) {
+	f, c := newTestFake(time.Hour)
+	start := c.t
+	mustTrack(t, f, "TRACK-1")
+
+	c.t = start.Add(30 * time.Minute)
+	mustTrack(t, f, "TRACK-2")
+
+	c.t = start.Add(time.Hour)
+	if got := mustTrack(t, f, "TRACK-1"); got.Status != domain.ShipmentStatusDelivered {
+		t.Errorf("TRACK-1 status = %s, want %s", got.Status, domain.ShipmentStatusDelivered)
+	}
+	if got := mustTrack(t, f, "TRACK-2"); got.Status != domain.ShipmentStatusInTransit {
+		t.Errorf("TRACK-2 status = %s, want %s", got.Status, domain.ShipmentStatusInTransit)
+	}
+
+	c.t = start.Add(90 * time.Minute)
+	got := mustTrack(t, f, "TRACK-2")
+	if want := start.Add(90 * time.Minute); got.Status != domain.ShipmentStatusDelivered || !got.DeliveredAt.Equal(want) {
+		t.Errorf("TRACK-2 = %+v, want delivered at %s", *got, want)
+	}
+}
+
+func TestFakeLosesParcels(t *testing.T) {
+	f, c := newTestFake(time.Hour)
+	for _, trackingNumber := range []string{"TRACK-LOST", "track-lost", "1-Lost"} {
+		t.Run(trackingNumber, func(t *testing.T) {
+			if got := mustTrack(t, f, trackingNumber); got.Status != domain.ShipmentStatusException {
+				t.Errorf("first Track status = %s, want %s", got.Status, domain.ShipmentStatusException)
+			}
+			c.t = c.t.Add(24 * time.Hour)
+			if got := mustTrack(t, f, trackingNumber); got.Status != domain.ShipmentStatusException {
+				t.Errorf("Track after a day status = %s, want %s", got.Status, domain.ShipmentStatusException)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+)
+
+// KeySize is the length of an AES-256 key
+const KeySize = 32
+
+// AESGCM encrypts personal data before it is stored, such as shipping addresses
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+func New(key []byte) (*AESGCM, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes.NewCipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cipher.NewGCM: %w", err)
+	}
+	return &AESGCM{aead: aead}, nil
+}
+
+// Seal encrypts plaintext under a random nonce, which prefixes the result.
+// The same aad must be given to Open, binding the ciphertext to the row it is stored in.
+func (e *AESGCM) Seal(plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, e.aead.NonceSize(), e.aead.NonceSize()+len(plaintext)+e.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("rand.Read: %w", err)
+	}
+	return e.aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// Open decrypts and authenticates a ciphertext returned by Seal
+func (e *AESGCM) Open(ciphertext, aad []byte) ([]byte, error) {
+	n := e.aead.NonceSize()
+	if len(ciphertext) < n {
+		return nil, fmt.Errorf("ciphertext is shorter than its nonce")
+	}
+	plaintext, err := e.aead.Open(nil, ciphertext[:n], ciphertext[n:], aad)
+	if err != nil {
+		return nil, fmt.Errorf("e.aead.Open: %w", err)
+	}
+	return plaintext, nil
+}
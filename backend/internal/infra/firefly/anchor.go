@@ -0,0 +1,72 @@
+package firefly
+
+import (
+	"backend/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const (
+	broadcastPath = "messages/broadcast"
+	messagesPath  = "messages"
+	// reviewTopic orders review anchors among themselves, apart from other broadcasts
+	reviewTopic = "reviews"
+)
+
+type broadcastRequest struct {
+	Header broadcastHeader `json:"header"`
+	Data   []messageData   `json:"data"`
+}
+
+type broadcastHeader struct {
+	Tag    string   `json:"tag"`
+	Topics []string `json:"topics"`
+}
+
+type messageData struct {
+	Value json.RawMessage `json:"value"`
+}
+
+type messageResponse struct {
+	Header struct {
+		ID string `json:"id"`
+	} `json:"header"`
+}
+
+// AnchorReview broadcasts the review's hash from the platform's node. Firefly pins the batch the message
+// is sent in on chain, so the hash cannot be changed afterwards. It returns the message ID.
+func (c *Client) AnchorReview(ctx context.Context, anchor *domain.ReviewAnchor) (string, error) {
+	value, err := json.Marshal(anchor)
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal type ReviewAnchor: %w", err)
+	}
+	req := broadcastRequest{
+		Header: broadcastHeader{Tag: "review", Topics: []string{reviewTopic}},
+		Data:   []messageData{{Value: value}},
+	}
+	var res messageResponse
+	u := c.port[defaultUserID].JoinPath(broadcastPath)
+	if err := c.doJSON(ctx, http.MethodPost, u.String(), req, &res); err != nil {
+		return "", fmt.Errorf("broadcast anchor of sale (%s): %w", anchor.SaleID, err)
+	}
+	return res.Header.ID, nil
+}
+
+// ReviewAnchor reads back the anchor broadcast in a message
+func (c *Client) ReviewAnchor(ctx context.Context, messageID string) (*domain.ReviewAnchor, error) {
+	var data []messageData
+	u := c.port[defaultUserID].JoinPath(messagesPath, messageID, "data")
+	if err := c.doJSON(ctx, http.MethodGet, u.String(), nil, &data); err != nil {
+		return nil, fmt.Errorf("get data of message (%s): %w", messageID, err)
+	}
+	if len(data) != 1 {
+		return nil, fmt.Errorf("message (%s) has %d data items, not one anchor: %w", messageID, len(data), domain.ErrNotFound)
+	}
+	var anchor domain.ReviewAnchor
+	if err := json.Unmarshal(data[0].Value, &anchor); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal anchor in message (%s): %w", messageID, err)
+	}
+	return &anchor, nil
+}
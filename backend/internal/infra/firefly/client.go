@@ -11,12 +11,18 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"strconv"
+	"sync"
 )
 
 type Client struct {
 	httpClient *http.Client
 	port       uidToHTTPPort
+
+	mu          sync.RWMutex
+	pools       map[string]*domain.TokenPool
+	defaultPool string
+	// keys caches the signing key of every user's Firefly node
+	keys map[string]string
 }
 
 type uidToHTTPPort map[string]*url.URL
@@ -24,6 +30,8 @@ type uidToHTTPPort map[string]*url.URL
 func New(u1, u2, u3 *url.URL, httpClient *http.Client) *Client {
 	return &Client{
 		httpClient: httpClient,
+		pools:      make(map[string]*domain.TokenPool),
+		keys:       make(map[string]string),
 		// For now, we use fixed userID
 		port: uidToHTTPPort{
 			"1": u1,
@@ -34,7 +42,6 @@ func New(u1, u2, u3 *url.URL, httpClient *http.Client) *Client {
 }
 
 const (
-	createPoolPath        = "tokens/pools"
 	mintTokenPath         = "tokens/mint"
 	approveTokenPath      = "tokens/approvals"
 	applicationJsonHeader = "application/json"
@@ -42,40 +49,14 @@ const (
 	deployContractPath = "contracts/deploy"
 	getTransactionPath = "transactions"
 
-	buyNFTPath       = "apis/marketplace/invoke/buyNFT"
-	nftPoolName      = "kaleido"
-	nftPoolID        = "0xd9d2f32fecdbcaa40b48b03132dc1023fa63d171"
 	nftDefaultAmount = "1"
-	nftType          = "nonfungible"
 	defaultUserID    = "1"
 )
 
-type createPoolRequest struct {
-	PoolName string `json:"name"`
-	PoolType string `json:"type"`
-}
-
-func (c *Client) CreatePool(_ context.Context) error {
-	// For now, we default to using the user ID 1's port number for token pool creation
-	p := c.port[defaultUserID].JoinPath(createPoolPath)
-	req := createPoolRequest{
-		PoolName: nftPoolName,
-		PoolType: nftType,
-	}
-	b, err := json.Marshal(req)
-	if err != nil {
-		return fmt.Errorf("json.Marshal type createPoolRequest: %w", err)
-	}
-	if _, err = c.httpClient.Post(p.String(), applicationJsonHeader, bytes.NewBuffer(b)); err != nil {
-		return fmt.Errorf("c.httpClient.Post to (%s): %w", p, err)
-	}
-	return nil
-}
-
 type deploySmartContractRequest struct {
 	Contract       string                     `json:"contract"`
 	Definition     contracts.SmartContractABI `json:"definition"`
-	Input          []string                   `json:"input"`
+	Input          []any                      `json:"input"`
 	IdempotencyKey string                     `json:"idempotencyKey"`
 }
 
@@ -84,9 +65,15 @@ type deploySmartContractResponse struct {
 }
 
 func (c *Client) DeploySmartContract(ctx context.Context, item *domain.Item) (string, error) {
-	var input []string
+	pool, err := c.Pool(item.PoolName)
+	if err != nil {
+		return "", err
+	}
 	// TODO: Add logic when NFT data is empty
-	input = append(input, nftPoolID, item.NFTID, strconv.Itoa(int(item.Price)))
+	input, err := contracts.GetMarketplace().Constructor.Inputs.Pack(pool.Address, item.NFTID, item.Price.Uint256())
+	if err != nil {
+		return "", fmt.Errorf("constructor inputs for item (%s): %w", item.ID, err)
+	}
 	req := deploySmartContractRequest{
 		Contract:   contracts.GetMarketplaceBin(),
 		Definition: contracts.GetMarketplaceABI(),
@@ -154,7 +141,13 @@ type approveTokenTransferRequest struct {
 	Pool string `json:"pool"`
 }
 
+// ApproveTokenTransfer lets the item's contract move its NFT on the caller's behalf. It waits for the
+// approval to be confirmed, so the contract can move the NFT in the next transaction.
 func (c *Client) ApproveTokenTransfer(ctx context.Context, item *domain.Item) error {
+	pool, err := c.Pool(item.PoolName)
+	if err != nil {
+		return err
+	}
 	req := approveTokenTransferRequest{
 		Operator: item.SmartContractAddress,
 		Config: struct {
@@ -162,16 +155,44 @@ func (c *Client) ApproveTokenTransfer(ctx context.Context, item *domain.Item) er
 		}{
 			TokenID: item.NFTID,
 		},
-		Pool: nftPoolName,
+		Pool: pool.Name,
 	}
-	b, err := json.Marshal(req)
+
+	u := c.port[utils.FromContext(ctx)].JoinPath(approveTokenPath)
+	u.RawQuery = "confirm=true"
+	if err := c.doJSON(ctx, http.MethodPost, u.String(), req, nil); err != nil {
+		return fmt.Errorf("approve (%s) for token (%s): %w", item.SmartContractAddress, item.NFTID, err)
+	}
+	return nil
+}
+
+type revokeTokenTransferRequest struct {
+	Operator string `json:"operator"`
+	Approved bool   `json:"approved"`
+	Config   struct {
+		TokenID string `json:"tokenIndex"`
+	} `json:"config"`
+	Pool string `json:"pool"`
+}
+
+// RevokeTokenTransfer withdraws the approval granted by ApproveTokenTransfer, so the item's
+// contract can no longer move the NFT. It waits for the revocation to be confirmed.
+func (c *Client) RevokeTokenTransfer(ctx context.Context, item *domain.Item) error {
+	pool, err := c.Pool(item.PoolName)
 	if err != nil {
-		return fmt.Errorf("json.Marshal type approveTokenTransferRequest: %w", err)
+		return err
 	}
+	req := revokeTokenTransferRequest{
+		Operator: item.SmartContractAddress,
+		Approved: false,
+		Pool:     pool.Name,
+	}
+	req.Config.TokenID = item.NFTID
 
 	u := c.port[utils.FromContext(ctx)].JoinPath(approveTokenPath)
-	if _, err := c.httpClient.Post(u.String(), applicationJsonHeader, bytes.NewReader(b)); err != nil {
-		return fmt.Errorf("c.httpClient.Post to (%s): %w", u.String(), err)
+	u.RawQuery = "confirm=true"
+	if err := c.doJSON(ctx, http.MethodPost, u.String(), req, nil); err != nil {
+		return fmt.Errorf("revoke approval of (%s) for token (%s): %w", item.SmartContractAddress, item.NFTID, err)
 	}
 	return nil
 }
@@ -179,16 +200,23 @@ func (c *Client) ApproveTokenTransfer(ctx context.Context, item *domain.Item) er
 type mintTokenRequest struct {
 	Pool   string `json:"pool"`
 	Amount string `json:"amount"`
+	URI    string `json:"uri,omitempty"`
 }
 
 type mintTokenResponse struct {
 	TokenIndex string `json:"tokenIndex"`
 }
 
-func (c *Client) MintToken(ctx context.Context) (string, error) {
+// MintToken mints an NFT in the named pool with the token URI, and returns its token index
+func (c *Client) MintToken(ctx context.Context, poolName, uri string) (string, error) {
+	pool, err := c.Pool(poolName)
+	if err != nil {
+		return "", err
+	}
 	req := mintTokenRequest{
-		Pool:   nftPoolName,
+		Pool:   pool.Name,
 		Amount: nftDefaultAmount,
+		URI:    uri,
 	}
 	b, err := json.Marshal(req)
 	if err != nil {
@@ -212,22 +240,9 @@ func (c *Client) MintToken(ctx context.Context) (string, error) {
 	return res.TokenIndex, nil
 }
 
-type buyNFTLocation struct {
-	Location struct {
-		ContractAddress string `json:"address"`
-	} `json:"location"`
-}
-
 func (c *Client) BuyNFT(ctx context.Context, contractAddress string) error {
-	req := buyNFTLocation{Location: location{ContractAddress: contractAddress}}
-	b, err := json.Marshal(req)
-	if err != nil {
-		return fmt.Errorf("json.Marshal type location: %w", err)
-	}
-
-	u := c.port[utils.FromContext(ctx)].JoinPath(buyNFTPath)
-	if _, err := c.httpClient.Post(u.String(), applicationJsonHeader, bytes.NewReader(b)); err != nil {
-		return fmt.Errorf("c.httpClient.Post to (%s): %w", u.String(), err)
+	if _, err := c.Marketplace().BuyNFT(ctx, contractAddress); err != nil {
+		return fmt.Errorf("c.Marketplace().BuyNFT on (%s): %w", contractAddress, err)
 	}
 	return nil
 }
@@ -0,0 +1,123 @@
+package firefly
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+//go:generate go run backend/cmd/abigen -abi ../../../contracts/Marketplace.abi -type Marketplace -api marketplace -pkg firefly -out marketplace_gen.go
+//go:generate go run backend/cmd/abigen -abi ../../../contracts/SharedMarketplace.abi -type SharedMarketplace -api sharedmarketplace -pkg firefly -out shared_marketplace_gen.go
+
+const (
+	contractAPIPath = "apis"
+	invokePath      = "invoke"
+	queryPath       = "query"
+)
+
+type contractRequest struct {
+	Location location       `json:"location"`
+	Input    map[string]any `json:"input"`
+}
+
+const operationSucceeded = "Succeeded"
+
+// invokeContractResponse is the Firefly operation that sent the transaction
+type invokeContractResponse struct {
+	Tx     string `json:"tx"`
+	Status string `json:"status"`
+	Error  string `json:"error"`
+}
+
+// invokeContract sends a transaction calling method through the named contract API, and returns its transaction ID.
+// It waits for the transaction to be mined, and fails when it reverted, so callers only act on calls that took effect.
+func (c *Client) invokeContract(ctx context.Context, api, method, contractAddress string, input map[string]any) (string, error) {
+	u := c.port[utils.FromContext(ctx)].JoinPath(contractAPIPath, api, invokePath, method)
+	u.RawQuery = "confirm=true"
+	body, err := c.postContract(ctx, u.String(), contractAddress, input)
+	if err != nil {
+		return "", err
+	}
+	var res invokeContractResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return "", fmt.Errorf("json.Unmarshal on response from (%s): %w", u.String(), err)
+	}
+	if res.Status != operationSucceeded {
+		return "", fmt.Errorf("%s on (%s) in transaction (%s) is %s: %s", method, contractAddress, res.Tx, res.Status, res.Error)
+	}
+	return res.Tx, nil
+}
+
+// queryContract calls a read-only method through the named contract API, and returns its outputs keyed by name
+func (c *Client) queryContract(ctx context.Context, api, method, contractAddress string, input map[string]any) (map[string]any, error) {
+	u := c.port[utils.FromContext(ctx)].JoinPath(contractAPIPath, api, queryPath, method)
+	body, err := c.postContract(ctx, u.String(), contractAddress, input)
+	if err != nil {
+		return nil, err
+	}
+	var res map[string]any
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal on response from (%s): %w", u.String(), err)
+	}
+	return res, nil
+}
+
+func (c *Client) postContract(ctx context.Context, u, contractAddress string, input map[string]any) ([]byte, error) {
+	req := contractRequest{
+		Location: location{ContractAddress: contractAddress},
+		Input:    input,
+	}
+	var body json.RawMessage
+	if err := c.doJSON(ctx, http.MethodPost, u, req, &body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// doJSON sends in as the JSON request body when set, and decodes the response body into out when set.
+// A 404 response is reported as domain.ErrNotFound.
+func (c *Client) doJSON(ctx context.Context, method, u string, in, out any) error {
+	var reqBody io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("json.Marshal type %T: %w", in, err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext to (%s): %w", u, err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", applicationJsonHeader)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("c.httpClient.Do to (%s): %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("io.ReadAll on response from (%s): %w", u, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s (%s): %w", method, u, domain.ErrNotFound)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code (%d) from %s (%s): %s", resp.StatusCode, method, u, body)
+	}
+	if out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("json.Unmarshal on response from (%s): %w", u, err)
+		}
+	}
+	return nil
+}
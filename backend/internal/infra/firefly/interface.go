@@ -0,0 +1,251 @@
+package firefly
+
+import (
+	"backend/contracts"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	contractInterfacesPath = "contracts/interfaces"
+	ffiVersion             = "1.2.0"
+)
+
+// ContractAPI describes a Firefly contract API backed by an embedded ABI
+type ContractAPI struct {
+	Name string
+	ABI  *contracts.ABI
+	// Location is the contract address the API is bound to, empty when every call passes its own location
+	Location string
+}
+
+type ffi struct {
+	ID      string      `json:"id,omitempty"`
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Methods []ffiMethod `json:"methods"`
+	Events  []ffiEvent  `json:"events"`
+}
+
+type ffiMethod struct {
+	Name    string         `json:"name"`
+	Params  []ffiParam     `json:"params"`
+	Returns []ffiParam     `json:"returns"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+type ffiEvent struct {
+	Name   string     `json:"name"`
+	Params []ffiParam `json:"params"`
+}
+
+type ffiParam struct {
+	Name   string    `json:"name"`
+	Schema ffiSchema `json:"schema"`
+}
+
+type ffiSchema struct {
+	Type    string           `json:"type"`
+	Details ffiSchemaDetails `json:"details"`
+}
+
+type ffiSchemaDetails struct {
+	Type         string `json:"type"`
+	InternalType string `json:"internalType,omitempty"`
+	Indexed      bool   `json:"indexed,omitempty"`
+}
+
+type contractAPIRequest struct {
+	Name      string `json:"name"`
+	Interface struct {
+		ID string `json:"id"`
+	} `json:"interface"`
+	Location *location `json:"location,omitempty"`
+}
+
+type contractAPIResponse struct {
+	Name      string `json:"name"`
+	Interface struct {
+		ID string `json:"id"`
+	} `json:"interface"`
+}
+
+// EnsureContractAPI registers the FireFly Interface generated from the embedded ABI and the contract API
+// on top of it when they are missing. When the API already exists, its interface must match the ABI.
+func (c *Client) EnsureContractAPI(ctx context.Context, api ContractAPI) error {
+	want := toFFI(api.Name, api.ABI)
+	base := c.port[defaultUserID]
+
+	var existing contractAPIResponse
+	err := c.doJSON(ctx, http.MethodGet, base.JoinPath(contractAPIPath, api.Name).String(), nil, &existing)
+	if err == nil {
+		var registered ffi
+		u := base.JoinPath(contractInterfacesPath, existing.Interface.ID)
+		u.RawQuery = "fetchchildren=true"
+		if err := c.doJSON(ctx, http.MethodGet, u.String(), nil, &registered); err != nil {
+			return fmt.Errorf("get interface (%s) of API (%s): %w", existing.Interface.ID, api.Name, err)
+		}
+		if diff := diffFFI(&registered, want); diff != "" {
+			return fmt.Errorf("contract API (%s) is bound to interface %s/%s which has drifted from the embedded ABI "+
+				"(- registered, + embedded):\n%s\ndelete the API in Firefly to register the embedded ABI again",
+				api.Name, registered.Name, registered.Version, diff)
+		}
+		return nil
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("get contract API (%s): %w", api.Name, err)
+	}
+
+	iface, err := c.ensureInterface(ctx, want)
+	if err != nil {
+		return err
+	}
+
+	req := contractAPIRequest{Name: api.Name}
+	req.Interface.ID = iface.ID
+	if len(api.Location) > 0 {
+		req.Location = &location{ContractAddress: api.Location}
+	}
+	u := base.JoinPath(contractAPIPath)
+	u.RawQuery = "confirm=true"
+	if err := c.doJSON(ctx, http.MethodPost, u.String(), req, nil); err != nil {
+		return fmt.Errorf("create contract API (%s): %w", api.Name, err)
+	}
+	return nil
+}
+
+// ensureInterface returns the registered interface with the same name and version, creating it when missing
+func (c *Client) ensureInterface(ctx context.Context, want *ffi) (*ffi, error) {
+	base := c.port[defaultUserID]
+
+	var registered ffi
+	u := base.JoinPath(contractInterfacesPath, want.Name, want.Version)
+	u.RawQuery = "fetchchildren=true"
+	err := c.doJSON(ctx, http.MethodGet, u.String(), nil, &registered)
+	if err == nil {
+		if diff := diffFFI(&registered, want); diff != "" {
+			return nil, fmt.Errorf("interface %s/%s has drifted from the embedded ABI (- registered, + embedded):\n%s",
+				want.Name, want.Version, diff)
+		}
+		return &registered, nil
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("get interface %s/%s: %w", want.Name, want.Version, err)
+	}
+
+	u = base.JoinPath(contractInterfacesPath)
+	u.RawQuery = "confirm=true"
+	var created ffi
+	if err := c.doJSON(ctx, http.MethodPost, u.String(), want, &created); err != nil {
+		return nil, fmt.Errorf("create interface %s/%s: %w", want.Name, want.Version, err)
+	}
+	return &created, nil
+}
+
+// toFFI converts an ABI the same way Firefly's interface generator does
+func toFFI(name string, abi *contracts.ABI) *ffi {
+	f := &ffi{
+		Name:    name,
+		Version: ffiVersion,
+		Methods: []ffiMethod{},
+		Events:  []ffiEvent{},
+	}
+	for _, m := range abi.Methods {
+		f.Methods = append(f.Methods, ffiMethod{
+			Name:    m.Name,
+			Params:  toFFIParams(m.Inputs),
+			Returns: toFFIParams(m.Outputs),
+			Details: map[string]any{"stateMutability": m.StateMutability},
+		})
+	}
+	for _, e := range abi.Events {
+		f.Events = append(f.Events, ffiEvent{
+			Name:   e.Name,
+			Params: toFFIParams(e.Inputs),
+		})
+	}
+	sort.Slice(f.Methods, func(i, j int) bool { return f.Methods[i].Name < f.Methods[j].Name })
+	sort.Slice(f.Events, func(i, j int) bool { return f.Events[i].Name < f.Events[j].Name })
+	return f
+}
+
+func toFFIParams(args contracts.Arguments) []ffiParam {
+	params := make([]ffiParam, len(args))
+	for i, arg := range args {
+		params[i] = ffiParam{
+			Name: arg.Name,
+			Schema: ffiSchema{
+				Type: jsonSchemaType(arg.Type),
+				Details: ffiSchemaDetails{
+					Type:         arg.Type,
+					InternalType: arg.InternalType,
+					Indexed:      arg.Indexed,
+				},
+			},
+		}
+	}
+	return params
+}
+
+func jsonSchemaType(solidityType string) string {
+	switch {
+	case solidityType == "bool":
+		return "boolean"
+	case strings.HasPrefix(solidityType, "uint"), strings.HasPrefix(solidityType, "int"):
+		return "integer"
+	}
+	return "string"
+}
+
+// diffFFI compares the callable surface of two interfaces, returning one line per difference
+func diffFFI(registered, embedded *ffi) string {
+	have, want := ffiSignatures(registered), ffiSignatures(embedded)
+	var lines []string
+	for sig := range have {
+		if _, ok := want[sig]; !ok {
+			lines = append(lines, "- "+sig)
+		}
+	}
+	for sig := range want {
+		if _, ok := have[sig]; !ok {
+			lines = append(lines, "+ "+sig)
+		}
+	}
+	// Sort by signature so that a changed entry shows its - and + lines next to each other
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i][2:] == lines[j][2:] {
+			return lines[i] < lines[j]
+		}
+		return lines[i][2:] < lines[j][2:]
+	})
+	return strings.Join(lines, "\n")
+}
+
+func ffiSignatures(f *ffi) map[string]struct{} {
+	sigs := make(map[string]struct{}, len(f.Methods)+len(f.Events))
+	for _, m := range f.Methods {
+		sig := fmt.Sprintf("function %s%s returns %s", m.Name, ffiParamTypes(m.Params), ffiParamTypes(m.Returns))
+		if mut, ok := m.Details["stateMutability"]; ok {
+			sig += fmt.Sprintf(" %v", mut)
+		}
+		sigs[sig] = struct{}{}
+	}
+	for _, e := range f.Events {
+		sigs[fmt.Sprintf("event %s%s", e.Name, ffiParamTypes(e.Params))] = struct{}{}
+	}
+	return sigs
+}
+
+func ffiParamTypes(params []ffiParam) string {
+	types := make([]string, len(params))
+	for i, p := range params {
+		types[i] = p.Schema.Details.Type
+		if p.Schema.Details.Indexed {
+			types[i] += " indexed"
+		}
+	}
+	return "(" + strings.Join(types, ",") + ")"
+}
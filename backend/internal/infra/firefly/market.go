@@ -0,0 +1,600 @@
+package firefly
+
+import (
+	"backend/contracts"
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+)
+
+// PerListingMarket deploys a dedicated Marketplace contract for every listing
+type PerListingMarket struct {
+	c *Client
+}
+
+func NewPerListingMarket(c *Client) *PerListingMarket {
+	return &PerListingMarket{c: c}
+}
+
+// List deploys a new contract for the item, and returns the deployment for the contract registry
+func (m *PerListingMarket) List(ctx context.Context, item *domain.Item) (*domain.ContractDeployment, error) {
+	trxID, err := m.c.DeploySmartContract(ctx, item)
+	if err != nil {
+		return nil, fmt.Errorf("m.c.DeploySmartContract: %w", err)
+	}
+
+	// TODO: Use event listener
+	time.Sleep(time.Second * 5)
+	clocation, err := m.c.GetSmartContractLocation(ctx, trxID)
+	if err != nil {
+		return nil, fmt.Errorf("m.c.GetSmartContractLocation: %w", err)
+	}
+	item.SmartContractAddress = clocation
+
+	if err := m.c.ApproveTokenTransfer(ctx, item); err != nil {
+		return nil, fmt.Errorf("m.c.ApproveTokenTransfer: %w", err)
+	}
+	// History registers missing listeners too, but listening from the deployment on has the events recorded by then
+	if _, err := m.ensureListeners(ctx, clocation); err != nil {
+		log.Printf("Failed to register listeners of contract (%s): %s", clocation, err.Error())
+	}
+	d := newDeployment(clocation, contracts.GetMarketplaceArtifact(), trxID, utils.FromContext(ctx))
+	d.PoolName, d.NFTID = item.PoolName, item.NFTID
+	return d, nil
+}
+
+// SettlesOnBuy is true, as buyNFT transfers the NFT to the buyer in the purchase itself
+func (m *PerListingMarket) SettlesOnBuy() bool {
+	return true
+}
+
+func (m *PerListingMarket) Buy(ctx context.Context, item *domain.Item) error {
+	if err := m.c.BuyNFT(ctx, item.SmartContractAddress); err != nil {
+		return fmt.Errorf("m.c.BuyNFT: %w", err)
+	}
+	return nil
+}
+
+// Delist revokes the contract's approval over the NFT, which only the seller may do. The contract itself
+// cannot be cancelled, but buyNFT reverts once it may no longer transfer the token.
+func (m *PerListingMarket) Delist(ctx context.Context, item *domain.Item) error {
+	ctx = utils.NewContext(ctx, item.SellerID)
+	if err := m.c.RevokeTokenTransfer(ctx, item); err != nil {
+		return fmt.Errorf("m.c.RevokeTokenTransfer: %w", err)
+	}
+	return nil
+}
+
+// SetPrice is unsupported, as the Marketplace contract fixes the price at deployment and has no setter.
+// The price route is not served in per-listing mode.
+func (m *PerListingMarket) SetPrice(_ context.Context, _ *domain.Item, _ domain.Money) error {
+	return fmt.Errorf("the per-listing Marketplace contract fixes the price at deployment, list the item again instead: %w", domain.ErrUnsupported)
+}
+
+func (m *PerListingMarket) HoldOffer(_ context.Context, _ *domain.Item, _ domain.Money) error {
+	return fmt.Errorf("offers need the shared marketplace contract, which holds them in escrow: %w", domain.ErrUnsupported)
+}
+
+func (m *PerListingMarket) ReleaseOffer(_ context.Context, _ *domain.Item) error {
+	return fmt.Errorf("offers need the shared marketplace contract, which holds them in escrow: %w", domain.ErrUnsupported)
+}
+
+func (m *PerListingMarket) AcceptOffer(_ context.Context, _ *domain.Item, _ string, _ domain.Money) error {
+	return fmt.Errorf("offers need the shared marketplace contract, which holds them in escrow: %w", domain.ErrUnsupported)
+}
+
+// Ship has nothing to do on chain, as buyNFT already transferred the NFT. The shipment is only tracked off chain.
+func (m *PerListingMarket) Ship(_ context.Context, _ *domain.Item) error {
+	return nil
+}
+
+// Receive has nothing to do on chain either, the buyer holds the NFT since buyNFT
+func (m *PerListingMarket) Receive(_ context.Context, _ *domain.Item) error {
+	return nil
+}
+
+// DisputeWindow is zero, as the contract holds no payment and the NFT moved with buyNFT, so nothing is left to dispute
+func (m *PerListingMarket) DisputeWindow() time.Duration {
+	return 0
+}
+
+// ReleasePayment has nothing to do, as per-listing contracts take no payment. It is settled off chain.
+func (m *PerListingMarket) ReleasePayment(_ context.Context, _ *domain.Item) error {
+	return nil
+}
+
+// Cancel withdraws a listing like Delist. A sale cannot be cancelled, as buyNFT has already transferred the NFT.
+func (m *PerListingMarket) Cancel(ctx context.Context, item *domain.Item) error {
+	if item.State != domain.ItemStateListed {
+		return fmt.Errorf("the per-listing Marketplace contract transfers the NFT on purchase, so only listings can be cancelled: %w", domain.ErrUnsupported)
+	}
+	return m.Delist(ctx, item)
+}
+
+func (m *PerListingMarket) Dispute(_ context.Context, _ *domain.Item) error {
+	return fmt.Errorf("the per-listing Marketplace contract transfers the NFT on purchase and holds no payment to dispute: %w", domain.ErrUnsupported)
+}
+
+func (m *PerListingMarket) Resolve(_ context.Context, _ *domain.Item, _ bool) error {
+	return fmt.Errorf("disputes are only supported by the shared marketplace contract: %w", domain.ErrUnsupported)
+}
+
+// History returns the listing and sale of the token by each of the given contracts, which the contract registry
+// records as deployed for it
+func (m *PerListingMarket) History(ctx context.Context, tokenIndex string, contracts []string) ([]*domain.ProvenanceEntry, error) {
+	var entries []*domain.ProvenanceEntry
+	for _, address := range contracts {
+		listeners, err := m.ensureListeners(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		var price string
+		for event, id := range listeners {
+			events, err := m.c.contractEvents(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("m.c.contractEvents: %w", err)
+			}
+			for _, ev := range events {
+				entry, nftID, err := listingHistoryEntry(event, &ev)
+				if err != nil {
+					return nil, err
+				}
+				if nftID != tokenIndex {
+					continue
+				}
+				// NFTListed leaves out the price, which the contract fixes at deployment
+				if entry.Kind == domain.ProvenanceKindListing {
+					if len(price) == 0 {
+						p, err := m.c.Marketplace().Price(ctx, address)
+						if err != nil {
+							return nil, fmt.Errorf("m.c.Marketplace().Price on (%s): %w", address, err)
+						}
+						price = p.String()
+					}
+					entry.Price = price
+				}
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// ensureListeners registers the contract listeners of the history events of a contract deployed per listing,
+// and returns their IDs by event name
+func (m *PerListingMarket) ensureListeners(ctx context.Context, address string) (map[string]string, error) {
+	listeners := make(map[string]string, len(historyEvents))
+	for event := range historyEvents {
+		id, err := m.c.EnsureContractListener(ctx, marketplaceAPI, event, address)
+		if err != nil {
+			return nil, fmt.Errorf("m.c.EnsureContractListener: %w", err)
+		}
+		listeners[event] = id
+	}
+	return listeners, nil
+}
+
+// listingHistoryEntry decodes a history event of a contract deployed per listing, and returns it with the index
+// of the token it is about
+func listingHistoryEntry(event string, ev *blockchainEventResponse) (*domain.ProvenanceEntry, string, error) {
+	entry, err := ev.toEntry(historyEvents[event])
+	if err != nil {
+		return nil, "", err
+	}
+	switch event {
+	case "NFTListed":
+		listed, err := DecodeMarketplaceNFTListedEvent(ev.Output)
+		if err != nil {
+			return nil, "", fmt.Errorf("blockchain event (%s): %w", ev.ID, err)
+		}
+		entry.From = listed.Seller.Hex()
+		return entry, listed.NftId.String(), nil
+	case "NFTBought":
+		bought, err := DecodeMarketplaceNFTBoughtEvent(ev.Output)
+		if err != nil {
+			return nil, "", fmt.Errorf("blockchain event (%s): %w", ev.ID, err)
+		}
+		entry.From, entry.To, entry.Price = bought.Seller.Hex(), bought.Buyer.Hex(), bought.Price.String()
+		return entry, bought.NftId.String(), nil
+	}
+	return nil, "", fmt.Errorf("unknown history event (%s)", event)
+}
+
+// SharedMarket sends every listing to a single SharedMarketplace contract registered by the operator.
+// Purchases are paid in the fungible payment pool the contract was deployed with, and held in escrow by the contract.
+type SharedMarket struct {
+	c           *Client
+	address     string
+	paymentPool string
+	// disputeWindow is how long after receipt the buyer can dispute, which the contract was deployed with
+	disputeWindow time.Duration
+	// listeners are the IDs of the Firefly contract listeners recording history events, by event name
+	listeners map[string]string
+}
+
+// sharedStatusReceived is the contract's Status.Received: the buyer holds the NFT, the payment is still in escrow
+const sharedStatusReceived = 5
+
+func NewSharedMarket(c *Client, address, paymentPool string, disputeWindow time.Duration) *SharedMarket {
+	return &SharedMarket{
+		c:             c,
+		address:       address,
+		paymentPool:   paymentPool,
+		disputeWindow: disputeWindow,
+	}
+}
+
+// CheckDisputeWindow fails when the contract was deployed with another dispute window than the configured one.
+// It must be called once the contract API is registered.
+func (m *SharedMarket) CheckDisputeWindow(ctx context.Context) error {
+	window, err := m.c.SharedMarketplace().DisputeWindow(ctx, m.address)
+	if err != nil {
+		return fmt.Errorf("m.c.SharedMarketplace().DisputeWindow: %w", err)
+	}
+	if seconds := int64(m.disputeWindow / time.Second); !window.IsInt64() || window.Int64() != seconds {
+		return fmt.Errorf("contract (%s) has a dispute window of %s seconds, not %d", m.address, window, seconds)
+	}
+	return nil
+}
+
+func (m *SharedMarket) DisputeWindow() time.Duration {
+	return m.disputeWindow
+}
+
+// Deployment describes the shared contract for the contract registry
+func (m *SharedMarket) Deployment() *domain.ContractDeployment {
+	return newDeployment(m.address, contracts.GetSharedMarketplaceArtifact(), "", "")
+}
+
+// List registers the item on the shared contract. Nothing is deployed, so no deployment is returned.
+func (m *SharedMarket) List(ctx context.Context, item *domain.Item) (*domain.ContractDeployment, error) {
+	tokenID, err := tokenIndex(item)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.checkCurrency(item.Price); err != nil {
+		return nil, err
+	}
+
+	// A token bought here before stays with the contract until its payment is released
+	if err := m.release(ctx, tokenID); err != nil {
+		return nil, err
+	}
+	// The contract moves the token on receipt, so it must be an approved operator first
+	item.SmartContractAddress = m.address
+	if err := m.c.ApproveTokenTransfer(ctx, item); err != nil {
+		return nil, fmt.Errorf("m.c.ApproveTokenTransfer: %w", err)
+	}
+	if _, err := m.c.SharedMarketplace().List(ctx, m.address, tokenID, item.Price.Uint256()); err != nil {
+		return nil, fmt.Errorf("m.c.SharedMarketplace().List: %w", err)
+	}
+	return nil, nil
+}
+
+// SettlesOnBuy is false, as the contract holds the payment in escrow until the buyer receives the item
+func (m *SharedMarket) SettlesOnBuy() bool {
+	return false
+}
+
+// Buy moves the price from the buyer into the contract's escrow, which releases it to the seller on receipt
+func (m *SharedMarket) Buy(ctx context.Context, item *domain.Item) error {
+	tokenID, err := tokenIndex(item)
+	if err != nil {
+		return err
+	}
+	if err := m.checkCurrency(item.Price); err != nil {
+		return err
+	}
+	if err := m.c.escrowPayment(ctx, m.paymentPool, m.address, item.Price.Uint256()); err != nil {
+		return fmt.Errorf("m.c.escrowPayment: %w", err)
+	}
+	if _, err := m.c.SharedMarketplace().Buy(ctx, m.address, tokenID); err != nil {
+		return fmt.Errorf("m.c.SharedMarketplace().Buy: %w", err)
+	}
+	return nil
+}
+
+// Delist cancels the listing on the contract, then revokes the contract's approval over the NFT
+func (m *SharedMarket) Delist(ctx context.Context, item *domain.Item) error {
+	return m.Cancel(ctx, item)
+}
+
+// SetPrice changes the price of an active listing, which the contract only accepts from the seller
+func (m *SharedMarket) SetPrice(ctx context.Context, item *domain.Item, price domain.Money) error {
+	tokenID, err := tokenIndex(item)
+	if err != nil {
+		return err
+	}
+	if err := m.checkCurrency(price); err != nil {
+		return err
+	}
+	if _, err := m.c.SharedMarketplace().SetPrice(ctx, m.address, tokenID, price.Uint256()); err != nil {
+		return fmt.Errorf("m.c.SharedMarketplace().SetPrice: %w", err)
+	}
+	return nil
+}
+
+// HoldOffer escrows amount in the contract as the caller's offer on the item. Only the difference to an offer
+// the caller already holds on it is paid in or refunded.
+func (m *SharedMarket) HoldOffer(ctx context.Context, item *domain.Item, amount domain.Money) error {
+	tokenID, err := tokenIndex(item)
+	if err != nil {
+		return err
+	}
+	if err := m.checkCurrency(amount); err != nil {
+		return err
+	}
+	held, err := m.heldOffer(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+	if more := new(big.Int).Sub(amount.Uint256(), held); more.Sign() > 0 {
+		if err := m.c.escrowPayment(ctx, m.paymentPool, m.address, more); err != nil {
+			return fmt.Errorf("m.c.escrowPayment: %w", err)
+		}
+	}
+	if _, err := m.c.SharedMarketplace().Offer(ctx, m.address, tokenID, amount.Uint256()); err != nil {
+		return fmt.Errorf("m.c.SharedMarketplace().Offer: %w", err)
+	}
+	return nil
+}
+
+// ReleaseOffer refunds the caller's offer on the item. It does nothing when they hold none, such as once
+// the offer was accepted.
+func (m *SharedMarket) ReleaseOffer(ctx context.Context, item *domain.Item) error {
+	tokenID, err := tokenIndex(item)
+	if err != nil {
+		return err
+	}
+	held, err := m.heldOffer(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+	if held.Sign() == 0 {
+		return nil
+	}
+	if _, err := m.c.SharedMarketplace().WithdrawOffer(ctx, m.address, tokenID); err != nil {
+		return fmt.Errorf("m.c.SharedMarketplace().WithdrawOffer: %w", err)
+	}
+	return nil
+}
+
+// AcceptOffer sells the item to buyerID out of the offer they hold in escrow, which must be exactly price.
+// The contract only accepts it from the seller, and nobody else can buy the item at that price.
+func (m *SharedMarket) AcceptOffer(ctx context.Context, item *domain.Item, buyerID string, price domain.Money) error {
+	tokenID, err := tokenIndex(item)
+	if err != nil {
+		return err
+	}
+	if err := m.checkCurrency(price); err != nil {
+		return err
+	}
+	buyer, err := m.c.signingAddress(utils.NewContext(ctx, buyerID))
+	if err != nil {
+		return err
+	}
+	if _, err := m.c.SharedMarketplace().AcceptOffer(ctx, m.address, tokenID, buyer, price.Uint256()); err != nil {
+		return fmt.Errorf("m.c.SharedMarketplace().AcceptOffer: %w", err)
+	}
+	return nil
+}
+
+// heldOffer returns what the caller holds in escrow as their offer on the token
+func (m *SharedMarket) heldOffer(ctx context.Context, tokenID *big.Int) (*big.Int, error) {
+	buyer, err := m.c.signingAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	held, err := m.c.SharedMarketplace().Offers(ctx, m.address, tokenID, buyer)
+	if err != nil {
+		return nil, fmt.Errorf("m.c.SharedMarketplace().Offers: %w", err)
+	}
+	return held, nil
+}
+
+func (m *SharedMarket) Ship(ctx context.Context, item *domain.Item) error {
+	tokenID, err := tokenIndex(item)
+	if err != nil {
+		return err
+	}
+	if _, err := m.c.SharedMarketplace().Shipped(ctx, m.address, tokenID); err != nil {
+		return fmt.Errorf("m.c.SharedMarketplace().Shipped: %w", err)
+	}
+	return nil
+}
+
+func (m *SharedMarket) Receive(ctx context.Context, item *domain.Item) error {
+	tokenID, err := tokenIndex(item)
+	if err != nil {
+		return err
+	}
+	if _, err := m.c.SharedMarketplace().Received(ctx, m.address, tokenID); err != nil {
+		return fmt.Errorf("m.c.SharedMarketplace().Received: %w", err)
+	}
+	return nil
+}
+
+// ReleasePayment pays the seller of a received item out of escrow, once its dispute window has passed.
+// It does nothing when the payment was already released.
+func (m *SharedMarket) ReleasePayment(ctx context.Context, item *domain.Item) error {
+	tokenID, err := tokenIndex(item)
+	if err != nil {
+		return err
+	}
+	return m.release(ctx, tokenID)
+}
+
+func (m *SharedMarket) release(ctx context.Context, tokenID *big.Int) error {
+	status, err := m.c.SharedMarketplace().Statuses(ctx, m.address, tokenID)
+	if err != nil {
+		return fmt.Errorf("m.c.SharedMarketplace().Statuses: %w", err)
+	}
+	if status.Cmp(big.NewInt(sharedStatusReceived)) != 0 {
+		return nil
+	}
+	if _, err := m.c.SharedMarketplace().Release(ctx, m.address, tokenID); err != nil {
+		return fmt.Errorf("m.c.SharedMarketplace().Release: %w", err)
+	}
+	return nil
+}
+
+// Cancel withdraws a listing or an unshipped sale, refunding the buyer if there is one. The NFT stays with
+// the seller, so the contract's approval over it is revoked as well.
+func (m *SharedMarket) Cancel(ctx context.Context, item *domain.Item) error {
+	tokenID, err := tokenIndex(item)
+	if err != nil {
+		return err
+	}
+	if _, err := m.c.SharedMarketplace().Cancel(ctx, m.address, tokenID); err != nil {
+		return fmt.Errorf("m.c.SharedMarketplace().Cancel: %w", err)
+	}
+	// Either party may cancel, but only the seller can revoke the approval they granted
+	if err := m.c.RevokeTokenTransfer(utils.NewContext(ctx, item.SellerID), item); err != nil {
+		return fmt.Errorf("m.c.RevokeTokenTransfer: %w", err)
+	}
+	return nil
+}
+
+// Dispute freezes the escrow of a bought, shipped or received item, which the contract only accepts from the buyer.
+// A buyer who received the item hands the NFT back to the contract, so they must approve it first.
+func (m *SharedMarket) Dispute(ctx context.Context, item *domain.Item) error {
+	tokenID, err := tokenIndex(item)
+	if err != nil {
+		return err
+	}
+	if item.State == domain.ItemStateReceived {
+		if err := m.c.ApproveTokenTransfer(ctx, item); err != nil {
+			return fmt.Errorf("m.c.ApproveTokenTransfer: %w", err)
+		}
+	}
+	if _, err := m.c.SharedMarketplace().Dispute(ctx, m.address, tokenID); err != nil {
+		return fmt.Errorf("m.c.SharedMarketplace().Dispute: %w", err)
+	}
+	return nil
+}
+
+// Resolve either refunds the buyer or completes the swap. The contract only accepts it from its arbiter.
+func (m *SharedMarket) Resolve(ctx context.Context, item *domain.Item, refund bool) error {
+	tokenID, err := tokenIndex(item)
+	if err != nil {
+		return err
+	}
+	if _, err := m.c.SharedMarketplace().Resolve(ctx, m.address, tokenID, refund); err != nil {
+		return fmt.Errorf("m.c.SharedMarketplace().Resolve: %w", err)
+	}
+	return nil
+}
+
+const (
+	// marketplaceAPI is the name the Firefly contract API of the contracts deployed per listing is registered under
+	marketplaceAPI = "marketplace"
+	// sharedMarketplaceAPI is the name the shared contract's Firefly contract API is registered under
+	sharedMarketplaceAPI = "sharedmarketplace"
+)
+
+// historyEvents are the contract events a provenance timeline is made of
+var historyEvents = map[string]domain.ProvenanceKind{
+	"NFTListed": domain.ProvenanceKindListing,
+	"NFTBought": domain.ProvenanceKindSale,
+}
+
+// EnsureListeners registers the Firefly contract listeners History reads from. It must be called
+// once the contract API is registered, and before History is.
+func (m *SharedMarket) EnsureListeners(ctx context.Context) error {
+	listeners := make(map[string]string, len(historyEvents))
+	for event := range historyEvents {
+		id, err := m.c.EnsureContractListener(ctx, sharedMarketplaceAPI, event, "")
+		if err != nil {
+			return fmt.Errorf("m.c.EnsureContractListener: %w", err)
+		}
+		listeners[event] = id
+	}
+	m.listeners = listeners
+	return nil
+}
+
+// History returns every listing and sale of the token on the contract. The token has no contracts of its own.
+func (m *SharedMarket) History(ctx context.Context, tokenIndex string, _ []string) ([]*domain.ProvenanceEntry, error) {
+	if len(m.listeners) == 0 {
+		return nil, fmt.Errorf("contract listeners of %s are not registered", sharedMarketplaceAPI)
+	}
+	var entries []*domain.ProvenanceEntry
+	for event, id := range m.listeners {
+		events, err := m.c.contractEvents(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("m.c.contractEvents: %w", err)
+		}
+		for _, ev := range events {
+			entry, nftID, err := historyEntry(event, &ev)
+			if err != nil {
+				return nil, err
+			}
+			if nftID == tokenIndex {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// historyEntry decodes a history event, and returns it with the index of the token it is about
+func historyEntry(event string, ev *blockchainEventResponse) (*domain.ProvenanceEntry, string, error) {
+	entry, err := ev.toEntry(historyEvents[event])
+	if err != nil {
+		return nil, "", err
+	}
+	switch event {
+	case "NFTListed":
+		listed, err := DecodeSharedMarketplaceNFTListedEvent(ev.Output)
+		if err != nil {
+			return nil, "", fmt.Errorf("blockchain event (%s): %w", ev.ID, err)
+		}
+		entry.From, entry.Price = listed.Seller.Hex(), listed.Price.String()
+		return entry, listed.NftId.String(), nil
+	case "NFTBought":
+		bought, err := DecodeSharedMarketplaceNFTBoughtEvent(ev.Output)
+		if err != nil {
+			return nil, "", fmt.Errorf("blockchain event (%s): %w", ev.ID, err)
+		}
+		entry.From, entry.To, entry.Price = bought.Seller.Hex(), bought.Buyer.Hex(), bought.Price.String()
+		return entry, bought.NftId.String(), nil
+	}
+	return nil, "", fmt.Errorf("unknown history event (%s)", event)
+}
+
+// checkCurrency rejects prices in another currency than the payment pool's token, which the contract settles in
+func (m *SharedMarket) checkCurrency(price domain.Money) error {
+	pool, err := m.c.PaymentPool(m.paymentPool)
+	if err != nil {
+		return err
+	}
+	if len(pool.Symbol) > 0 && price.Currency() != pool.Symbol {
+		return fmt.Errorf("price is in %s, but payments settle in %s: %w", price.Currency(), pool.Symbol, domain.ErrInvalidArgument)
+	}
+	return nil
+}
+
+func newDeployment(address string, a *contracts.Artifact, trxID, deployedBy string) *domain.ContractDeployment {
+	return &domain.ContractDeployment{
+		Address:         address,
+		ContractName:    a.Name,
+		CodeHash:        a.CodeHash,
+		ABIHash:         a.ABIHash,
+		CompilerVersion: a.CompilerVersion,
+		DeployTxID:      trxID,
+		DeployedBy:      deployedBy,
+	}
+}
+
+func tokenIndex(item *domain.Item) (*big.Int, error) {
+	id, ok := new(big.Int).SetString(item.NFTID, 10)
+	if !ok {
+		return nil, fmt.Errorf("nft id (%s) of item (%s) is not a token index: %w", item.NFTID, item.ID, domain.ErrInvalidArgument)
+	}
+	return id, nil
+}
@@ -0,0 +1,169 @@
+// Code generated by abigen. DO NOT EDIT.
+
+package firefly
+
+import (
+	"backend/contracts"
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// Marketplace exposes every Marketplace contract function through the "marketplace" Firefly contract API.
+// Functions that change state are invoked as transactions and return the Firefly transaction ID,
+// read-only functions are queried and return their decoded outputs.
+type Marketplace struct {
+	c *Client
+}
+
+func (c *Client) Marketplace() *Marketplace {
+	return &Marketplace{c: c}
+}
+
+func MarketplaceABI() *contracts.ABI {
+	return contracts.GetMarketplace()
+}
+
+// BuyNFT invokes buyNFT on the contract deployed at location
+func (m *Marketplace) BuyNFT(ctx context.Context, location string) (string, error) {
+	method := MarketplaceABI().Methods["buyNFT"]
+	input, err := method.Inputs.PackNamed()
+	if err != nil {
+		return "", fmt.Errorf("buyNFT inputs: %w", err)
+	}
+	tx, err := m.c.invokeContract(ctx, "marketplace", "buyNFT", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+}
+
+// Nft queries nft on the contract deployed at location
+func (m *Marketplace) Nft(ctx context.Context, location string) (contracts.Address, error) {
+	method := MarketplaceABI().Methods["nft"]
+	input, err := method.Inputs.PackNamed()
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("nft inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "marketplace", "nft", location, input)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("nft outputs: %w", err)
+	}
+	return values[0].(contracts.Address), nil
+}
+
+// NftId queries nftId on the contract deployed at location
+func (m *Marketplace) NftId(ctx context.Context, location string) (*big.Int, error) {
+	method := MarketplaceABI().Methods["nftId"]
+	input, err := method.Inputs.PackNamed()
+	if err != nil {
+		return nil, fmt.Errorf("nftId inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "marketplace", "nftId", location, input)
+	if err != nil {
+		return nil, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("nftId outputs: %w", err)
+	}
+	return values[0].(*big.Int), nil
+}
+
+// OnSale queries onSale on the contract deployed at location
+func (m *Marketplace) OnSale(ctx context.Context, location string) (bool, error) {
+	method := MarketplaceABI().Methods["onSale"]
+	input, err := method.Inputs.PackNamed()
+	if err != nil {
+		return false, fmt.Errorf("onSale inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "marketplace", "onSale", location, input)
+	if err != nil {
+		return false, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return false, fmt.Errorf("onSale outputs: %w", err)
+	}
+	return values[0].(bool), nil
+}
+
+// Price queries price on the contract deployed at location
+func (m *Marketplace) Price(ctx context.Context, location string) (*big.Int, error) {
+	method := MarketplaceABI().Methods["price"]
+	input, err := method.Inputs.PackNamed()
+	if err != nil {
+		return nil, fmt.Errorf("price inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "marketplace", "price", location, input)
+	if err != nil {
+		return nil, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("price outputs: %w", err)
+	}
+	return values[0].(*big.Int), nil
+}
+
+// Seller queries seller on the contract deployed at location
+func (m *Marketplace) Seller(ctx context.Context, location string) (contracts.Address, error) {
+	method := MarketplaceABI().Methods["seller"]
+	input, err := method.Inputs.PackNamed()
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("seller inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "marketplace", "seller", location, input)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("seller outputs: %w", err)
+	}
+	return values[0].(contracts.Address), nil
+}
+
+// MarketplaceNFTBoughtEvent is the decoded NFTBought event
+type MarketplaceNFTBoughtEvent struct {
+	Buyer  contracts.Address
+	Seller contracts.Address
+	NftId  *big.Int
+	Price  *big.Int
+}
+
+// DecodeMarketplaceNFTBoughtEvent decodes the output of a Firefly blockchain event for NFTBought
+func DecodeMarketplaceNFTBoughtEvent(output map[string]any) (*MarketplaceNFTBoughtEvent, error) {
+	values, err := MarketplaceABI().Events["NFTBought"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("NFTBought: %w", err)
+	}
+	return &MarketplaceNFTBoughtEvent{
+		Buyer:  values[0].(contracts.Address),
+		Seller: values[1].(contracts.Address),
+		NftId:  values[2].(*big.Int),
+		Price:  values[3].(*big.Int),
+	}, nil
+}
+
+// MarketplaceNFTListedEvent is the decoded NFTListed event
+type MarketplaceNFTListedEvent struct {
+	Seller contracts.Address
+	NftId  *big.Int
+}
+
+// DecodeMarketplaceNFTListedEvent decodes the output of a Firefly blockchain event for NFTListed
+func DecodeMarketplaceNFTListedEvent(output map[string]any) (*MarketplaceNFTListedEvent, error) {
+	values, err := MarketplaceABI().Events["NFTListed"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("NFTListed: %w", err)
+	}
+	return &MarketplaceNFTListedEvent{
+		Seller: values[0].(contracts.Address),
+		NftId:  values[1].(*big.Int),
+	}, nil
+}
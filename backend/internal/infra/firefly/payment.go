@@ -0,0 +1,146 @@
+package firefly
+
+import (
+	"backend/contracts"
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+)
+
+const (
+	statusPath       = "status"
+	tokenBalancePath = "tokens/balances"
+	verifierTypeEth  = "ethereum_address"
+)
+
+type statusResponse struct {
+	Org struct {
+		Verifiers []struct {
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		} `json:"verifiers"`
+	} `json:"org"`
+}
+
+type tokenBalanceResponse struct {
+	Balance string `json:"balance"`
+}
+
+type approvePaymentRequest struct {
+	Pool     string `json:"pool"`
+	Operator string `json:"operator"`
+	Approved bool   `json:"approved"`
+	Config   struct {
+		Allowance string `json:"allowance"`
+	} `json:"config"`
+}
+
+// SigningKey returns the Ethereum address the caller's Firefly node signs transactions with
+func (c *Client) SigningKey(ctx context.Context) (string, error) {
+	uid := utils.FromContext(ctx)
+	c.mu.RLock()
+	key, ok := c.keys[uid]
+	c.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	base, ok := c.port[uid]
+	if !ok {
+		return "", fmt.Errorf("user (%s) has no Firefly node: %w", uid, domain.ErrNotFound)
+	}
+	var res statusResponse
+	u := base.JoinPath(statusPath)
+	if err := c.doJSON(ctx, http.MethodGet, u.String(), nil, &res); err != nil {
+		return "", fmt.Errorf("get status of user (%s): %w", uid, err)
+	}
+	for _, v := range res.Org.Verifiers {
+		if v.Type == verifierTypeEth {
+			c.mu.Lock()
+			c.keys[uid] = v.Value
+			c.mu.Unlock()
+			return v.Value, nil
+		}
+	}
+	return "", fmt.Errorf("org of user (%s) has no %s verifier", uid, verifierTypeEth)
+}
+
+// signingAddress returns the caller's signing key as a contract argument
+func (c *Client) signingAddress(ctx context.Context) (contracts.Address, error) {
+	key, err := c.SigningKey(ctx)
+	if err != nil {
+		return contracts.Address{}, err
+	}
+	a, err := contracts.ParseAddress(key)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("signing key of user (%s): %w", utils.FromContext(ctx), err)
+	}
+	return a, nil
+}
+
+// PaymentBalance returns the caller's balance in the named fungible pool, in the pool's base units
+func (c *Client) PaymentBalance(ctx context.Context, poolName string) (*big.Int, error) {
+	pool, err := c.PaymentPool(poolName)
+	if err != nil {
+		return nil, err
+	}
+	key, err := c.SigningKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []tokenBalanceResponse
+	u := c.port[utils.FromContext(ctx)].JoinPath(tokenBalancePath)
+	u.RawQuery = url.Values{"pool": {pool.ID}, "key": {key}}.Encode()
+	if err := c.doJSON(ctx, http.MethodGet, u.String(), nil, &res); err != nil {
+		return nil, fmt.Errorf("get balance of (%s) in pool (%s): %w", key, pool.Name, err)
+	}
+	// Firefly has no balance record for a key that never held the token
+	balance := new(big.Int)
+	if len(res) == 0 {
+		return balance, nil
+	}
+	if _, ok := balance.SetString(res[0].Balance, 10); !ok {
+		return nil, fmt.Errorf("balance (%s) of (%s) in pool (%s) is not an integer", res[0].Balance, key, pool.Name)
+	}
+	return balance, nil
+}
+
+// ApprovePayment lets operator spend up to amount of the caller's tokens in the named fungible pool.
+// It waits for the approval to be confirmed, so the operator can spend them in the next transaction.
+func (c *Client) ApprovePayment(ctx context.Context, poolName, operator string, amount *big.Int) error {
+	pool, err := c.PaymentPool(poolName)
+	if err != nil {
+		return err
+	}
+	req := approvePaymentRequest{
+		Pool:     pool.Name,
+		Operator: operator,
+		Approved: true,
+	}
+	req.Config.Allowance = amount.String()
+
+	u := c.port[utils.FromContext(ctx)].JoinPath(approveTokenPath)
+	u.RawQuery = "confirm=true"
+	if err := c.doJSON(ctx, http.MethodPost, u.String(), req, nil); err != nil {
+		return fmt.Errorf("approve (%s) to spend %s in pool (%s): %w", operator, amount, pool.Name, err)
+	}
+	return nil
+}
+
+// escrowPayment checks that the caller can pay amount, then approves the market contract to take it into escrow.
+// An insufficient balance is rejected before any transaction is sent.
+func (c *Client) escrowPayment(ctx context.Context, poolName, market string, amount *big.Int) error {
+	balance, err := c.PaymentBalance(ctx, poolName)
+	if err != nil {
+		return err
+	}
+	if balance.Cmp(amount) < 0 {
+		return fmt.Errorf("balance %s does not cover %s: %w", balance, amount, domain.ErrInsufficientFunds)
+	}
+	return c.ApprovePayment(ctx, poolName, market, amount)
+}
@@ -0,0 +1,145 @@
+package firefly
+
+import (
+	"backend/internal/domain"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	tokenPoolsPath     = "tokens/pools"
+	poolStateConfirmed = "confirmed"
+	poolConfirmTimeout = 60 * time.Second
+	poolConfirmPoll    = 2 * time.Second
+)
+
+type tokenPoolRequest struct {
+	Name string               `json:"name"`
+	Type domain.TokenPoolType `json:"type"`
+}
+
+type tokenPoolResponse struct {
+	ID        string               `json:"id"`
+	Name      string               `json:"name"`
+	Type      domain.TokenPoolType `json:"type"`
+	Symbol    string               `json:"symbol"`
+	Locator   string               `json:"locator"`
+	Connector string               `json:"connector"`
+	State     string               `json:"state"`
+	Info      struct {
+		Address string `json:"address"`
+	} `json:"info"`
+}
+
+func (r *tokenPoolResponse) toDomain() *domain.TokenPool {
+	return &domain.TokenPool{
+		ID:        r.ID,
+		Name:      r.Name,
+		Type:      r.Type,
+		Symbol:    r.Symbol,
+		Locator:   r.Locator,
+		Address:   r.Info.Address,
+		Connector: r.Connector,
+		State:     r.State,
+	}
+}
+
+// EnsurePool looks the named token pool up and creates it only when it is missing.
+// It returns once the pool is confirmed, and caches it for Pool and PaymentPool.
+func (c *Client) EnsurePool(ctx context.Context, name string, poolType domain.TokenPoolType) (*domain.TokenPool, error) {
+	base := c.port[defaultUserID]
+
+	var found []tokenPoolResponse
+	u := base.JoinPath(tokenPoolsPath)
+	u.RawQuery = url.Values{"name": {name}}.Encode()
+	if err := c.doJSON(ctx, http.MethodGet, u.String(), nil, &found); err != nil {
+		return nil, fmt.Errorf("get token pool (%s): %w", name, err)
+	}
+
+	var pool tokenPoolResponse
+	if len(found) > 0 {
+		pool = found[0]
+		if pool.Type != poolType {
+			return nil, fmt.Errorf("token pool (%s) is %s, expected %s", name, pool.Type, poolType)
+		}
+	} else {
+		u = base.JoinPath(tokenPoolsPath)
+		u.RawQuery = "confirm=true"
+		if err := c.doJSON(ctx, http.MethodPost, u.String(), tokenPoolRequest{Name: name, Type: poolType}, &pool); err != nil {
+			return nil, fmt.Errorf("create token pool (%s): %w", name, err)
+		}
+	}
+
+	if pool.State != poolStateConfirmed {
+		confirmed, err := c.waitForPool(ctx, pool.ID)
+		if err != nil {
+			return nil, fmt.Errorf("token pool (%s): %w", name, err)
+		}
+		pool = *confirmed
+	}
+
+	p := pool.toDomain()
+	c.mu.Lock()
+	c.pools[p.Name] = p
+	c.mu.Unlock()
+	return p, nil
+}
+
+// waitForPool polls the pool until the token connector has confirmed it
+func (c *Client) waitForPool(ctx context.Context, id string) (*tokenPoolResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, poolConfirmTimeout)
+	defer cancel()
+
+	u := c.port[defaultUserID].JoinPath(tokenPoolsPath, id)
+	ticker := time.NewTicker(poolConfirmPoll)
+	defer ticker.Stop()
+	for {
+		var pool tokenPoolResponse
+		if err := c.doJSON(ctx, http.MethodGet, u.String(), nil, &pool); err != nil {
+			return nil, fmt.Errorf("get token pool (%s): %w", id, err)
+		}
+		if pool.State == poolStateConfirmed {
+			return &pool, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("not confirmed, last state (%s): %w", pool.State, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// SetDefaultPool selects the pool used for items without a pool name
+func (c *Client) SetDefaultPool(name string) {
+	c.mu.Lock()
+	c.defaultPool = name
+	c.mu.Unlock()
+}
+
+// Pool returns an NFT pool resolved by EnsurePool, or the default pool when name is empty
+func (c *Client) Pool(name string) (*domain.TokenPool, error) {
+	c.mu.RLock()
+	if len(name) == 0 {
+		name = c.defaultPool
+	}
+	c.mu.RUnlock()
+	return c.lookupPool(name, domain.TokenPoolTypeNonFungible)
+}
+
+// PaymentPool returns a fungible pool resolved by EnsurePool
+func (c *Client) PaymentPool(name string) (*domain.TokenPool, error) {
+	return c.lookupPool(name, domain.TokenPoolTypeFungible)
+}
+
+func (c *Client) lookupPool(name string, poolType domain.TokenPoolType) (*domain.TokenPool, error) {
+	c.mu.RLock()
+	p, ok := c.pools[name]
+	c.mu.RUnlock()
+	if !ok || p.Type != poolType {
+		return nil, fmt.Errorf("%s token pool (%s) is not configured: %w", poolType, name, domain.ErrInvalidArgument)
+	}
+	return p, nil
+}
@@ -0,0 +1,205 @@
+package firefly
+
+import (
+	"backend/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tokenTransfersPath   = "tokens/transfers"
+	blockchainEventsPath = "blockchainevents"
+	listenersPath        = "listeners"
+	// pageSize is how many records a list query asks Firefly for at once, below its maximum limit
+	pageSize = 200
+)
+
+type tokenTransferResponse struct {
+	Type            string `json:"type"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	BlockchainEvent string `json:"blockchainEvent"`
+}
+
+type blockchainEventResponse struct {
+	ID        string         `json:"id"`
+	Output    map[string]any `json:"output"`
+	Timestamp time.Time      `json:"timestamp"`
+	Info      struct {
+		// BlockNumber is a decimal string with the EVM connector, and a number with some others
+		BlockNumber     json.RawMessage `json:"blockNumber"`
+		TransactionHash string          `json:"transactionHash"`
+	} `json:"info"`
+}
+
+// toEntry fills in where and when the event happened on chain
+func (e *blockchainEventResponse) toEntry(kind domain.ProvenanceKind) (*domain.ProvenanceEntry, error) {
+	n, err := strconv.ParseUint(strings.Trim(string(e.Info.BlockNumber), `"`), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("block number (%s) of blockchain event (%s): %w", e.Info.BlockNumber, e.ID, err)
+	}
+	return &domain.ProvenanceEntry{
+		Kind:        kind,
+		TxHash:      e.Info.TransactionHash,
+		BlockNumber: n,
+		Timestamp:   e.Timestamp.UTC(),
+	}, nil
+}
+
+var transferKinds = map[string]domain.ProvenanceKind{
+	"mint":     domain.ProvenanceKindMint,
+	"transfer": domain.ProvenanceKindTransfer,
+	"burn":     domain.ProvenanceKindBurn,
+}
+
+// TokenTransfers returns every mint, transfer and burn of the token in the named NFT pool, oldest first
+func (c *Client) TokenTransfers(ctx context.Context, poolName, tokenIndex string) ([]*domain.ProvenanceEntry, error) {
+	pool, err := c.Pool(poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	u := c.port[defaultUserID].JoinPath(tokenTransfersPath)
+	transfers, err := getAll[tokenTransferResponse](ctx, c, u, url.Values{"pool": {pool.ID}, "tokenindex": {tokenIndex}, "sort": {"created"}})
+	if err != nil {
+		return nil, fmt.Errorf("get transfers of token (%s) in pool (%s): %w", tokenIndex, pool.Name, err)
+	}
+
+	entries := make([]*domain.ProvenanceEntry, 0, len(transfers))
+	for _, t := range transfers {
+		kind, ok := transferKinds[t.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown type (%s) of transfer of token (%s)", t.Type, tokenIndex)
+		}
+		ev, err := c.blockchainEvent(ctx, t.BlockchainEvent)
+		if err != nil {
+			return nil, err
+		}
+		entry, err := ev.toEntry(kind)
+		if err != nil {
+			return nil, err
+		}
+		entry.From, entry.To = t.From, t.To
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (c *Client) blockchainEvent(ctx context.Context, id string) (*blockchainEventResponse, error) {
+	var ev blockchainEventResponse
+	u := c.port[defaultUserID].JoinPath(blockchainEventsPath, id)
+	if err := c.doJSON(ctx, http.MethodGet, u.String(), nil, &ev); err != nil {
+		return nil, fmt.Errorf("get blockchain event (%s): %w", id, err)
+	}
+	return &ev, nil
+}
+
+type contractListenerRequest struct {
+	Name     string    `json:"name"`
+	Topic    string    `json:"topic"`
+	Location *location `json:"location,omitempty"`
+	Options  struct {
+		FirstEvent string `json:"firstEvent"`
+	} `json:"options"`
+}
+
+type contractListenerResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// EnsureContractListener makes Firefly record every event of the named contract API, from the first block on,
+// and returns the listener's ID. An existing listener with the same name is reused. The address is empty for
+// an API bound to a contract, and names the contract to listen to otherwise.
+func (c *Client) EnsureContractListener(ctx context.Context, api, event, address string) (string, error) {
+	name := api + "-" + event
+	if len(address) > 0 {
+		name += "-" + address
+	}
+	u := c.port[defaultUserID].JoinPath(contractAPIPath, api, listenersPath, event)
+
+	var existing []contractListenerResponse
+	if err := c.doJSON(ctx, http.MethodGet, u.String(), nil, &existing); err != nil {
+		return "", fmt.Errorf("get listeners of (%s) on API (%s): %w", event, api, err)
+	}
+	for _, l := range existing {
+		if l.Name == name {
+			return l.ID, nil
+		}
+	}
+
+	req := contractListenerRequest{Name: name, Topic: api}
+	if len(address) > 0 {
+		req.Location = &location{ContractAddress: address}
+	}
+	req.Options.FirstEvent = "oldest"
+	var created contractListenerResponse
+	if err := c.doJSON(ctx, http.MethodPost, u.String(), req, &created); err != nil {
+		return "", fmt.Errorf("create listener for (%s) on API (%s): %w", event, api, err)
+	}
+	return created.ID, nil
+}
+
+// contractEvents returns every event the listener has recorded, oldest first. Firefly cannot filter on the
+// values of an event, so callers filter the events by token themselves.
+func (c *Client) contractEvents(ctx context.Context, listenerID string) ([]blockchainEventResponse, error) {
+	u := c.port[defaultUserID].JoinPath(blockchainEventsPath)
+	events, err := getAll[blockchainEventResponse](ctx, c, u, url.Values{"listener": {listenerID}, "sort": {"timestamp"}})
+	if err != nil {
+		return nil, fmt.Errorf("get events of listener (%s): %w", listenerID, err)
+	}
+	return events, nil
+}
+
+// getAll lists the records at u matching query, a page at a time until Firefly returns a short page.
+// A single query only returns Firefly's default limit of records.
+func getAll[T any](ctx context.Context, c *Client, u *url.URL, query url.Values) ([]T, error) {
+	var all []T
+	query.Set("limit", strconv.Itoa(pageSize))
+	for skip := 0; ; skip += pageSize {
+		query.Set("skip", strconv.Itoa(skip))
+		u.RawQuery = query.Encode()
+		var page []T
+		if err := c.doJSON(ctx, http.MethodGet, u.String(), nil, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < pageSize {
+			return all, nil
+		}
+	}
+}
+
+type tokenHolderResponse struct {
+	Key     string `json:"key"`
+	Balance string `json:"balance"`
+	URI     string `json:"uri"`
+}
+
+// TokenHolder returns who holds the token in the named NFT pool, or nil when nobody does
+func (c *Client) TokenHolder(ctx context.Context, poolName, tokenIndex string) (*domain.TokenHolder, error) {
+	pool, err := c.Pool(poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	var balances []tokenHolderResponse
+	u := c.port[defaultUserID].JoinPath(tokenBalancePath)
+	u.RawQuery = url.Values{"pool": {pool.ID}, "tokenindex": {tokenIndex}}.Encode()
+	if err := c.doJSON(ctx, http.MethodGet, u.String(), nil, &balances); err != nil {
+		return nil, fmt.Errorf("get balances of token (%s) in pool (%s): %w", tokenIndex, pool.Name, err)
+	}
+	// Firefly keeps a zero balance for every key the token has left
+	for _, b := range balances {
+		if b.Balance != "0" {
+			return &domain.TokenHolder{Owner: b.Key, URI: b.URI}, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,603 @@
+// Code generated by abigen. DO NOT EDIT.
+
+package firefly
+
+import (
+	"backend/contracts"
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// SharedMarketplace exposes every SharedMarketplace contract function through the "sharedmarketplace" Firefly contract API.
+// Functions that change state are invoked as transactions and return the Firefly transaction ID,
+// read-only functions are queried and return their decoded outputs.
+type SharedMarketplace struct {
+	c *Client
+}
+
+func (c *Client) SharedMarketplace() *SharedMarketplace {
+	return &SharedMarketplace{c: c}
+}
+
+func SharedMarketplaceABI() *contracts.ABI {
+	return contracts.GetSharedMarketplace()
+}
+
+// AcceptOffer invokes acceptOffer on the contract deployed at location
+func (m *SharedMarketplace) AcceptOffer(ctx context.Context, location string, tokenId *big.Int, buyer contracts.Address, price *big.Int) (string, error) {
+	method := SharedMarketplaceABI().Methods["acceptOffer"]
+	input, err := method.Inputs.PackNamed(tokenId, buyer, price)
+	if err != nil {
+		return "", fmt.Errorf("acceptOffer inputs: %w", err)
+	}
+	tx, err := m.c.invokeContract(ctx, "sharedmarketplace", "acceptOffer", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+}
+
+// Arbiter queries arbiter on the contract deployed at location
+func (m *SharedMarketplace) Arbiter(ctx context.Context, location string) (contracts.Address, error) {
+	method := SharedMarketplaceABI().Methods["arbiter"]
+	input, err := method.Inputs.PackNamed()
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("arbiter inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "sharedmarketplace", "arbiter", location, input)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("arbiter outputs: %w", err)
+	}
+	return values[0].(contracts.Address), nil
+}
+
+// Buy invokes buy on the contract deployed at location
+func (m *SharedMarketplace) Buy(ctx context.Context, location string, tokenId *big.Int) (string, error) {
+	method := SharedMarketplaceABI().Methods["buy"]
+	input, err := method.Inputs.PackNamed(tokenId)
+	if err != nil {
+		return "", fmt.Errorf("buy inputs: %w", err)
+	}
+	tx, err := m.c.invokeContract(ctx, "sharedmarketplace", "buy", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+}
+
+// Buyers queries buyers on the contract deployed at location
+func (m *SharedMarketplace) Buyers(ctx context.Context, location string, arg0 *big.Int) (contracts.Address, error) {
+	method := SharedMarketplaceABI().Methods["buyers"]
+	input, err := method.Inputs.PackNamed(arg0)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("buyers inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "sharedmarketplace", "buyers", location, input)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("buyers outputs: %w", err)
+	}
+	return values[0].(contracts.Address), nil
+}
+
+// Cancel invokes cancel on the contract deployed at location
+func (m *SharedMarketplace) Cancel(ctx context.Context, location string, tokenId *big.Int) (string, error) {
+	method := SharedMarketplaceABI().Methods["cancel"]
+	input, err := method.Inputs.PackNamed(tokenId)
+	if err != nil {
+		return "", fmt.Errorf("cancel inputs: %w", err)
+	}
+	tx, err := m.c.invokeContract(ctx, "sharedmarketplace", "cancel", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+}
+
+// Dispute invokes dispute on the contract deployed at location
+func (m *SharedMarketplace) Dispute(ctx context.Context, location string, tokenId *big.Int) (string, error) {
+	method := SharedMarketplaceABI().Methods["dispute"]
+	input, err := method.Inputs.PackNamed(tokenId)
+	if err != nil {
+		return "", fmt.Errorf("dispute inputs: %w", err)
+	}
+	tx, err := m.c.invokeContract(ctx, "sharedmarketplace", "dispute", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+}
+
+// DisputeWindow queries disputeWindow on the contract deployed at location
+func (m *SharedMarketplace) DisputeWindow(ctx context.Context, location string) (*big.Int, error) {
+	method := SharedMarketplaceABI().Methods["disputeWindow"]
+	input, err := method.Inputs.PackNamed()
+	if err != nil {
+		return nil, fmt.Errorf("disputeWindow inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "sharedmarketplace", "disputeWindow", location, input)
+	if err != nil {
+		return nil, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("disputeWindow outputs: %w", err)
+	}
+	return values[0].(*big.Int), nil
+}
+
+// List invokes list on the contract deployed at location
+func (m *SharedMarketplace) List(ctx context.Context, location string, tokenId *big.Int, price *big.Int) (string, error) {
+	method := SharedMarketplaceABI().Methods["list"]
+	input, err := method.Inputs.PackNamed(tokenId, price)
+	if err != nil {
+		return "", fmt.Errorf("list inputs: %w", err)
+	}
+	tx, err := m.c.invokeContract(ctx, "sharedmarketplace", "list", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+}
+
+// Nft queries nft on the contract deployed at location
+func (m *SharedMarketplace) Nft(ctx context.Context, location string) (contracts.Address, error) {
+	method := SharedMarketplaceABI().Methods["nft"]
+	input, err := method.Inputs.PackNamed()
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("nft inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "sharedmarketplace", "nft", location, input)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("nft outputs: %w", err)
+	}
+	return values[0].(contracts.Address), nil
+}
+
+// Offer invokes offer on the contract deployed at location
+func (m *SharedMarketplace) Offer(ctx context.Context, location string, tokenId *big.Int, amount *big.Int) (string, error) {
+	method := SharedMarketplaceABI().Methods["offer"]
+	input, err := method.Inputs.PackNamed(tokenId, amount)
+	if err != nil {
+		return "", fmt.Errorf("offer inputs: %w", err)
+	}
+	tx, err := m.c.invokeContract(ctx, "sharedmarketplace", "offer", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+}
+
+// Offers queries offers on the contract deployed at location
+func (m *SharedMarketplace) Offers(ctx context.Context, location string, arg0 *big.Int, arg1 contracts.Address) (*big.Int, error) {
+	method := SharedMarketplaceABI().Methods["offers"]
+	input, err := method.Inputs.PackNamed(arg0, arg1)
+	if err != nil {
+		return nil, fmt.Errorf("offers inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "sharedmarketplace", "offers", location, input)
+	if err != nil {
+		return nil, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("offers outputs: %w", err)
+	}
+	return values[0].(*big.Int), nil
+}
+
+// Payment queries payment on the contract deployed at location
+func (m *SharedMarketplace) Payment(ctx context.Context, location string) (contracts.Address, error) {
+	method := SharedMarketplaceABI().Methods["payment"]
+	input, err := method.Inputs.PackNamed()
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("payment inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "sharedmarketplace", "payment", location, input)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("payment outputs: %w", err)
+	}
+	return values[0].(contracts.Address), nil
+}
+
+// Prices queries prices on the contract deployed at location
+func (m *SharedMarketplace) Prices(ctx context.Context, location string, arg0 *big.Int) (*big.Int, error) {
+	method := SharedMarketplaceABI().Methods["prices"]
+	input, err := method.Inputs.PackNamed(arg0)
+	if err != nil {
+		return nil, fmt.Errorf("prices inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "sharedmarketplace", "prices", location, input)
+	if err != nil {
+		return nil, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("prices outputs: %w", err)
+	}
+	return values[0].(*big.Int), nil
+}
+
+// Received invokes received on the contract deployed at location
+func (m *SharedMarketplace) Received(ctx context.Context, location string, tokenId *big.Int) (string, error) {
+	method := SharedMarketplaceABI().Methods["received"]
+	input, err := method.Inputs.PackNamed(tokenId)
+	if err != nil {
+		return "", fmt.Errorf("received inputs: %w", err)
+	}
+	tx, err := m.c.invokeContract(ctx, "sharedmarketplace", "received", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+}
+
+// ReceivedAt queries receivedAt on the contract deployed at location
+func (m *SharedMarketplace) ReceivedAt(ctx context.Context, location string, arg0 *big.Int) (*big.Int, error) {
+	method := SharedMarketplaceABI().Methods["receivedAt"]
+	input, err := method.Inputs.PackNamed(arg0)
+	if err != nil {
+		return nil, fmt.Errorf("receivedAt inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "sharedmarketplace", "receivedAt", location, input)
+	if err != nil {
+		return nil, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("receivedAt outputs: %w", err)
+	}
+	return values[0].(*big.Int), nil
+}
+
+// Release invokes release on the contract deployed at location
+func (m *SharedMarketplace) Release(ctx context.Context, location string, tokenId *big.Int) (string, error) {
+	method := SharedMarketplaceABI().Methods["release"]
+	input, err := method.Inputs.PackNamed(tokenId)
+	if err != nil {
+		return "", fmt.Errorf("release inputs: %w", err)
+	}
+	tx, err := m.c.invokeContract(ctx, "sharedmarketplace", "release", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+}
+
+// Resolve invokes resolve on the contract deployed at location
+func (m *SharedMarketplace) Resolve(ctx context.Context, location string, tokenId *big.Int, refund bool) (string, error) {
+	method := SharedMarketplaceABI().Methods["resolve"]
+	input, err := method.Inputs.PackNamed(tokenId, refund)
+	if err != nil {
+		return "", fmt.Errorf("resolve inputs: %w", err)
+	}
+	tx, err := m.c.invokeContract(ctx, "sharedmarketplace", "resolve", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+}
+
+// Sellers queries sellers on the contract deployed at location
+func (m *SharedMarketplace) Sellers(ctx context.Context, location string, arg0 *big.Int) (contracts.Address, error) {
+	method := SharedMarketplaceABI().Methods["sellers"]
+	input, err := method.Inputs.PackNamed(arg0)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("sellers inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "sharedmarketplace", "sellers", location, input)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return contracts.Address{}, fmt.Errorf("sellers outputs: %w", err)
+	}
+	return values[0].(contracts.Address), nil
+}
+
+// SetPrice invokes setPrice on the contract deployed at location
+func (m *SharedMarketplace) SetPrice(ctx context.Context, location string, tokenId *big.Int, price *big.Int) (string, error) {
+	method := SharedMarketplaceABI().Methods["setPrice"]
+	input, err := method.Inputs.PackNamed(tokenId, price)
+	if err != nil {
+		return "", fmt.Errorf("setPrice inputs: %w", err)
+	}
+	tx, err := m.c.invokeContract(ctx, "sharedmarketplace", "setPrice", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+}
+
+// Shipped invokes shipped on the contract deployed at location
+func (m *SharedMarketplace) Shipped(ctx context.Context, location string, tokenId *big.Int) (string, error) {
+	method := SharedMarketplaceABI().Methods["shipped"]
+	input, err := method.Inputs.PackNamed(tokenId)
+	if err != nil {
+		return "", fmt.Errorf("shipped inputs: %w", err)
+	}
+	tx, err := m.c.invokeContract(ctx, "sharedmarketplace", "shipped", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+}
+
+// Statuses queries statuses on the contract deployed at location
+func (m *SharedMarketplace) Statuses(ctx context.Context, location string, arg0 *big.Int) (*big.Int, error) {
+	method := SharedMarketplaceABI().Methods["statuses"]
+	input, err := method.Inputs.PackNamed(arg0)
+	if err != nil {
+		return nil, fmt.Errorf("statuses inputs: %w", err)
+	}
+	output, err := m.c.queryContract(ctx, "sharedmarketplace", "statuses", location, input)
+	if err != nil {
+		return nil, fmt.Errorf("m.c.queryContract: %w", err)
+	}
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("statuses outputs: %w", err)
+	}
+	return values[0].(*big.Int), nil
+}
+
+// WithdrawOffer invokes withdrawOffer on the contract deployed at location
+func (m *SharedMarketplace) WithdrawOffer(ctx context.Context, location string, tokenId *big.Int) (string, error) {
+	method := SharedMarketplaceABI().Methods["withdrawOffer"]
+	input, err := method.Inputs.PackNamed(tokenId)
+	if err != nil {
+		return "", fmt.Errorf("withdrawOffer inputs: %w", err)
+	}
+	tx, err := m.c.invokeContract(ctx, "sharedmarketplace", "withdrawOffer", location, input)
+	if err != nil {
+		return "", fmt.Errorf("m.c.invokeContract: %w", err)
+	}
+	return tx, nil
+}
+
+// SharedMarketplaceDisputeResolvedEvent is the decoded DisputeResolved event
+type SharedMarketplaceDisputeResolvedEvent struct {
+	NftId    *big.Int
+	Refunded bool
+}
+
+// DecodeSharedMarketplaceDisputeResolvedEvent decodes the output of a Firefly blockchain event for DisputeResolved
+func DecodeSharedMarketplaceDisputeResolvedEvent(output map[string]any) (*SharedMarketplaceDisputeResolvedEvent, error) {
+	values, err := SharedMarketplaceABI().Events["DisputeResolved"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("DisputeResolved: %w", err)
+	}
+	return &SharedMarketplaceDisputeResolvedEvent{
+		NftId:    values[0].(*big.Int),
+		Refunded: values[1].(bool),
+	}, nil
+}
+
+// SharedMarketplaceNFTBoughtEvent is the decoded NFTBought event
+type SharedMarketplaceNFTBoughtEvent struct {
+	Buyer  contracts.Address
+	Seller contracts.Address
+	NftId  *big.Int
+	Price  *big.Int
+}
+
+// DecodeSharedMarketplaceNFTBoughtEvent decodes the output of a Firefly blockchain event for NFTBought
+func DecodeSharedMarketplaceNFTBoughtEvent(output map[string]any) (*SharedMarketplaceNFTBoughtEvent, error) {
+	values, err := SharedMarketplaceABI().Events["NFTBought"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("NFTBought: %w", err)
+	}
+	return &SharedMarketplaceNFTBoughtEvent{
+		Buyer:  values[0].(contracts.Address),
+		Seller: values[1].(contracts.Address),
+		NftId:  values[2].(*big.Int),
+		Price:  values[3].(*big.Int),
+	}, nil
+}
+
+// SharedMarketplaceNFTCancelEvent is the decoded NFTCancel event
+type SharedMarketplaceNFTCancelEvent struct {
+	NftId *big.Int
+}
+
+// DecodeSharedMarketplaceNFTCancelEvent decodes the output of a Firefly blockchain event for NFTCancel
+func DecodeSharedMarketplaceNFTCancelEvent(output map[string]any) (*SharedMarketplaceNFTCancelEvent, error) {
+	values, err := SharedMarketplaceABI().Events["NFTCancel"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("NFTCancel: %w", err)
+	}
+	return &SharedMarketplaceNFTCancelEvent{
+		NftId: values[0].(*big.Int),
+	}, nil
+}
+
+// SharedMarketplaceNFTDisputedEvent is the decoded NFTDisputed event
+type SharedMarketplaceNFTDisputedEvent struct {
+	Buyer contracts.Address
+	NftId *big.Int
+}
+
+// DecodeSharedMarketplaceNFTDisputedEvent decodes the output of a Firefly blockchain event for NFTDisputed
+func DecodeSharedMarketplaceNFTDisputedEvent(output map[string]any) (*SharedMarketplaceNFTDisputedEvent, error) {
+	values, err := SharedMarketplaceABI().Events["NFTDisputed"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("NFTDisputed: %w", err)
+	}
+	return &SharedMarketplaceNFTDisputedEvent{
+		Buyer: values[0].(contracts.Address),
+		NftId: values[1].(*big.Int),
+	}, nil
+}
+
+// SharedMarketplaceNFTListedEvent is the decoded NFTListed event
+type SharedMarketplaceNFTListedEvent struct {
+	Seller contracts.Address
+	NftId  *big.Int
+	Price  *big.Int
+}
+
+// DecodeSharedMarketplaceNFTListedEvent decodes the output of a Firefly blockchain event for NFTListed
+func DecodeSharedMarketplaceNFTListedEvent(output map[string]any) (*SharedMarketplaceNFTListedEvent, error) {
+	values, err := SharedMarketplaceABI().Events["NFTListed"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("NFTListed: %w", err)
+	}
+	return &SharedMarketplaceNFTListedEvent{
+		Seller: values[0].(contracts.Address),
+		NftId:  values[1].(*big.Int),
+		Price:  values[2].(*big.Int),
+	}, nil
+}
+
+// SharedMarketplaceNFTReceivedEvent is the decoded NFTReceived event
+type SharedMarketplaceNFTReceivedEvent struct {
+	Buyer contracts.Address
+	NftId *big.Int
+}
+
+// DecodeSharedMarketplaceNFTReceivedEvent decodes the output of a Firefly blockchain event for NFTReceived
+func DecodeSharedMarketplaceNFTReceivedEvent(output map[string]any) (*SharedMarketplaceNFTReceivedEvent, error) {
+	values, err := SharedMarketplaceABI().Events["NFTReceived"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("NFTReceived: %w", err)
+	}
+	return &SharedMarketplaceNFTReceivedEvent{
+		Buyer: values[0].(contracts.Address),
+		NftId: values[1].(*big.Int),
+	}, nil
+}
+
+// SharedMarketplaceNFTShippedEvent is the decoded NFTShipped event
+type SharedMarketplaceNFTShippedEvent struct {
+	Seller contracts.Address
+	NftId  *big.Int
+}
+
+// DecodeSharedMarketplaceNFTShippedEvent decodes the output of a Firefly blockchain event for NFTShipped
+func DecodeSharedMarketplaceNFTShippedEvent(output map[string]any) (*SharedMarketplaceNFTShippedEvent, error) {
+	values, err := SharedMarketplaceABI().Events["NFTShipped"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("NFTShipped: %w", err)
+	}
+	return &SharedMarketplaceNFTShippedEvent{
+		Seller: values[0].(contracts.Address),
+		NftId:  values[1].(*big.Int),
+	}, nil
+}
+
+// SharedMarketplaceOfferMadeEvent is the decoded OfferMade event
+type SharedMarketplaceOfferMadeEvent struct {
+	Buyer  contracts.Address
+	NftId  *big.Int
+	Amount *big.Int
+}
+
+// DecodeSharedMarketplaceOfferMadeEvent decodes the output of a Firefly blockchain event for OfferMade
+func DecodeSharedMarketplaceOfferMadeEvent(output map[string]any) (*SharedMarketplaceOfferMadeEvent, error) {
+	values, err := SharedMarketplaceABI().Events["OfferMade"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("OfferMade: %w", err)
+	}
+	return &SharedMarketplaceOfferMadeEvent{
+		Buyer:  values[0].(contracts.Address),
+		NftId:  values[1].(*big.Int),
+		Amount: values[2].(*big.Int),
+	}, nil
+}
+
+// SharedMarketplaceOfferWithdrawnEvent is the decoded OfferWithdrawn event
+type SharedMarketplaceOfferWithdrawnEvent struct {
+	Buyer  contracts.Address
+	NftId  *big.Int
+	Amount *big.Int
+}
+
+// DecodeSharedMarketplaceOfferWithdrawnEvent decodes the output of a Firefly blockchain event for OfferWithdrawn
+func DecodeSharedMarketplaceOfferWithdrawnEvent(output map[string]any) (*SharedMarketplaceOfferWithdrawnEvent, error) {
+	values, err := SharedMarketplaceABI().Events["OfferWithdrawn"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("OfferWithdrawn: %w", err)
+	}
+	return &SharedMarketplaceOfferWithdrawnEvent{
+		Buyer:  values[0].(contracts.Address),
+		NftId:  values[1].(*big.Int),
+		Amount: values[2].(*big.Int),
+	}, nil
+}
+
+// SharedMarketplacePaymentRefundedEvent is the decoded PaymentRefunded event
+type SharedMarketplacePaymentRefundedEvent struct {
+	Buyer  contracts.Address
+	NftId  *big.Int
+	Amount *big.Int
+}
+
+// DecodeSharedMarketplacePaymentRefundedEvent decodes the output of a Firefly blockchain event for PaymentRefunded
+func DecodeSharedMarketplacePaymentRefundedEvent(output map[string]any) (*SharedMarketplacePaymentRefundedEvent, error) {
+	values, err := SharedMarketplaceABI().Events["PaymentRefunded"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("PaymentRefunded: %w", err)
+	}
+	return &SharedMarketplacePaymentRefundedEvent{
+		Buyer:  values[0].(contracts.Address),
+		NftId:  values[1].(*big.Int),
+		Amount: values[2].(*big.Int),
+	}, nil
+}
+
+// SharedMarketplacePaymentReleasedEvent is the decoded PaymentReleased event
+type SharedMarketplacePaymentReleasedEvent struct {
+	Seller contracts.Address
+	NftId  *big.Int
+	Amount *big.Int
+}
+
+// DecodeSharedMarketplacePaymentReleasedEvent decodes the output of a Firefly blockchain event for PaymentReleased
+func DecodeSharedMarketplacePaymentReleasedEvent(output map[string]any) (*SharedMarketplacePaymentReleasedEvent, error) {
+	values, err := SharedMarketplaceABI().Events["PaymentReleased"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("PaymentReleased: %w", err)
+	}
+	return &SharedMarketplacePaymentReleasedEvent{
+		Seller: values[0].(contracts.Address),
+		NftId:  values[1].(*big.Int),
+		Amount: values[2].(*big.Int),
+	}, nil
+}
+
+// SharedMarketplacePriceChangedEvent is the decoded PriceChanged event
+type SharedMarketplacePriceChangedEvent struct {
+	NftId    *big.Int
+	OldPrice *big.Int
+	NewPrice *big.Int
+}
+
+// DecodeSharedMarketplacePriceChangedEvent decodes the output of a Firefly blockchain event for PriceChanged
+func DecodeSharedMarketplacePriceChangedEvent(output map[string]any) (*SharedMarketplacePriceChangedEvent, error) {
+	values, err := SharedMarketplaceABI().Events["PriceChanged"].Inputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("PriceChanged: %w", err)
+	}
+	return &SharedMarketplacePriceChangedEvent{
+		NftId:    values[0].(*big.Int),
+		OldPrice: values[1].(*big.Int),
+		NewPrice: values[2].(*big.Int),
+	}, nil
+}
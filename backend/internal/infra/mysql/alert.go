@@ -0,0 +1,384 @@
+package mysql
+
+import (
+	"backend/internal/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreateSavedSearch(ctx context.Context, s *domain.SavedSearch) error {
+	if s == nil {
+		return fmt.Errorf("CreateSavedSearch called with nil search data")
+	}
+	attributes, err := json.Marshal(s.Attributes)
+	if err != nil {
+		return fmt.Errorf("json.Marshal attributes: %w", err)
+	}
+	var maxPrice sql.NullString
+	var maxCurrency string
+	if s.MaxPrice != nil {
+		maxPrice = sql.NullString{String: s.MaxPrice.Amount().String(), Valid: true}
+		maxCurrency = s.MaxPrice.Currency()
+	}
+
+	s.ID = uuid.NewString()
+	s.CreatedAt = time.Now().UTC()
+	insertQuery := "INSERT INTO saved_search (id, user_id, name, query, category, min_condition, max_price, max_currency, attributes, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, s.ID, s.UserID, s.Name, s.Query, s.Category, s.MinCondition, maxPrice, maxCurrency, attributes, s.CreatedAt); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, s.ID, err)
+	}
+	return nil
+}
+
+const selectSavedSearch = "SELECT id, user_id, name, query, category, min_condition, max_price, max_currency, attributes, created_at FROM saved_search"
+
+func (c *Client) GetSavedSearchByID(ctx context.Context, id string) (*domain.SavedSearch, error) {
+	query := selectSavedSearch + " WHERE id = ?"
+	s, err := scanSavedSearch(c.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("c.db.QueryRowContext on (%s) with id (%s): %w", query, id, err)
+	}
+	return s, nil
+}
+
+func (c *Client) ListSavedSearchesByUserID(ctx context.Context, userID string) ([]*domain.SavedSearch, error) {
+	return c.listSavedSearches(ctx, selectSavedSearch+" WHERE user_id = ? ORDER BY created_at", userID)
+}
+
+// ListSavedSearches returns the searches of every user, which the matcher checks each listing against
+func (c *Client) ListSavedSearches(ctx context.Context) ([]*domain.SavedSearch, error) {
+	return c.listSavedSearches(ctx, selectSavedSearch)
+}
+
+func (c *Client) listSavedSearches(ctx context.Context, query string, args ...any) ([]*domain.SavedSearch, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s): %w", query, err)
+	}
+	defer rows.Close()
+
+	var searches []*domain.SavedSearch
+	for rows.Next() {
+		s, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanSavedSearch: %w", err)
+		}
+		searches = append(searches, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err on (%s): %w", query, err)
+	}
+	return searches, nil
+}
+
+func scanSavedSearch(row scanner) (*domain.SavedSearch, error) {
+	var s domain.SavedSearch
+	var maxPrice sql.NullString
+	var maxCurrency string
+	var attributes []byte
+	if err := row.Scan(&s.ID, &s.UserID, &s.Name, &s.Query, &s.Category, &s.MinCondition, &maxPrice, &maxCurrency, &attributes, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	if maxPrice.Valid {
+		price, err := domain.ParseMoney(maxPrice.String, maxCurrency)
+		if err != nil {
+			return nil, fmt.Errorf("max price of search (%s): %w", s.ID, err)
+		}
+		s.MaxPrice = &price
+	}
+	if len(attributes) > 0 {
+		if err := json.Unmarshal(attributes, &s.Attributes); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal attributes of search (%s): %w", s.ID, err)
+		}
+	}
+	return &s, nil
+}
+
+// DeleteSavedSearch removes the search along with the record of the items it matched
+func (c *Client) DeleteSavedSearch(ctx context.Context, id string) error {
+	deleteQuery := "DELETE FROM search_match WHERE search_id = ?"
+	if _, err := c.db.ExecContext(ctx, deleteQuery, id); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", deleteQuery, id, err)
+	}
+	deleteQuery = "DELETE FROM saved_search WHERE id = ?"
+	if _, err := c.db.ExecContext(ctx, deleteQuery, id); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", deleteQuery, id, err)
+	}
+	return nil
+}
+
+// RecordSearchMatch remembers that the search matched the item, and reports false when it already had
+func (c *Client) RecordSearchMatch(ctx context.Context, searchID, itemID string) (bool, error) {
+	insertQuery := "INSERT IGNORE INTO search_match (search_id, item_id) VALUES (?, ?)"
+	res, err := c.db.ExecContext(ctx, insertQuery, searchID, itemID)
+	if err != nil {
+		return false, fmt.Errorf("c.db.ExecContext on (%s) with search id (%s): %w", insertQuery, searchID, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("res.RowsAffected on (%s) with search id (%s): %w", insertQuery, searchID, err)
+	}
+	return n > 0, nil
+}
+
+// UpsertWatchedItem adds the item to the user's watchlist, or resets the price a drop is measured from
+func (c *Client) UpsertWatchedItem(ctx context.Context, w *domain.WatchedItem) error {
+	if w == nil {
+		return fmt.Errorf("UpsertWatchedItem called with nil watched item data")
+	}
+	w.CreatedAt = time.Now().UTC()
+	upsertQuery := "INSERT INTO watched_item (user_id, item_id, price, currency, created_at) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE price = VALUES(price), currency = VALUES(currency)"
+	if _, err := c.db.ExecContext(ctx, upsertQuery, w.UserID, w.ItemID, w.Price.Amount().String(), w.Price.Currency(), w.CreatedAt); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with item id (%s): %w", upsertQuery, w.ItemID, err)
+	}
+	return nil
+}
+
+func (c *Client) UpdateWatchedItemPrice(ctx context.Context, userID, itemID string, price domain.Money) error {
+	updateQuery := "UPDATE watched_item SET price = ?, currency = ? WHERE user_id = ? AND item_id = ?"
+	if _, err := c.db.ExecContext(ctx, updateQuery, price.Amount().String(), price.Currency(), userID, itemID); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with item id (%s): %w", updateQuery, itemID, err)
+	}
+	return nil
+}
+
+func (c *Client) DeleteWatchedItem(ctx context.Context, userID, itemID string) error {
+	deleteQuery := "DELETE FROM watched_item WHERE user_id = ? AND item_id = ?"
+	res, err := c.db.ExecContext(ctx, deleteQuery, userID, itemID)
+	if err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with item id (%s): %w", deleteQuery, itemID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("res.RowsAffected on (%s) with item id (%s): %w", deleteQuery, itemID, err)
+	} else if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+const selectWatchedItem = "SELECT user_id, item_id, price, currency, created_at FROM watched_item"
+
+func (c *Client) ListWatchedItemsByUserID(ctx context.Context, userID string) ([]*domain.WatchedItem, error) {
+	return c.listWatchedItems(ctx, selectWatchedItem+" WHERE user_id = ? ORDER BY created_at", userID)
+}
+
+func (c *Client) ListWatchedItemsByItemID(ctx context.Context, itemID string) ([]*domain.WatchedItem, error) {
+	return c.listWatchedItems(ctx, selectWatchedItem+" WHERE item_id = ?", itemID)
+}
+
+func (c *Client) listWatchedItems(ctx context.Context, query string, args ...any) ([]*domain.WatchedItem, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s): %w", query, err)
+	}
+	defer rows.Close()
+
+	var watched []*domain.WatchedItem
+	for rows.Next() {
+		var w domain.WatchedItem
+		var price, currency string
+		if err := rows.Scan(&w.UserID, &w.ItemID, &price, &currency, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		if w.Price, err = domain.ParseMoney(price, currency); err != nil {
+			return nil, fmt.Errorf("price of watched item (%s): %w", w.ItemID, err)
+		}
+		watched = append(watched, &w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err on (%s): %w", query, err)
+	}
+	return watched, nil
+}
+
+func (c *Client) CreateNotification(ctx context.Context, n *domain.Notification) error {
+	if n == nil {
+		return fmt.Errorf("CreateNotification called with nil notification data")
+	}
+	var previousPrice sql.NullString
+	if n.PreviousPrice != nil {
+		previousPrice = sql.NullString{String: n.PreviousPrice.Amount().String(), Valid: true}
+	}
+
+	n.ID = uuid.NewString()
+	n.CreatedAt = time.Now().UTC()
+	insertQuery := "INSERT INTO notification (id, user_id, kind, item_id, item_name, price, currency, search_id, previous_price, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, n.ID, n.UserID, n.Kind, n.ItemID, n.ItemName, n.Price.Amount().String(), n.Price.Currency(), n.SearchID, previousPrice, n.CreatedAt); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, n.ID, err)
+	}
+	return nil
+}
+
+const selectNotification = "SELECT id, user_id, kind, item_id, item_name, price, currency, search_id, previous_price, created_at, sent_at FROM notification"
+
+// ListNotificationsByUserID returns the user's latest notifications, newest first
+func (c *Client) ListNotificationsByUserID(ctx context.Context, userID string, limit int) ([]*domain.Notification, error) {
+	return c.listNotifications(ctx, selectNotification+" WHERE user_id = ? ORDER BY created_at DESC LIMIT ?", userID, limit)
+}
+
+// ListPendingNotifications returns the notifications not sent to the user yet, oldest first
+func (c *Client) ListPendingNotifications(ctx context.Context, userID string) ([]*domain.Notification, error) {
+	return c.listNotifications(ctx, selectNotification+" WHERE user_id = ? AND sent_at IS NULL ORDER BY created_at", userID)
+}
+
+func (c *Client) listNotifications(ctx context.Context, query string, args ...any) ([]*domain.Notification, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s): %w", query, err)
+	}
+	defer rows.Close()
+
+	var notifications []*domain.Notification
+	for rows.Next() {
+		var n domain.Notification
+		var price, currency string
+		var previousPrice sql.NullString
+		var sentAt sql.NullTime
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.ItemID, &n.ItemName, &price, &currency, &n.SearchID, &previousPrice, &n.CreatedAt, &sentAt); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		if n.Price, err = domain.ParseMoney(price, currency); err != nil {
+			return nil, fmt.Errorf("price of notification (%s): %w", n.ID, err)
+		}
+		if previousPrice.Valid {
+			previous, err := domain.ParseMoney(previousPrice.String, currency)
+			if err != nil {
+				return nil, fmt.Errorf("previous price of notification (%s): %w", n.ID, err)
+			}
+			n.PreviousPrice = &previous
+		}
+		if sentAt.Valid {
+			n.SentAt = &sentAt.Time
+		}
+		notifications = append(notifications, &n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err on (%s): %w", query, err)
+	}
+	return notifications, nil
+}
+
+// ListUsersWithPendingNotifications returns the users who have notifications waiting to be sent
+func (c *Client) ListUsersWithPendingNotifications(ctx context.Context) ([]string, error) {
+	query := "SELECT DISTINCT user_id FROM notification WHERE sent_at IS NULL"
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s): %w", query, err)
+	}
+	defer rows.Close()
+
+	var users []string
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		users = append(users, uid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err on (%s): %w", query, err)
+	}
+	return users, nil
+}
+
+func (c *Client) MarkNotificationsSent(ctx context.Context, ids []string, sentAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	updateQuery := "UPDATE notification SET sent_at = ? WHERE id IN (?" + strings.Repeat(", ?", len(ids)-1) + ")"
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, sentAt)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	if _, err := c.db.ExecContext(ctx, updateQuery, args...); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s): %w", updateQuery, err)
+	}
+	return nil
+}
+
+func (c *Client) GetAlertSettings(ctx context.Context, userID string) (*domain.AlertSettings, error) {
+	var s domain.AlertSettings
+	var lastDigestAt sql.NullTime
+	query := "SELECT user_id, frequency, channel, address, last_digest_at FROM alert_settings WHERE user_id = ?"
+	if err := c.db.QueryRowContext(ctx, query, userID).Scan(&s.UserID, &s.Frequency, &s.Channel, &s.Address, &lastDigestAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("c.db.QueryRowContext on (%s) with user id (%s): %w", query, userID, err)
+	}
+	if lastDigestAt.Valid {
+		s.LastDigestAt = &lastDigestAt.Time
+	}
+	return &s, nil
+}
+
+// UpsertAlertSettings stores how the user wants their notifications, keeping when their last digest was sent
+func (c *Client) UpsertAlertSettings(ctx context.Context, s *domain.AlertSettings) error {
+	upsertQuery := "INSERT INTO alert_settings (user_id, frequency, channel, address) VALUES (?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE frequency = VALUES(frequency), channel = VALUES(channel), address = VALUES(address)"
+	if _, err := c.db.ExecContext(ctx, upsertQuery, s.UserID, s.Frequency, s.Channel, s.Address); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with user id (%s): %w", upsertQuery, s.UserID, err)
+	}
+	return nil
+}
+
+func (c *Client) UpdateLastDigestAt(ctx context.Context, userID string, at time.Time) error {
+	upsertQuery := "INSERT INTO alert_settings (user_id, frequency, channel, address, last_digest_at) VALUES (?, ?, ?, '', ?) ON DUPLICATE KEY UPDATE last_digest_at = VALUES(last_digest_at)"
+	if _, err := c.db.ExecContext(ctx, upsertQuery, userID, domain.DigestFrequencyImmediate, domain.DefaultAlertChannel, at); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with user id (%s): %w", upsertQuery, userID, err)
+	}
+	return nil
+}
+
+// CreateAlertJob queues a listing change for the matcher
+func (c *Client) CreateAlertJob(ctx context.Context, job *domain.AlertJob) error {
+	job.ID = uuid.NewString()
+	job.CreatedAt = time.Now().UTC()
+	insertQuery := "INSERT INTO alert_job (id, item_id, event_type, created_at) VALUES (?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, job.ID, job.ItemID, job.EventType, job.CreatedAt); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with item id (%s): %w", insertQuery, job.ItemID, err)
+	}
+	return nil
+}
+
+// ListAlertJobs returns the oldest queued listing changes
+func (c *Client) ListAlertJobs(ctx context.Context, limit int) ([]*domain.AlertJob, error) {
+	query := "SELECT id, item_id, event_type, created_at FROM alert_job ORDER BY created_at LIMIT ?"
+	rows, err := c.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s): %w", query, err)
+	}
+	defer rows.Close()
+
+	var jobs []*domain.AlertJob
+	for rows.Next() {
+		var job domain.AlertJob
+		if err := rows.Scan(&job.ID, &job.ItemID, &job.EventType, &job.CreatedAt); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		jobs = append(jobs, &job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err on (%s): %w", query, err)
+	}
+	return jobs, nil
+}
+
+func (c *Client) DeleteAlertJob(ctx context.Context, id string) error {
+	deleteQuery := "DELETE FROM alert_job WHERE id = ?"
+	if _, err := c.db.ExecContext(ctx, deleteQuery, id); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", deleteQuery, id, err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"backend/internal/domain"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -20,15 +21,68 @@ func New(db *sql.DB) *Client {
 	}
 }
 
+const selectItem = "SELECT id, item_name, item_state, item_price, item_currency, nft_id, smart_contract_address, seller_id, buyer_id, creator_id, pool_name, category, item_condition, attributes, images FROM listing"
+
 func (c *Client) GetItemByID(ctx context.Context, id string) (*domain.Item, error) {
-	var item domain.Item
-	query := "SELECT id, item_name, item_state, item_price, nft_id, smart_contract_address FROM listing WHERE id = ?"
-	if err := c.db.QueryRow(query, id).Scan(&item.ID, &item.Name, &item.State, &item.Price, &item.NFTID, &item.SmartContractAddress); err != nil {
-		if errors.As(err, sql.ErrNoRows) {
+	query := selectItem + " WHERE id = ?"
+	item, err := scanItem(c.db.QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
 		return nil, fmt.Errorf("c.db.QueryRow on (%s) with id (%s): %w", query, id, err)
 	}
+	return item, nil
+}
+
+// ListItemsByNFTID returns the items whose NFT has the token index, which may be in different pools
+func (c *Client) ListItemsByNFTID(ctx context.Context, nftID string) ([]*domain.Item, error) {
+	query := selectItem + " WHERE nft_id = ? ORDER BY created_at"
+	rows, err := c.db.QueryContext(ctx, query, nftID)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s) with nft id (%s): %w", query, nftID, err)
+	}
+	defer rows.Close()
+
+	var items []*domain.Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanItem: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+	return items, nil
+}
+
+func scanItem(row scanner) (*domain.Item, error) {
+	var item domain.Item
+	var state, price, currency string
+	var attributes, images []byte
+	if err := row.Scan(&item.ID, &item.Name, &state, &price, &currency, &item.NFTID, &item.SmartContractAddress, &item.SellerID, &item.BuyerID, &item.CreatorID, &item.PoolName, &item.Category, &item.Condition, &attributes, &images); err != nil {
+		return nil, err
+	}
+	var err error
+	if item.State, err = domain.ParseItemState(state); err != nil {
+		return nil, fmt.Errorf("state of item (%s): %w", item.ID, err)
+	}
+	if item.Price, err = domain.ParseMoney(price, currency); err != nil {
+		return nil, fmt.Errorf("price of item (%s): %w", item.ID, err)
+	}
+	// Items listed before attributes and images existed have none stored
+	if len(attributes) > 0 {
+		if err := json.Unmarshal(attributes, &item.Attributes); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal attributes of item (%s): %w", item.ID, err)
+		}
+	}
+	if len(images) > 0 {
+		if err := json.Unmarshal(images, &item.Images); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal images of item (%s): %w", item.ID, err)
+		}
+	}
 	return &item, nil
 }
 
@@ -39,19 +93,35 @@ func (c *Client) CreateItem(ctx context.Context, item *domain.Item) error {
 
 	item.ID = uuid.NewString()
 	insertQuery := "INSERT INTO items (id, name, state) VALUES (?, ?, ?)"
-	if _, err := c.db.ExecContext(ctx, insertQuery, item.ID, item.Name, item.State); err != nil {
+	if _, err := c.db.ExecContext(ctx, insertQuery, item.ID, item.Name, item.State.String()); err != nil {
 		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, item.ID, err)
 	}
 	return nil
 }
 
-func (c *Client) UpdateItem(ctx context.Context, item *domain.Item) error {
+// UpdateItem stores item if it is still in the from state, so that of two concurrent transitions out of
+// the same state only the first is stored. The other fails with domain.ErrInvalidTransition.
+func (c *Client) UpdateItem(ctx context.Context, item *domain.Item, from domain.ItemState) error {
 	if item == nil {
 		return fmt.Errorf("UpdateItem called with nil item data")
 	}
-	updateQuery := "UPDATE listing SET item_name = ?, item_state = ?, item_price = ?, nft_id = ?, smart_contract_address = ? WHERE id = ?"
-	if _, err := c.db.ExecContext(ctx, updateQuery, item.Name, item.State, item.Price, item.NFTID, item.SmartContractAddress, item.ID); err != nil {
-		return fmt.Errorf("c.db.QueryRow on (%s) with id (%s): %w", updateQuery, item.ID, err)
+	attributes, err := json.Marshal(item.Attributes)
+	if err != nil {
+		return fmt.Errorf("json.Marshal attributes: %w", err)
+	}
+	images, err := json.Marshal(item.Images)
+	if err != nil {
+		return fmt.Errorf("json.Marshal images: %w", err)
+	}
+	updateQuery := "UPDATE listing SET item_name = ?, item_state = ?, item_price = ?, item_currency = ?, nft_id = ?, smart_contract_address = ?, seller_id = ?, buyer_id = ?, creator_id = ?, pool_name = ?, category = ?, item_condition = ?, attributes = ?, images = ? WHERE id = ? AND item_state = ?"
+	res, err := c.db.ExecContext(ctx, updateQuery, item.Name, item.State.String(), item.Price.Amount().String(), item.Price.Currency(), item.NFTID, item.SmartContractAddress, item.SellerID, item.BuyerID, item.CreatorID, item.PoolName, item.Category, item.Condition, attributes, images, item.ID, from.String())
+	if err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", updateQuery, item.ID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("res.RowsAffected on (%s) with id (%s): %w", updateQuery, item.ID, err)
+	} else if n == 0 {
+		return fmt.Errorf("item (%s) is no longer %s: %w", item.ID, from, domain.ErrInvalidTransition)
 	}
 	return nil
 }
@@ -61,14 +131,21 @@ func (c *Client) CreateOrUpdateItem(ctx context.Context, item *domain.Item) erro
 		return fmt.Errorf("CreateOrUpdateItem called with nil item data")
 	}
 
+	attributes, err := json.Marshal(item.Attributes)
+	if err != nil {
+		return fmt.Errorf("json.Marshal attributes: %w", err)
+	}
+	images, err := json.Marshal(item.Images)
+	if err != nil {
+		return fmt.Errorf("json.Marshal images: %w", err)
+	}
 	selectQuery := "SELECT id FROM listing WHERE id = ?"
 	var isCreated bool
 	var id string
-	item.State = domain.ItemStateListed
 	if err := c.db.QueryRow(selectQuery, item.ID).Scan(&id); errors.Is(err, sql.ErrNoRows) {
-		insertQuery := "INSERT INTO listing (id, item_name, item_state, item_price, smart_contract_address, nft_id) VALUES (?, ?, ?, ?, ?, ?)"
+		insertQuery := "INSERT INTO listing (id, item_name, item_state, item_price, item_currency, smart_contract_address, nft_id, seller_id, buyer_id, creator_id, pool_name, category, item_condition, attributes, images) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
 		item.ID = uuid.NewString()
-		if _, err := c.db.ExecContext(ctx, insertQuery, item.ID, item.Name, item.State, item.Price, item.SmartContractAddress, item.NFTID); err != nil {
+		if _, err := c.db.ExecContext(ctx, insertQuery, item.ID, item.Name, item.State.String(), item.Price.Amount().String(), item.Price.Currency(), item.SmartContractAddress, item.NFTID, item.SellerID, item.BuyerID, item.CreatorID, item.PoolName, item.Category, item.Condition, attributes, images); err != nil {
 			return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, item.ID, err)
 		}
 		isCreated = true
@@ -80,8 +157,8 @@ func (c *Client) CreateOrUpdateItem(ctx context.Context, item *domain.Item) erro
 		return nil
 	}
 
-	updateQuery := "UPDATE listing SET item_name = ?, item_state = ?, item_price = ?, smart_contract_address = ?, nft_id = ? WHERE id = ?"
-	if _, err := c.db.ExecContext(ctx, updateQuery, item.Name, item.State, item.Price, item.SmartContractAddress, item.NFTID, item.ID); err != nil {
+	updateQuery := "UPDATE listing SET item_name = ?, item_state = ?, item_price = ?, item_currency = ?, smart_contract_address = ?, nft_id = ?, seller_id = ?, buyer_id = ?, creator_id = ?, pool_name = ?, category = ?, item_condition = ?, attributes = ?, images = ? WHERE id = ?"
+	if _, err := c.db.ExecContext(ctx, updateQuery, item.Name, item.State.String(), item.Price.Amount().String(), item.Price.Currency(), item.SmartContractAddress, item.NFTID, item.SellerID, item.BuyerID, item.CreatorID, item.PoolName, item.Category, item.Condition, attributes, images, item.ID); err != nil {
 		return fmt.Errorf("c.db.QueryRow on (%s) with id (%s): %w", updateQuery, item.ID, err)
 	}
 	return nil
@@ -0,0 +1,91 @@
+package mysql
+
+import (
+	"backend/internal/domain"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// listingRow is the part of a listing row the fake driver keeps
+type listingRow struct {
+	state string
+	buyer string
+}
+
+// listingDB is a database/sql driver over an in-memory listing table. It only runs UpdateItem's statement,
+// applying it the way MySQL would: to the row with the id, and only while it is in the expected state.
+type listingDB struct {
+	mu   sync.Mutex
+	rows map[string]listingRow
+}
+
+func (d *listingDB) Connect(context.Context) (driver.Conn, error) { return &listingConn{d}, nil }
+func (d *listingDB) Driver() driver.Driver                        { return nil }
+
+type listingConn struct{ db *listingDB }
+
+func (c *listingConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *listingConn) Close() error                        { return nil }
+func (c *listingConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *listingConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if !strings.HasPrefix(query, "UPDATE listing SET item_name = ?, item_state = ?,") || !strings.HasSuffix(query, "WHERE id = ? AND item_state = ?") {
+		return nil, fmt.Errorf("unexpected query (%s)", query)
+	}
+	id, from := args[len(args)-2].Value.(string), args[len(args)-1].Value.(string)
+
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	row, ok := c.db.rows[id]
+	if !ok || row.state != from {
+		return driver.RowsAffected(0), nil
+	}
+	c.db.rows[id] = listingRow{state: args[1].Value.(string), buyer: args[7].Value.(string)}
+	return driver.RowsAffected(1), nil
+}
+
+func TestUpdateItemRejectsStaleCopies(t *testing.T) {
+	db := &listingDB{rows: map[string]listingRow{"item": {state: domain.ItemStateListed.String()}}}
+	c := New(sql.OpenDB(db))
+	ctx := context.Background()
+
+	// Two purchases load the listed item before either of them stores the sale
+	loaded := domain.Item{ID: "item", State: domain.ItemStateListed, SellerID: "alice"}
+	first, second := loaded, loaded
+	buy := func(item *domain.Item, buyer string) (*domain.Transition, error) {
+		tr, err := item.Transition(domain.ItemActionPurchase, buyer)
+		if err != nil {
+			return nil, err
+		}
+		if err := tr.Apply(item); err != nil {
+			return nil, err
+		}
+		item.BuyerID = buyer
+		return tr, nil
+	}
+	firstTr, err := buy(&first, "bob")
+	if err != nil {
+		t.Fatalf("first purchase: %v", err)
+	}
+	secondTr, err := buy(&second, "carol")
+	if err != nil {
+		t.Fatalf("second purchase: %v", err)
+	}
+
+	if err := c.UpdateItem(ctx, &first, firstTr.From); err != nil {
+		t.Fatalf("UpdateItem of the first purchase = %v, want nil", err)
+	}
+	if err := c.UpdateItem(ctx, &second, secondTr.From); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("UpdateItem of the second purchase = %v, want %v", err, domain.ErrInvalidTransition)
+	}
+	want := listingRow{state: domain.ItemStateSold.String(), buyer: "bob"}
+	if got := db.rows["item"]; got != want {
+		t.Errorf("stored listing = %+v, want %+v", got, want)
+	}
+}
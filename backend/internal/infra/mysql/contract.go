@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"backend/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// CreateContractDeployment records a deployment, leaving an existing record for the same address untouched
+func (c *Client) CreateContractDeployment(ctx context.Context, d *domain.ContractDeployment) error {
+	if d == nil {
+		return fmt.Errorf("CreateContractDeployment called with nil deployment data")
+	}
+	insertQuery := "INSERT IGNORE INTO contract_registry (address, contract_name, code_hash, abi_hash, compiler_version, deploy_tx_id, deployed_by, pool_name, nft_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, d.Address, d.ContractName, d.CodeHash, d.ABIHash, d.CompilerVersion, d.DeployTxID, d.DeployedBy, d.PoolName, d.NFTID); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with address (%s): %w", insertQuery, d.Address, err)
+	}
+	return nil
+}
+
+const selectContractDeployment = "SELECT address, contract_name, code_hash, abi_hash, compiler_version, deploy_tx_id, deployed_by, pool_name, nft_id, created_at FROM contract_registry"
+
+func (c *Client) GetContractDeploymentByAddress(ctx context.Context, address string) (*domain.ContractDeployment, error) {
+	query := selectContractDeployment + " WHERE address = ?"
+	d, err := scanContractDeployment(c.db.QueryRowContext(ctx, query, address))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("c.db.QueryRowContext on (%s) with address (%s): %w", query, address, err)
+	}
+	return d, nil
+}
+
+// ListContractDeploymentsByNFTID returns the contracts deployed per listing for the token index, which may be
+// in different pools, oldest first
+func (c *Client) ListContractDeploymentsByNFTID(ctx context.Context, nftID string) ([]*domain.ContractDeployment, error) {
+	query := selectContractDeployment + " WHERE nft_id = ? ORDER BY created_at"
+	rows, err := c.db.QueryContext(ctx, query, nftID)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s) with nft id (%s): %w", query, nftID, err)
+	}
+	defer rows.Close()
+
+	var deployments []*domain.ContractDeployment
+	for rows.Next() {
+		d, err := scanContractDeployment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanContractDeployment: %w", err)
+		}
+		deployments = append(deployments, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err on (%s): %w", query, err)
+	}
+	return deployments, nil
+}
+
+func scanContractDeployment(row scanner) (*domain.ContractDeployment, error) {
+	var d domain.ContractDeployment
+	if err := row.Scan(&d.Address, &d.ContractName, &d.CodeHash, &d.ABIHash, &d.CompilerVersion, &d.DeployTxID, &d.DeployedBy, &d.PoolName, &d.NFTID, &d.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
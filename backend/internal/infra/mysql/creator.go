@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RecordNFTCreator records creatorID as the creator of the NFT unless one was recorded before, and returns
+// whoever is recorded. The NFT is identified by its pool and token index, as token indexes repeat across pools.
+func (c *Client) RecordNFTCreator(ctx context.Context, poolName, nftID, creatorID string) (string, error) {
+	insertQuery := "INSERT IGNORE INTO nft_creator (pool_name, nft_id, creator_id, created_at) VALUES (?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, poolName, nftID, creatorID, time.Now().UTC()); err != nil {
+		return "", fmt.Errorf("c.db.ExecContext on (%s) with nft id (%s): %w", insertQuery, nftID, err)
+	}
+	selectQuery := "SELECT creator_id FROM nft_creator WHERE pool_name = ? AND nft_id = ?"
+	var recorded string
+	if err := c.db.QueryRowContext(ctx, selectQuery, poolName, nftID).Scan(&recorded); err != nil {
+		return "", fmt.Errorf("c.db.QueryRowContext on (%s) with nft id (%s): %w", selectQuery, nftID, err)
+	}
+	return recorded, nil
+}
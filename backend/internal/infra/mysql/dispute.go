@@ -0,0 +1,139 @@
+package mysql
+
+import (
+	"backend/internal/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreateDispute(ctx context.Context, d *domain.Dispute) error {
+	if d == nil {
+		return fmt.Errorf("CreateDispute called with nil dispute data")
+	}
+
+	d.ID = uuid.NewString()
+	d.CreatedAt = time.Now().UTC()
+	d.UpdatedAt = d.CreatedAt
+	insertQuery := "INSERT INTO dispute (id, item_id, buyer_id, seller_id, reason, state, resolved_by, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, d.ID, d.ItemID, d.BuyerID, d.SellerID, d.Reason, d.State, d.ResolvedBy, d.CreatedAt, d.UpdatedAt); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, d.ID, err)
+	}
+	return nil
+}
+
+const selectDispute = "SELECT id, item_id, buyer_id, seller_id, reason, state, resolved_by, created_at, updated_at FROM dispute"
+
+func (c *Client) GetDisputeByID(ctx context.Context, id string) (*domain.Dispute, error) {
+	query := selectDispute + " WHERE id = ?"
+	d, err := scanDispute(c.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("c.db.QueryRowContext on (%s) with id (%s): %w", query, id, err)
+	}
+	return d, nil
+}
+
+// ListDisputesByState returns the disputes in state, oldest first so that they are arbitrated in order
+func (c *Client) ListDisputesByState(ctx context.Context, state domain.DisputeState) ([]*domain.Dispute, error) {
+	query := selectDispute + " WHERE state = ? ORDER BY created_at"
+	rows, err := c.db.QueryContext(ctx, query, state)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s) with state (%s): %w", query, state, err)
+	}
+	defer rows.Close()
+
+	var disputes []*domain.Dispute
+	for rows.Next() {
+		d, err := scanDispute(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanDispute: %w", err)
+		}
+		disputes = append(disputes, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+	return disputes, nil
+}
+
+// UpdateDisputeState moves the dispute to d.State, but only while it is still in state from,
+// so that a dispute cannot be resolved twice. It reports domain.ErrConflict otherwise.
+func (c *Client) UpdateDisputeState(ctx context.Context, d *domain.Dispute, from domain.DisputeState) error {
+	updatedAt := time.Now().UTC()
+	updateQuery := "UPDATE dispute SET state = ?, resolved_by = ?, updated_at = ? WHERE id = ? AND state = ?"
+	res, err := c.db.ExecContext(ctx, updateQuery, d.State, d.ResolvedBy, updatedAt, d.ID, from)
+	if err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", updateQuery, d.ID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("res.RowsAffected on (%s) with id (%s): %w", updateQuery, d.ID, err)
+	} else if n == 0 {
+		return fmt.Errorf("dispute (%s) is no longer %s: %w", d.ID, from, domain.ErrConflict)
+	}
+	d.UpdatedAt = updatedAt
+	return nil
+}
+
+func scanDispute(row scanner) (*domain.Dispute, error) {
+	var d domain.Dispute
+	if err := row.Scan(&d.ID, &d.ItemID, &d.BuyerID, &d.SellerID, &d.Reason, &d.State, &d.ResolvedBy, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// CreateDisputeEntry appends a step to the dispute's audit trail
+func (c *Client) CreateDisputeEntry(ctx context.Context, e *domain.DisputeEntry) error {
+	if e == nil {
+		return fmt.Errorf("CreateDisputeEntry called with nil entry data")
+	}
+	if e.Evidence == nil {
+		e.Evidence = []string{}
+	}
+	evidence, err := json.Marshal(e.Evidence)
+	if err != nil {
+		return fmt.Errorf("json.Marshal evidence: %w", err)
+	}
+
+	e.ID = uuid.NewString()
+	e.CreatedAt = time.Now().UTC()
+	insertQuery := "INSERT INTO dispute_entry (id, dispute_id, actor_id, action, note, evidence, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, e.ID, e.DisputeID, e.ActorID, e.Action, e.Note, evidence, e.CreatedAt); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, e.ID, err)
+	}
+	return nil
+}
+
+func (c *Client) ListDisputeEntries(ctx context.Context, disputeID string) ([]*domain.DisputeEntry, error) {
+	query := "SELECT id, dispute_id, actor_id, action, note, evidence, created_at FROM dispute_entry WHERE dispute_id = ? ORDER BY created_at, id"
+	rows, err := c.db.QueryContext(ctx, query, disputeID)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s) with dispute id (%s): %w", query, disputeID, err)
+	}
+	defer rows.Close()
+
+	var entries []*domain.DisputeEntry
+	for rows.Next() {
+		var e domain.DisputeEntry
+		var evidence []byte
+		if err := rows.Scan(&e.ID, &e.DisputeID, &e.ActorID, &e.Action, &e.Note, &evidence, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		if err := json.Unmarshal(evidence, &e.Evidence); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal evidence of dispute entry (%s): %w", e.ID, err)
+		}
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,175 @@
+package mysql
+
+import (
+	"backend/internal/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreateImportJob(ctx context.Context, job *domain.ImportJob) error {
+	if job == nil {
+		return fmt.Errorf("CreateImportJob called with nil job data")
+	}
+	job.ID = uuid.NewString()
+	job.CreatedAt = time.Now().UTC()
+	job.UpdatedAt = job.CreatedAt
+	insertQuery := "INSERT INTO import_job (id, seller_id, format, state, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, job.ID, job.SellerID, job.Format, job.State, job.CreatedAt, job.UpdatedAt); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, job.ID, err)
+	}
+	return nil
+}
+
+// CreateImportRows stores the rows of a job in one statement, so that a job has either all of its rows or none
+func (c *Client) CreateImportRows(ctx context.Context, rows []*domain.ImportRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	insertQuery := "INSERT INTO import_row (job_id, line, state, item, error) VALUES (?, ?, ?, ?, ?)" + strings.Repeat(", (?, ?, ?, ?, ?)", len(rows)-1)
+	args := make([]any, 0, len(rows)*5)
+	for _, r := range rows {
+		item, err := json.Marshal(r.Item)
+		if err != nil {
+			return fmt.Errorf("json.Marshal item of line (%d): %w", r.Line, err)
+		}
+		args = append(args, r.JobID, r.Line, r.State, item, r.Error)
+	}
+	if _, err := c.db.ExecContext(ctx, insertQuery, args...); err != nil {
+		return fmt.Errorf("c.db.ExecContext on import rows of job (%s): %w", rows[0].JobID, err)
+	}
+	return nil
+}
+
+const selectImportJob = "SELECT j.id, j.seller_id, j.format, j.state, j.created_at, j.updated_at, COUNT(r.line), " +
+	"COALESCE(SUM(r.state = 'pending'), 0), COALESCE(SUM(r.state = 'listed'), 0), COALESCE(SUM(r.state = 'failed'), 0), COALESCE(SUM(r.state = 'invalid'), 0) " +
+	"FROM import_job j LEFT JOIN import_row r ON r.job_id = j.id"
+
+func (c *Client) GetImportJobByID(ctx context.Context, id string) (*domain.ImportJob, error) {
+	query := selectImportJob + " WHERE j.id = ? GROUP BY j.id"
+	job, err := scanImportJob(c.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("c.db.QueryRowContext on (%s) with id (%s): %w", query, id, err)
+	}
+	return job, nil
+}
+
+// ListImportJobsBySellerID returns the seller's jobs, newest first
+func (c *Client) ListImportJobsBySellerID(ctx context.Context, sellerID string) ([]*domain.ImportJob, error) {
+	return c.listImportJobs(ctx, selectImportJob+" WHERE j.seller_id = ? GROUP BY j.id ORDER BY j.created_at DESC", sellerID)
+}
+
+// ListImportJobsByState returns the jobs in the state, oldest first
+func (c *Client) ListImportJobsByState(ctx context.Context, state domain.ImportJobState) ([]*domain.ImportJob, error) {
+	return c.listImportJobs(ctx, selectImportJob+" WHERE j.state = ? GROUP BY j.id ORDER BY j.created_at", state)
+}
+
+func (c *Client) listImportJobs(ctx context.Context, query string, args ...any) ([]*domain.ImportJob, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s): %w", query, err)
+	}
+	defer rows.Close()
+
+	var jobs []*domain.ImportJob
+	for rows.Next() {
+		job, err := scanImportJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanImportJob: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err on (%s): %w", query, err)
+	}
+	return jobs, nil
+}
+
+func scanImportJob(row scanner) (*domain.ImportJob, error) {
+	var job domain.ImportJob
+	if err := row.Scan(&job.ID, &job.SellerID, &job.Format, &job.State, &job.CreatedAt, &job.UpdatedAt, &job.Total, &job.Pending, &job.Listed, &job.Failed, &job.Invalid); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (c *Client) UpdateImportJobState(ctx context.Context, id string, state domain.ImportJobState) error {
+	updateQuery := "UPDATE import_job SET state = ?, updated_at = ? WHERE id = ?"
+	if _, err := c.db.ExecContext(ctx, updateQuery, state, time.Now().UTC(), id); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", updateQuery, id, err)
+	}
+	return nil
+}
+
+const selectImportRow = "SELECT job_id, line, state, item, error FROM import_row"
+
+func (c *Client) ListImportRows(ctx context.Context, jobID string) ([]*domain.ImportRow, error) {
+	return c.listImportRows(ctx, selectImportRow+" WHERE job_id = ? ORDER BY line", jobID)
+}
+
+func (c *Client) ListImportRowsByState(ctx context.Context, jobID string, state domain.ImportRowState) ([]*domain.ImportRow, error) {
+	return c.listImportRows(ctx, selectImportRow+" WHERE job_id = ? AND state = ? ORDER BY line", jobID, state)
+}
+
+func (c *Client) listImportRows(ctx context.Context, query string, args ...any) ([]*domain.ImportRow, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s): %w", query, err)
+	}
+	defer rows.Close()
+
+	var importRows []*domain.ImportRow
+	for rows.Next() {
+		var r domain.ImportRow
+		var item []byte
+		if err := rows.Scan(&r.JobID, &r.Line, &r.State, &item, &r.Error); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		if err := json.Unmarshal(item, &r.Item); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal item of line (%d) of job (%s): %w", r.Line, r.JobID, err)
+		}
+		importRows = append(importRows, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err on (%s): %w", query, err)
+	}
+	return importRows, nil
+}
+
+func (c *Client) UpdateImportRow(ctx context.Context, r *domain.ImportRow) error {
+	item, err := json.Marshal(r.Item)
+	if err != nil {
+		return fmt.Errorf("json.Marshal item of line (%d): %w", r.Line, err)
+	}
+	updateQuery := "UPDATE import_row SET state = ?, item = ?, error = ? WHERE job_id = ? AND line = ?"
+	if _, err := c.db.ExecContext(ctx, updateQuery, r.State, item, r.Error, r.JobID, r.Line); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with job id (%s): %w", updateQuery, r.JobID, err)
+	}
+	return nil
+}
+
+// RetryImportJob puts the job's failed rows back in line and the job back to pending, returning how many rows it requeued
+func (c *Client) RetryImportJob(ctx context.Context, id string) (int64, error) {
+	updateQuery := "UPDATE import_row SET state = ?, error = '' WHERE job_id = ? AND state = ?"
+	res, err := c.db.ExecContext(ctx, updateQuery, domain.ImportRowStatePending, id, domain.ImportRowStateFailed)
+	if err != nil {
+		return 0, fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", updateQuery, id, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("res.RowsAffected on (%s) with id (%s): %w", updateQuery, id, err)
+	}
+	if err := c.UpdateImportJobState(ctx, id, domain.ImportJobStatePending); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
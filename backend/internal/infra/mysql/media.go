@@ -0,0 +1,46 @@
+package mysql
+
+import (
+	"backend/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CreateMedia records an upload, leaving an existing record for the same hash untouched
+func (c *Client) CreateMedia(ctx context.Context, m *domain.Media) error {
+	if m == nil {
+		return fmt.Errorf("CreateMedia called with nil media data")
+	}
+
+	m.CreatedAt = time.Now().UTC()
+	insertQuery := "INSERT IGNORE INTO media (hash, content_type, size, width, height, thumbnail_hash, uploader_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, m.Hash, m.ContentType, m.Size, m.Width, m.Height, m.ThumbnailHash, m.UploaderID, m.CreatedAt); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with hash (%s): %w", insertQuery, m.Hash, err)
+	}
+	return nil
+}
+
+const selectMedia = "SELECT hash, content_type, size, width, height, thumbnail_hash, uploader_id, created_at FROM media"
+
+func (c *Client) GetMediaByHash(ctx context.Context, hash string) (*domain.Media, error) {
+	return c.getMedia(ctx, selectMedia+" WHERE hash = ?", hash)
+}
+
+// GetMediaByThumbnailHash returns the media whose thumbnail is stored under hash
+func (c *Client) GetMediaByThumbnailHash(ctx context.Context, hash string) (*domain.Media, error) {
+	return c.getMedia(ctx, selectMedia+" WHERE thumbnail_hash = ? LIMIT 1", hash)
+}
+
+func (c *Client) getMedia(ctx context.Context, query, hash string) (*domain.Media, error) {
+	var m domain.Media
+	if err := c.db.QueryRowContext(ctx, query, hash).Scan(&m.Hash, &m.ContentType, &m.Size, &m.Width, &m.Height, &m.ThumbnailHash, &m.UploaderID, &m.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("c.db.QueryRowContext on (%s) with hash (%s): %w", query, hash, err)
+	}
+	return &m, nil
+}
@@ -0,0 +1,133 @@
+package mysql
+
+import (
+	"backend/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreateOffer(ctx context.Context, o *domain.Offer) error {
+	if o == nil {
+		return fmt.Errorf("CreateOffer called with nil offer data")
+	}
+
+	o.ID = uuid.NewString()
+	o.CreatedAt = time.Now().UTC()
+	o.UpdatedAt = o.CreatedAt
+	insertQuery := "INSERT INTO offer (id, item_id, buyer_id, seller_id, proposed_by, amount, currency, state, parent_id, expires_at, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, o.ID, o.ItemID, o.BuyerID, o.SellerID, o.ProposedBy, o.Amount.Amount().String(), o.Amount.Currency(), o.State, o.ParentID, o.ExpiresAt, o.CreatedAt, o.UpdatedAt); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, o.ID, err)
+	}
+	return nil
+}
+
+const selectOffer = "SELECT id, item_id, buyer_id, seller_id, proposed_by, amount, currency, state, parent_id, expires_at, created_at, updated_at FROM offer"
+
+func (c *Client) GetOfferByID(ctx context.Context, id string) (*domain.Offer, error) {
+	query := selectOffer + " WHERE id = ?"
+	o, err := scanOffer(c.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("c.db.QueryRowContext on (%s) with id (%s): %w", query, id, err)
+	}
+	return o, nil
+}
+
+func (c *Client) ListOffersByItemID(ctx context.Context, itemID string) ([]*domain.Offer, error) {
+	query := selectOffer + " WHERE item_id = ? ORDER BY created_at DESC"
+	offers, err := c.queryOffers(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("c.queryOffers on (%s) with item id (%s): %w", query, itemID, err)
+	}
+	return offers, nil
+}
+
+// ListOpenOffersByBuyerID returns the buyer's pending offers that have not expired at now
+func (c *Client) ListOpenOffersByBuyerID(ctx context.Context, buyerID string, now time.Time) ([]*domain.Offer, error) {
+	query := selectOffer + " WHERE buyer_id = ? AND state = ? AND expires_at > ?"
+	offers, err := c.queryOffers(ctx, query, buyerID, domain.OfferStatePending, now)
+	if err != nil {
+		return nil, fmt.Errorf("c.queryOffers on (%s) with buyer id (%s): %w", query, buyerID, err)
+	}
+	return offers, nil
+}
+
+// UpdateOfferState moves the offer to state, but only while it is still pending, so that two responses
+// cannot both succeed. It reports domain.ErrConflict when the offer was no longer pending.
+func (c *Client) UpdateOfferState(ctx context.Context, o *domain.Offer, state domain.OfferState) error {
+	updatedAt := time.Now().UTC()
+	updateQuery := "UPDATE offer SET state = ?, updated_at = ? WHERE id = ? AND state = ?"
+	res, err := c.db.ExecContext(ctx, updateQuery, state, updatedAt, o.ID, domain.OfferStatePending)
+	if err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", updateQuery, o.ID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("res.RowsAffected on (%s) with id (%s): %w", updateQuery, o.ID, err)
+	} else if n == 0 {
+		return fmt.Errorf("offer (%s) is no longer pending: %w", o.ID, domain.ErrConflict)
+	}
+	o.State = state
+	o.UpdatedAt = updatedAt
+	return nil
+}
+
+// ListPendingOffersByItemID returns the item's pending offers, whether or not they have expired
+func (c *Client) ListPendingOffersByItemID(ctx context.Context, itemID string) ([]*domain.Offer, error) {
+	query := selectOffer + " WHERE item_id = ? AND state = ?"
+	offers, err := c.queryOffers(ctx, query, itemID, domain.OfferStatePending)
+	if err != nil {
+		return nil, fmt.Errorf("c.queryOffers on (%s) with item id (%s): %w", query, itemID, err)
+	}
+	return offers, nil
+}
+
+// ListExpiredOffers returns the pending offers past their expiry at now, which are yet to be marked expired
+func (c *Client) ListExpiredOffers(ctx context.Context, now time.Time) ([]*domain.Offer, error) {
+	query := selectOffer + " WHERE state = ? AND expires_at <= ?"
+	offers, err := c.queryOffers(ctx, query, domain.OfferStatePending, now)
+	if err != nil {
+		return nil, fmt.Errorf("c.queryOffers on (%s): %w", query, err)
+	}
+	return offers, nil
+}
+
+func (c *Client) queryOffers(ctx context.Context, query string, args ...any) ([]*domain.Offer, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	var offers []*domain.Offer
+	for rows.Next() {
+		o, err := scanOffer(rows)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+	return offers, nil
+}
+
+func scanOffer(row scanner) (*domain.Offer, error) {
+	var o domain.Offer
+	var amount, currency string
+	if err := row.Scan(&o.ID, &o.ItemID, &o.BuyerID, &o.SellerID, &o.ProposedBy, &amount, &currency, &o.State, &o.ParentID, &o.ExpiresAt, &o.CreatedAt, &o.UpdatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if o.Amount, err = domain.ParseMoney(amount, currency); err != nil {
+		return nil, fmt.Errorf("amount of offer (%s): %w", o.ID, err)
+	}
+	return &o, nil
+}
@@ -0,0 +1,20 @@
+package mysql
+
+import (
+	"backend/internal/domain"
+	"context"
+	"fmt"
+)
+
+// UpsertTokenPool records the pool resolved for a name, replacing what an earlier boot resolved
+func (c *Client) UpsertTokenPool(ctx context.Context, p *domain.TokenPool) error {
+	if p == nil {
+		return fmt.Errorf("UpsertTokenPool called with nil pool data")
+	}
+	upsertQuery := "INSERT INTO token_pool (name, id, type, symbol, locator, address, connector, state) VALUES (?, ?, ?, ?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE id = VALUES(id), type = VALUES(type), symbol = VALUES(symbol), locator = VALUES(locator), address = VALUES(address), connector = VALUES(connector), state = VALUES(state)"
+	if _, err := c.db.ExecContext(ctx, upsertQuery, p.Name, p.ID, p.Type, p.Symbol, p.Locator, p.Address, p.Connector, p.State); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with name (%s): %w", upsertQuery, p.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package mysql
+
+import (
+	"backend/internal/domain"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreatePriceChange(ctx context.Context, pc *domain.PriceChange) error {
+	if pc == nil {
+		return fmt.Errorf("CreatePriceChange called with nil price change data")
+	}
+
+	pc.ID = uuid.NewString()
+	pc.CreatedAt = time.Now().UTC()
+	insertQuery := "INSERT INTO price_change (id, item_id, currency, old_price, new_price, changed_by, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, pc.ID, pc.ItemID, pc.NewPrice.Currency(), pc.OldPrice.Amount().String(), pc.NewPrice.Amount().String(), pc.ChangedBy, pc.CreatedAt); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, pc.ID, err)
+	}
+	return nil
+}
+
+// ListPriceChanges returns the price history of an item, newest first
+func (c *Client) ListPriceChanges(ctx context.Context, itemID string) ([]*domain.PriceChange, error) {
+	query := "SELECT id, item_id, currency, old_price, new_price, changed_by, created_at FROM price_change WHERE item_id = ? ORDER BY created_at DESC"
+	rows, err := c.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s) with item id (%s): %w", query, itemID, err)
+	}
+	defer rows.Close()
+
+	var changes []*domain.PriceChange
+	for rows.Next() {
+		var pc domain.PriceChange
+		var currency, oldPrice, newPrice string
+		if err := rows.Scan(&pc.ID, &pc.ItemID, &currency, &oldPrice, &newPrice, &pc.ChangedBy, &pc.CreatedAt); err != nil {
+			return nil, fmt.Errorf("rows.Scan on (%s): %w", query, err)
+		}
+		if pc.OldPrice, err = domain.ParseMoney(oldPrice, currency); err != nil {
+			return nil, fmt.Errorf("old price of price change (%s): %w", pc.ID, err)
+		}
+		if pc.NewPrice, err = domain.ParseMoney(newPrice, currency); err != nil {
+			return nil, fmt.Errorf("new price of price change (%s): %w", pc.ID, err)
+		}
+		changes = append(changes, &pc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err on (%s): %w", query, err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"backend/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// CreateReview stores a review, whose CreatedAt the caller has already set, as it is part of the anchored hash.
+// There can be one review per sale, a second one is a domain.ErrConflict.
+func (c *Client) CreateReview(ctx context.Context, r *domain.Review) error {
+	if r == nil {
+		return fmt.Errorf("CreateReview called with nil review data")
+	}
+
+	r.ID = uuid.NewString()
+	insertQuery := "INSERT IGNORE INTO review (id, sale_id, item_id, seller_id, buyer_id, rating, text, hash, anchor_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	res, err := c.db.ExecContext(ctx, insertQuery, r.ID, r.SaleID, r.ItemID, r.SellerID, r.BuyerID, r.Rating, r.Text, r.Hash, r.AnchorID, r.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, r.ID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("res.RowsAffected on (%s) with id (%s): %w", insertQuery, r.ID, err)
+	} else if n == 0 {
+		return fmt.Errorf("sale (%s) is already reviewed: %w", r.SaleID, domain.ErrConflict)
+	}
+	return nil
+}
+
+const selectReview = "SELECT id, sale_id, item_id, seller_id, buyer_id, rating, text, hash, anchor_id, created_at FROM review"
+
+func (c *Client) GetReviewByID(ctx context.Context, id string) (*domain.Review, error) {
+	query := selectReview + " WHERE id = ?"
+	r, err := scanReview(c.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("c.db.QueryRowContext on (%s) with id (%s): %w", query, id, err)
+	}
+	return r, nil
+}
+
+func (c *Client) GetReviewBySaleID(ctx context.Context, saleID string) (*domain.Review, error) {
+	query := selectReview + " WHERE sale_id = ?"
+	r, err := scanReview(c.db.QueryRowContext(ctx, query, saleID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("c.db.QueryRowContext on (%s) with sale id (%s): %w", query, saleID, err)
+	}
+	return r, nil
+}
+
+// ListReviewsBySellerID returns every review of the seller, newest first
+func (c *Client) ListReviewsBySellerID(ctx context.Context, sellerID string) ([]*domain.Review, error) {
+	query := selectReview + " WHERE seller_id = ? ORDER BY created_at DESC"
+	rows, err := c.db.QueryContext(ctx, query, sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s) with seller id (%s): %w", query, sellerID, err)
+	}
+	defer rows.Close()
+
+	var reviews []*domain.Review
+	for rows.Next() {
+		r, err := scanReview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanReview: %w", err)
+		}
+		reviews = append(reviews, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+	return reviews, nil
+}
+
+func scanReview(row scanner) (*domain.Review, error) {
+	var r domain.Review
+	if err := row.Scan(&r.ID, &r.SaleID, &r.ItemID, &r.SellerID, &r.BuyerID, &r.Rating, &r.Text, &r.Hash, &r.AnchorID, &r.CreatedAt); err != nil {
+		return nil, err
+	}
+	r.CreatedAt = r.CreatedAt.UTC()
+	return &r, nil
+}
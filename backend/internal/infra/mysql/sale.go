@@ -0,0 +1,157 @@
+package mysql
+
+import (
+	"backend/internal/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreateSale(ctx context.Context, sale *domain.Sale) error {
+	if sale == nil {
+		return fmt.Errorf("CreateSale called with nil sale data")
+	}
+
+	sale.ID = uuid.NewString()
+	sale.CreatedAt = time.Now().UTC()
+	// Every part of the split is in the currency of the price
+	insertQuery := "INSERT INTO sale (id, item_id, seller_id, buyer_id, creator_id, currency, price, platform_fee, royalty, seller_proceeds, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, sale.ID, sale.ItemID, sale.SellerID, sale.BuyerID, sale.CreatorID, sale.Price.Currency(),
+		sale.Price.Amount().String(), sale.PlatformFee.Amount().String(), sale.Royalty.Amount().String(), sale.SellerProceeds.Amount().String(), sale.CreatedAt); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, sale.ID, err)
+	}
+	return nil
+}
+
+// CompleteSale marks the buyer's latest open purchase of the item as completed
+func (c *Client) CompleteSale(ctx context.Context, itemID, buyerID string) error {
+	completedAt := time.Now().UTC()
+	updateQuery := "UPDATE sale SET completed_at = ? WHERE item_id = ? AND buyer_id = ? AND completed_at IS NULL ORDER BY created_at DESC LIMIT 1"
+	if _, err := c.db.ExecContext(ctx, updateQuery, completedAt, itemID, buyerID); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with item id (%s): %w", updateQuery, itemID, err)
+	}
+	return nil
+}
+
+// RefundSale marks the buyer's latest purchase of the item as refunded
+func (c *Client) RefundSale(ctx context.Context, itemID, buyerID string) error {
+	updateQuery := "UPDATE sale SET refunded_at = ? WHERE item_id = ? AND buyer_id = ? AND refunded_at IS NULL ORDER BY created_at DESC LIMIT 1"
+	if _, err := c.db.ExecContext(ctx, updateQuery, time.Now().UTC(), itemID, buyerID); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with item id (%s): %w", updateQuery, itemID, err)
+	}
+	return nil
+}
+
+// ReleaseSale marks the buyer's latest purchase of the item as paid out to the seller
+func (c *Client) ReleaseSale(ctx context.Context, itemID, buyerID string) error {
+	updateQuery := "UPDATE sale SET released_at = ? WHERE item_id = ? AND buyer_id = ? AND released_at IS NULL ORDER BY created_at DESC LIMIT 1"
+	if _, err := c.db.ExecContext(ctx, updateQuery, time.Now().UTC(), itemID, buyerID); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with item id (%s): %w", updateQuery, itemID, err)
+	}
+	return nil
+}
+
+const saleColumns = "s.id, s.item_id, s.seller_id, s.buyer_id, s.creator_id, s.currency, s.price, s.platform_fee, s.royalty, s.seller_proceeds, s.created_at, s.completed_at, s.refunded_at, s.released_at"
+
+const selectSale = "SELECT " + saleColumns + " FROM sale s"
+
+// ListSalesByItemID returns every sale of the item, oldest first
+func (c *Client) ListSalesByItemID(ctx context.Context, itemID string) ([]*domain.Sale, error) {
+	return c.listSales(ctx, selectSale+" WHERE s.item_id = ? ORDER BY s.created_at", itemID)
+}
+
+// ListSalesToRelease returns the completed sales that were neither refunded nor paid out, and were completed
+// before a time, oldest first
+func (c *Client) ListSalesToRelease(ctx context.Context, completedBefore time.Time) ([]*domain.Sale, error) {
+	return c.listSales(ctx, selectSale+" WHERE s.completed_at <= ? AND s.refunded_at IS NULL AND s.released_at IS NULL ORDER BY s.completed_at", completedBefore)
+}
+
+// ListSettledSalesByCategory returns the settled sales since a time of items in the category, oldest first,
+// with the condition and attributes of the items they sold
+func (c *Client) ListSettledSalesByCategory(ctx context.Context, category string, since time.Time) ([]*domain.ComparableSale, error) {
+	query := "SELECT " + saleColumns + ", l.item_condition, l.attributes FROM sale s JOIN listing l ON l.id = s.item_id " +
+		"WHERE l.category = ? AND s.created_at >= ? AND s.completed_at IS NOT NULL AND s.refunded_at IS NULL ORDER BY s.created_at"
+	rows, err := c.db.QueryContext(ctx, query, category, since)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s): %w", query, err)
+	}
+	defer rows.Close()
+
+	var sales []*domain.ComparableSale
+	for rows.Next() {
+		var cs domain.ComparableSale
+		var attributes []byte
+		if cs.Sale, err = scanSale(rows, &cs.Condition, &attributes); err != nil {
+			return nil, fmt.Errorf("scanSale: %w", err)
+		}
+		if len(attributes) > 0 {
+			if err := json.Unmarshal(attributes, &cs.Attributes); err != nil {
+				return nil, fmt.Errorf("json.Unmarshal attributes of item (%s): %w", cs.Sale.ItemID, err)
+			}
+		}
+		sales = append(sales, &cs)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err on (%s): %w", query, err)
+	}
+	return sales, nil
+}
+
+func (c *Client) listSales(ctx context.Context, query string, args ...any) ([]*domain.Sale, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s): %w", query, err)
+	}
+	defer rows.Close()
+
+	var sales []*domain.Sale
+	for rows.Next() {
+		sale, err := scanSale(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanSale: %w", err)
+		}
+		sales = append(sales, sale)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err on (%s): %w", query, err)
+	}
+	return sales, nil
+}
+
+// scanSale scans the sale columns, followed by any extra columns into extra
+func scanSale(row scanner, extra ...any) (*domain.Sale, error) {
+	var sale domain.Sale
+	var currency, price, platformFee, royalty, sellerProceeds string
+	var completedAt, refundedAt, releasedAt sql.NullTime
+	dest := []any{&sale.ID, &sale.ItemID, &sale.SellerID, &sale.BuyerID, &sale.CreatorID, &currency, &price, &platformFee, &royalty, &sellerProceeds, &sale.CreatedAt, &completedAt, &refundedAt, &releasedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if completedAt.Valid {
+		sale.CompletedAt = &completedAt.Time
+	}
+	if refundedAt.Valid {
+		sale.RefundedAt = &refundedAt.Time
+	}
+	if releasedAt.Valid {
+		sale.ReleasedAt = &releasedAt.Time
+	}
+	var err error
+	if sale.Price, err = domain.ParseMoney(price, currency); err != nil {
+		return nil, fmt.Errorf("price of sale (%s): %w", sale.ID, err)
+	}
+	if sale.PlatformFee, err = domain.ParseMoney(platformFee, currency); err != nil {
+		return nil, fmt.Errorf("platform fee of sale (%s): %w", sale.ID, err)
+	}
+	if sale.Royalty, err = domain.ParseMoney(royalty, currency); err != nil {
+		return nil, fmt.Errorf("royalty of sale (%s): %w", sale.ID, err)
+	}
+	if sale.SellerProceeds, err = domain.ParseMoney(sellerProceeds, currency); err != nil {
+		return nil, fmt.Errorf("seller proceeds of sale (%s): %w", sale.ID, err)
+	}
+	return &sale, nil
+}
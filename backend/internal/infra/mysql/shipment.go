@@ -0,0 +1,128 @@
+package mysql
+
+import (
+	"backend/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreateShipment(ctx context.Context, sh *domain.Shipment) error {
+	if sh == nil {
+		return fmt.Errorf("CreateShipment called with nil shipment data")
+	}
+
+	sh.ID = uuid.NewString()
+	sh.CreatedAt = time.Now().UTC()
+	sh.UpdatedAt = sh.CreatedAt
+	insertQuery := "INSERT INTO shipment (id, item_id, seller_id, buyer_id, carrier, tracking_number, status, delivered_at, confirmed_at, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, sh.ID, sh.ItemID, sh.SellerID, sh.BuyerID, sh.Carrier, sh.TrackingNumber, sh.Status, sh.DeliveredAt, sh.ConfirmedAt, sh.CreatedAt, sh.UpdatedAt); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, sh.ID, err)
+	}
+	return nil
+}
+
+const selectShipment = "SELECT id, item_id, seller_id, buyer_id, carrier, tracking_number, status, delivered_at, confirmed_at, created_at, updated_at FROM shipment"
+
+// GetLatestShipmentByItemID returns the item's most recent shipment, as a resold item is shipped again
+func (c *Client) GetLatestShipmentByItemID(ctx context.Context, itemID string) (*domain.Shipment, error) {
+	query := selectShipment + " WHERE item_id = ? ORDER BY created_at DESC LIMIT 1"
+	sh, err := scanShipment(c.db.QueryRowContext(ctx, query, itemID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("c.db.QueryRowContext on (%s) with item id (%s): %w", query, itemID, err)
+	}
+	return sh, nil
+}
+
+// ListShipmentsByStatus returns the shipments in any of the given statuses, oldest first
+func (c *Client) ListShipmentsByStatus(ctx context.Context, statuses ...domain.ShipmentStatus) ([]*domain.Shipment, error) {
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+	query := selectShipment + " WHERE status IN (?" + strings.Repeat(", ?", len(statuses)-1) + ") ORDER BY created_at"
+	args := make([]any, len(statuses))
+	for i, st := range statuses {
+		args[i] = st
+	}
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext on (%s): %w", query, err)
+	}
+	defer rows.Close()
+
+	var shipments []*domain.Shipment
+	for rows.Next() {
+		sh, err := scanShipment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanShipment: %w", err)
+		}
+		shipments = append(shipments, sh)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+	return shipments, nil
+}
+
+// UpdateShipment saves the shipment's status and timestamps, but only while it is still in status from,
+// so that a carrier update cannot overwrite the buyer's confirmation. It reports domain.ErrConflict otherwise.
+func (c *Client) UpdateShipment(ctx context.Context, sh *domain.Shipment, from domain.ShipmentStatus) error {
+	updatedAt := time.Now().UTC()
+	updateQuery := "UPDATE shipment SET status = ?, delivered_at = ?, confirmed_at = ?, updated_at = ? WHERE id = ? AND status = ?"
+	res, err := c.db.ExecContext(ctx, updateQuery, sh.Status, sh.DeliveredAt, sh.ConfirmedAt, updatedAt, sh.ID, from)
+	if err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", updateQuery, sh.ID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("res.RowsAffected on (%s) with id (%s): %w", updateQuery, sh.ID, err)
+	} else if n == 0 {
+		return fmt.Errorf("shipment (%s) is no longer %s: %w", sh.ID, from, domain.ErrConflict)
+	}
+	sh.UpdatedAt = updatedAt
+	return nil
+}
+
+func scanShipment(row scanner) (*domain.Shipment, error) {
+	var sh domain.Shipment
+	var deliveredAt, confirmedAt sql.NullTime
+	if err := row.Scan(&sh.ID, &sh.ItemID, &sh.SellerID, &sh.BuyerID, &sh.Carrier, &sh.TrackingNumber, &sh.Status, &deliveredAt, &confirmedAt, &sh.CreatedAt, &sh.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if deliveredAt.Valid {
+		sh.DeliveredAt = &deliveredAt.Time
+	}
+	if confirmedAt.Valid {
+		sh.ConfirmedAt = &confirmedAt.Time
+	}
+	return &sh, nil
+}
+
+// UpsertShippingAddress stores the buyer's encrypted address for an item, replacing an earlier one
+func (c *Client) UpsertShippingAddress(ctx context.Context, itemID, buyerID string, sealed []byte) error {
+	upsertQuery := "INSERT INTO shipping_address (item_id, buyer_id, ciphertext) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE ciphertext = VALUES(ciphertext)"
+	if _, err := c.db.ExecContext(ctx, upsertQuery, itemID, buyerID, sealed); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with item id (%s): %w", upsertQuery, itemID, err)
+	}
+	return nil
+}
+
+// GetShippingAddress returns the encrypted address the buyer gave for an item
+func (c *Client) GetShippingAddress(ctx context.Context, itemID, buyerID string) ([]byte, error) {
+	var sealed []byte
+	query := "SELECT ciphertext FROM shipping_address WHERE item_id = ? AND buyer_id = ?"
+	if err := c.db.QueryRowContext(ctx, query, itemID, buyerID).Scan(&sealed); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("c.db.QueryRowContext on (%s) with item id (%s): %w", query, itemID, err)
+	}
+	return sealed, nil
+}
@@ -0,0 +1,192 @@
+package mysql
+
+import (
+	"backend/internal/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreateWebhookSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	if sub == nil {
+		return fmt.Errorf("CreateWebhookSubscription called with nil subscription data")
+	}
+	eventTypes, err := json.Marshal(sub.EventTypes)
+	if err != nil {
+		return fmt.Errorf("json.Marshal event types: %w", err)
+	}
+
+	sub.ID = uuid.NewString()
+	insertQuery := "INSERT INTO webhook_subscription (id, user_id, url, secret, event_types) VALUES (?, ?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, sub.ID, sub.UserID, sub.URL, sub.Secret, eventTypes); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, sub.ID, err)
+	}
+	return nil
+}
+
+const selectWebhookSubscription = "SELECT id, user_id, url, secret, event_types, created_at FROM webhook_subscription"
+
+func (c *Client) GetWebhookSubscriptionByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	query := selectWebhookSubscription + " WHERE id = ?"
+	sub, err := scanWebhookSubscription(c.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("c.db.QueryRowContext on (%s) with id (%s): %w", query, id, err)
+	}
+	return sub, nil
+}
+
+func (c *Client) ListWebhookSubscriptionsByUserID(ctx context.Context, userID string) ([]*domain.WebhookSubscription, error) {
+	query := selectWebhookSubscription + " WHERE user_id = ? ORDER BY created_at"
+	subs, err := c.queryWebhookSubscriptions(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("c.queryWebhookSubscriptions on (%s) with user id (%s): %w", query, userID, err)
+	}
+	return subs, nil
+}
+
+func (c *Client) ListWebhookSubscriptionsByEventType(ctx context.Context, eventType domain.EventType) ([]*domain.WebhookSubscription, error) {
+	query := selectWebhookSubscription + " WHERE JSON_CONTAINS(event_types, JSON_QUOTE(?))"
+	subs, err := c.queryWebhookSubscriptions(ctx, query, string(eventType))
+	if err != nil {
+		return nil, fmt.Errorf("c.queryWebhookSubscriptions on (%s) with event type (%s): %w", query, eventType, err)
+	}
+	return subs, nil
+}
+
+func (c *Client) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	deleteQuery := "DELETE FROM webhook_subscription WHERE id = ?"
+	if _, err := c.db.ExecContext(ctx, deleteQuery, id); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", deleteQuery, id, err)
+	}
+	return nil
+}
+
+func (c *Client) queryWebhookSubscriptions(ctx context.Context, query string, args ...any) ([]*domain.WebhookSubscription, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []*domain.WebhookSubscription
+	for rows.Next() {
+		sub, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+	return subs, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhookSubscription(row scanner) (*domain.WebhookSubscription, error) {
+	var sub domain.WebhookSubscription
+	var eventTypes []byte
+	if err := row.Scan(&sub.ID, &sub.UserID, &sub.URL, &sub.Secret, &eventTypes, &sub.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(eventTypes, &sub.EventTypes); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal event types of subscription (%s): %w", sub.ID, err)
+	}
+	return &sub, nil
+}
+
+func (c *Client) CreateWebhookDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	if d == nil {
+		return fmt.Errorf("CreateWebhookDelivery called with nil delivery data")
+	}
+
+	d.ID = uuid.NewString()
+	insertQuery := "INSERT INTO webhook_delivery (id, subscription_id, event_type, payload, state, attempts, next_attempt_at, last_error) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	if _, err := c.db.ExecContext(ctx, insertQuery, d.ID, d.SubscriptionID, d.EventType, d.Payload, d.State, d.Attempts, d.NextAttemptAt, d.LastError); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", insertQuery, d.ID, err)
+	}
+	return nil
+}
+
+const selectWebhookDelivery = "SELECT id, subscription_id, event_type, payload, state, attempts, next_attempt_at, last_error, created_at FROM webhook_delivery"
+
+func (c *Client) GetWebhookDeliveryByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	query := selectWebhookDelivery + " WHERE id = ?"
+	d, err := scanWebhookDelivery(c.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("c.db.QueryRowContext on (%s) with id (%s): %w", query, id, err)
+	}
+	return d, nil
+}
+
+func (c *Client) ListWebhookDeliveriesBySubscriptionID(ctx context.Context, subscriptionID string) ([]*domain.WebhookDelivery, error) {
+	query := selectWebhookDelivery + " WHERE subscription_id = ? ORDER BY created_at DESC"
+	deliveries, err := c.queryWebhookDeliveries(ctx, query, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("c.queryWebhookDeliveries on (%s) with subscription id (%s): %w", query, subscriptionID, err)
+	}
+	return deliveries, nil
+}
+
+func (c *Client) ListDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	query := selectWebhookDelivery + " WHERE state = ? AND next_attempt_at <= ? ORDER BY next_attempt_at LIMIT ?"
+	deliveries, err := c.queryWebhookDeliveries(ctx, query, domain.WebhookDeliveryStatePending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("c.queryWebhookDeliveries on (%s): %w", query, err)
+	}
+	return deliveries, nil
+}
+
+func (c *Client) UpdateWebhookDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	if d == nil {
+		return fmt.Errorf("UpdateWebhookDelivery called with nil delivery data")
+	}
+	updateQuery := "UPDATE webhook_delivery SET state = ?, attempts = ?, next_attempt_at = ?, last_error = ? WHERE id = ?"
+	if _, err := c.db.ExecContext(ctx, updateQuery, d.State, d.Attempts, d.NextAttemptAt, d.LastError, d.ID); err != nil {
+		return fmt.Errorf("c.db.ExecContext on (%s) with id (%s): %w", updateQuery, d.ID, err)
+	}
+	return nil
+}
+
+func (c *Client) queryWebhookDeliveries(ctx context.Context, query string, args ...any) ([]*domain.WebhookDelivery, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("c.db.QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+	return deliveries, nil
+}
+
+func scanWebhookDelivery(row scanner) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	if err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventType, &d.Payload, &d.State, &d.Attempts, &d.NextAttemptAt, &d.LastError, &d.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
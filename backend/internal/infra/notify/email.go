@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"backend/internal/domain"
+	"context"
+	"fmt"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// EmailName is the channel name the email notifier is registered under
+const EmailName = "email"
+
+// Email sends every digest as a plain text mail through an SMTP relay
+type Email struct {
+	// addr is the host:port of the SMTP relay
+	addr string
+	from string
+}
+
+func NewEmail(addr, from string) *Email {
+	return &Email{addr: addr, from: from}
+}
+
+func (e *Email) ValidateAddress(address string) error {
+	a, err := mail.ParseAddress(address)
+	if err != nil || a.Name != "" {
+		return fmt.Errorf("address (%s) must be a plain email address: %w", address, domain.ErrInvalidArgument)
+	}
+	return nil
+}
+
+func (e *Email) Notify(ctx context.Context, address string, d *domain.Digest) error {
+	if err := smtp.SendMail(e.addr, nil, e.from, []string{address}, e.message(address, d)); err != nil {
+		return fmt.Errorf("smtp.SendMail to (%s): %w", address, err)
+	}
+	return nil
+}
+
+func (e *Email) message(to string, d *domain.Digest) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %d new marketplace notification(s)\r\n", len(d.Notifications))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	for _, n := range d.Notifications {
+		switch n.Kind {
+		case domain.NotificationKindPriceDrop:
+			fmt.Fprintf(&b, "Price drop: %s is now %s, down from %s (item %s)\r\n", n.ItemName, n.Price, n.PreviousPrice, n.ItemID)
+		default:
+			fmt.Fprintf(&b, "New match: %s listed for %s (item %s)\r\n", n.ItemName, n.Price, n.ItemID)
+		}
+	}
+	return []byte(b.String())
+}
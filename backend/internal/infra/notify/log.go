@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"backend/internal/domain"
+	"context"
+	"log"
+)
+
+// LogName is the channel name the log notifier is registered under
+const LogName = "log"
+
+// Log writes digests to the server log, for development and for users who have not set up a channel
+type Log struct{}
+
+func NewLog() *Log {
+	return &Log{}
+}
+
+// ValidateAddress accepts any address, as the log has nowhere to deliver to
+func (l *Log) ValidateAddress(address string) error {
+	return nil
+}
+
+func (l *Log) Notify(ctx context.Context, address string, d *domain.Digest) error {
+	for _, n := range d.Notifications {
+		switch n.Kind {
+		case domain.NotificationKindPriceDrop:
+			log.Printf("Notification for user (%s): price of %s (%s) dropped from %s to %s", d.UserID, n.ItemName, n.ItemID, n.PreviousPrice, n.Price)
+		default:
+			log.Printf("Notification for user (%s): %s (%s) listed for %s matches search (%s)", d.UserID, n.ItemName, n.ItemID, n.Price, n.SearchID)
+		}
+	}
+	return nil
+}
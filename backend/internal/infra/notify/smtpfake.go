@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/textproto"
+	"strings"
+)
+
+// SMTPFake is a local SMTP server for development that logs every mail it receives instead of
+// delivering it. It speaks just enough SMTP for net/smtp: HELO/EHLO, MAIL, RCPT, DATA, RSET, NOOP and QUIT.
+type SMTPFake struct {
+	listener net.Listener
+}
+
+// NewSMTPFake starts listening on addr. Mail is only accepted once Run is called.
+func NewSMTPFake(addr string) (*SMTPFake, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("net.Listen on (%s): %w", addr, err)
+	}
+	return &SMTPFake{listener: l}, nil
+}
+
+// Addr is the address the fake listens on
+func (f *SMTPFake) Addr() string {
+	return f.listener.Addr().String()
+}
+
+// Run accepts connections until ctx is cancelled
+func (f *SMTPFake) Run(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		f.listener.Close()
+	}()
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Printf("SMTP fake failed to accept a connection: %s", err.Error())
+			}
+			return
+		}
+		go f.serve(conn)
+	}
+}
+
+func (f *SMTPFake) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	reply := func(code int, msg string) bool {
+		return tp.PrintfLine("%d %s", code, msg) == nil
+	}
+
+	var from string
+	var to []string
+	if !reply(220, "localhost fake SMTP ready") {
+		return
+	}
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "HELO", "EHLO":
+			reply(250, "localhost")
+		case "MAIL":
+			from, to = strings.TrimPrefix(arg, "FROM:"), nil
+			reply(250, "OK")
+		case "RCPT":
+			to = append(to, strings.TrimPrefix(arg, "TO:"))
+			reply(250, "OK")
+		case "DATA":
+			if len(to) == 0 {
+				reply(503, "need RCPT first")
+				continue
+			}
+			reply(354, "end data with <CR><LF>.<CR><LF>")
+			body, err := readData(tp.R)
+			if err != nil {
+				return
+			}
+			log.Printf("SMTP fake received mail from %s to %s:\n%s", from, strings.Join(to, ", "), body)
+			from, to = "", nil
+			reply(250, "OK")
+		case "RSET":
+			from, to = "", nil
+			reply(250, "OK")
+		case "NOOP":
+			reply(250, "OK")
+		case "QUIT":
+			reply(221, "bye")
+			return
+		default:
+			reply(502, "command not implemented")
+		}
+	}
+}
+
+func readData(r *bufio.Reader) (string, error) {
+	b, err := textproto.NewReader(r).ReadDotBytes()
+	if err != nil {
+		return "", fmt.Errorf("ReadDotBytes: %w", err)
+	}
+	return string(b), nil
+}
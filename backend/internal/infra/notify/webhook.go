@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"backend/internal/domain"
+	"backend/internal/infra/publichttp"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookName is the channel name the webhook notifier is registered under
+const WebhookName = "webhook"
+
+// Webhook posts every digest as JSON to the URL the user gave as their address.
+// It only connects to public addresses, so that users cannot have the server call into its own network.
+type Webhook struct {
+	httpClient *publichttp.Client
+}
+
+// NewWebhook returns a webhook notifier whose requests, including connecting, give up after timeout
+func NewWebhook(timeout time.Duration) *Webhook {
+	return &Webhook{
+		httpClient: publichttp.New(timeout),
+	}
+}
+
+// ValidateAddress rejects URLs whose host is or resolves to a non-public address
+func (w *Webhook) ValidateAddress(address string) error {
+	return w.httpClient.ValidateURL(address)
+}
+
+func (w *Webhook) Notify(ctx context.Context, address string, d *domain.Digest) error {
+	payload, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("json.Marshal type domain.Digest: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext to (%s): %w", address, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("w.httpClient.Do to (%s): %w", address, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code (%d) from (%s)", resp.StatusCode, address)
+	}
+	return nil
+}
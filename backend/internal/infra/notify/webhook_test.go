@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"backend/internal/domain"
+	"backend/internal/infra/publichttp"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookRefusesToDialLoopback(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }))
+	defer srv.Close()
+
+	w := NewWebhook(time.Second)
+	if err := w.ValidateAddress(srv.URL); !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Errorf("ValidateAddress(%q) = %v, want %v", srv.URL, err, domain.ErrInvalidArgument)
+	}
+	if err := w.Notify(context.Background(), srv.URL, &domain.Digest{}); !errors.Is(err, publichttp.ErrNotPublic) {
+		t.Errorf("Notify to (%s) = %v, want %v", srv.URL, err, publichttp.ErrNotPublic)
+	}
+	if called {
+		t.Error("Notify reached the loopback server")
+	}
+}
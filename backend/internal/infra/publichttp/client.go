@@ -0,0 +1,113 @@
+package publichttp
+
+import (
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrNotPublic is returned for addresses the server must not be made to call, such as its own or its network's
+var ErrNotPublic = errors.New("not a public address")
+
+// nonPublicPrefixes are the ranges outside the ones netip classifies that are not reachable on the internet
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	// Carrier-grade NAT
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	// Benchmarking
+	netip.MustParsePrefix("198.18.0.0/15"),
+}
+
+// Client sends requests to URLs users give, such as webhooks. It only connects to public addresses,
+// so that users cannot have the server call into its own network.
+type Client struct {
+	httpClient *http.Client
+	lookup     func(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// New returns a client whose requests, including connecting, give up after timeout
+func New(timeout time.Duration) *Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: publicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialled instead of the URL's host, which would escape the check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &Client{
+		httpClient: &http.Client{Timeout: timeout, Transport: transport},
+		lookup:     net.DefaultResolver.LookupNetIP,
+	}
+}
+
+// ValidateURL rejects URLs whose host is or resolves to a non-public address. The address is checked again
+// on every connection, as DNS can change after validation.
+func (c *Client) ValidateURL(address string) error {
+	u, err := url.Parse(address)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url (%s) must be an absolute http(s) url: %w", address, domain.ErrInvalidArgument)
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("url (%s) must not point at a local host: %w", address, domain.ErrInvalidArgument)
+	}
+	addrs := []netip.Addr{}
+	if ip, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, ip)
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if addrs, err = c.lookup(ctx, "ip", host); err != nil {
+			return fmt.Errorf("host of url (%s) does not resolve: %w", address, domain.ErrInvalidArgument)
+		}
+	}
+	for _, ip := range addrs {
+		if !isPublic(ip) {
+			return fmt.Errorf("url (%s) must not point at %s, %s: %w", address, ip, ErrNotPublic, domain.ErrInvalidArgument)
+		}
+	}
+	return nil
+}
+
+// Do sends the request, failing with ErrNotPublic when its host, or that of a redirect, is not public
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	return c.httpClient.Do(req)
+}
+
+// publicOnly is a net.Dialer Control that refuses to connect to non-public addresses. It runs once the
+// host has been resolved, for every connection including those of redirects.
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("net.SplitHostPort (%s): %w", address, err)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("netip.ParseAddr (%s): %w", host, err)
+	}
+	if !isPublic(ip) {
+		return fmt.Errorf("dial %s %s: %w", network, address, ErrNotPublic)
+	}
+	return nil
+}
+
+func isPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, p := range nonPublicPrefixes {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
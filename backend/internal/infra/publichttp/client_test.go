@@ -0,0 +1,80 @@
+package publichttp
+
+import (
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestValidateURL(t *testing.T) {
+	c := New(time.Second)
+	hosts := map[string][]netip.Addr{
+		"hooks.example.com":    {netip.MustParseAddr("93.184.216.34")},
+		"internal.example.com": {netip.MustParseAddr("93.184.216.34"), netip.MustParseAddr("10.1.2.3")},
+	}
+	c.lookup = func(_ context.Context, _, host string) ([]netip.Addr, error) {
+		if addrs, ok := hosts[host]; ok {
+			return addrs, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	tests := []struct {
+		address string
+		wantErr bool
+	}{
+		{address: "https://hooks.example.com/digest"},
+		{address: "http://93.184.216.34:8080/digest"},
+		{address: "https://[2606:2800:220:1:248:1893:25c8:1946]/digest"},
+		{address: "ftp://hooks.example.com/digest", wantErr: true},
+		{address: "/digest", wantErr: true},
+		{address: "http://localhost:8080/", wantErr: true},
+		{address: "http://api.LOCALHOST./", wantErr: true},
+		{address: "http://127.0.0.1/", wantErr: true},
+		{address: "http://[::1]/", wantErr: true},
+		{address: "http://[::ffff:127.0.0.1]/", wantErr: true},
+		{address: "http://0.0.0.0/", wantErr: true},
+		{address: "http://10.0.0.8/", wantErr: true},
+		{address: "http://172.16.5.4/", wantErr: true},
+		{address: "http://192.168.1.1/", wantErr: true},
+		{address: "http://100.64.0.1/", wantErr: true},
+		{address: "http://169.254.169.254/latest/meta-data/", wantErr: true},
+		{address: "http://[fe80::1]/", wantErr: true},
+		{address: "http://[fd00::1]/", wantErr: true},
+		{address: "https://internal.example.com/", wantErr: true},
+		{address: "https://unknown.example.com/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := c.ValidateURL(tt.address)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateURL(%q) = %v, wantErr %v", tt.address, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, domain.ErrInvalidArgument) {
+				t.Errorf("ValidateURL(%q) = %v, want %v", tt.address, err, domain.ErrInvalidArgument)
+			}
+		})
+	}
+}
+
+func TestRefusesToDialLoopback(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }))
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequestWithContext: %v", err)
+	}
+	if _, err := New(time.Second).Do(req); !errors.Is(err, ErrNotPublic) {
+		t.Errorf("Do to (%s) = %v, want %v", srv.URL, err, ErrNotPublic)
+	}
+	if called {
+		t.Error("Do reached the loopback server")
+	}
+}
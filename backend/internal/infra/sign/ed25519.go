@@ -0,0 +1,32 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"fmt"
+)
+
+// Ed25519 signs documents exported to users, such as provenance timelines, so that they can be verified offline
+type Ed25519 struct {
+	key ed25519.PrivateKey
+}
+
+// New takes the 32 byte seed of the private key
+func New(seed []byte) (*Ed25519, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("seed must be %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	return &Ed25519{key: ed25519.NewKeyFromSeed(seed)}, nil
+}
+
+// Algorithm names the signature scheme in signed exports
+func (s *Ed25519) Algorithm() string {
+	return "Ed25519"
+}
+
+func (s *Ed25519) Sign(payload []byte) []byte {
+	return ed25519.Sign(s.key, payload)
+}
+
+func (s *Ed25519) PublicKey() []byte {
+	return s.key.Public().(ed25519.PublicKey)
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"backend/internal/utils"
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// userIDMetadataKey is the gRPC counterpart of the UserID HTTP header.
+// Metadata keys are always lower-cased on the wire.
+const userIDMetadataKey = "userid"
+
+func UnaryUserID(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := userIDFromMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func StreamUserID(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := userIDFromMetadata(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &userIDServerStream{ServerStream: ss, ctx: ctx})
+}
+
+func userIDFromMetadata(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	uids := md.Get(userIDMetadataKey)
+	if len(uids) == 0 || len(uids[0]) == 0 {
+		// For now, when no user ID metadata is set, treat as unauthorized request
+		return nil, status.Error(codes.Unauthenticated, "missing userid metadata")
+	}
+	return utils.NewContext(ctx, uids[0]), nil
+}
+
+type userIDServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *userIDServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit lets every client address make at most limit requests per window, answering 429 beyond that.
+// It is meant for endpoints that are open to anyone, where there is no user to limit.
+func RateLimit(limit int, window time.Duration) func(http.Handler) http.Handler {
+	l := &fixedWindow{
+		limit:  limit,
+		window: window,
+		counts: make(map[string]int),
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if wait, ok := l.allow(clientAddr(r), time.Now()); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// fixedWindow counts requests per client in the current window. All counts are dropped together
+// when a new window starts, so clients that went away are not kept around.
+type fixedWindow struct {
+	limit  int
+	window time.Duration
+
+	mu     sync.Mutex
+	start  time.Time
+	counts map[string]int
+}
+
+// allow counts a request by client, and returns how long until the next window when it is over the limit
+func (l *fixedWindow) allow(client string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.start) >= l.window {
+		l.start = now
+		clear(l.counts)
+	}
+	if l.counts[client] >= l.limit {
+		return l.window - now.Sub(l.start), false
+	}
+	l.counts[client]++
+	return 0, true
+}
+
+// clientAddr is the remote IP of the request. Forwarded headers are ignored, as any client can set them.
+func clientAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
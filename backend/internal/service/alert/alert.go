@@ -0,0 +1,369 @@
+package alert
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+)
+
+const (
+	pollInterval     = time.Second * 30
+	jobBatchSize     = 50
+	maxSearches      = 50
+	notificationPage = 100
+)
+
+type dbClient interface {
+	GetItemByID(ctx context.Context, id string) (*domain.Item, error)
+	CreateSavedSearch(ctx context.Context, s *domain.SavedSearch) error
+	GetSavedSearchByID(ctx context.Context, id string) (*domain.SavedSearch, error)
+	ListSavedSearchesByUserID(ctx context.Context, userID string) ([]*domain.SavedSearch, error)
+	ListSavedSearches(ctx context.Context) ([]*domain.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, id string) error
+	RecordSearchMatch(ctx context.Context, searchID, itemID string) (bool, error)
+	UpsertWatchedItem(ctx context.Context, w *domain.WatchedItem) error
+	UpdateWatchedItemPrice(ctx context.Context, userID, itemID string, price domain.Money) error
+	DeleteWatchedItem(ctx context.Context, userID, itemID string) error
+	ListWatchedItemsByUserID(ctx context.Context, userID string) ([]*domain.WatchedItem, error)
+	ListWatchedItemsByItemID(ctx context.Context, itemID string) ([]*domain.WatchedItem, error)
+	CreateNotification(ctx context.Context, n *domain.Notification) error
+	ListNotificationsByUserID(ctx context.Context, userID string, limit int) ([]*domain.Notification, error)
+	ListPendingNotifications(ctx context.Context, userID string) ([]*domain.Notification, error)
+	ListUsersWithPendingNotifications(ctx context.Context) ([]string, error)
+	MarkNotificationsSent(ctx context.Context, ids []string, sentAt time.Time) error
+	GetAlertSettings(ctx context.Context, userID string) (*domain.AlertSettings, error)
+	UpsertAlertSettings(ctx context.Context, s *domain.AlertSettings) error
+	UpdateLastDigestAt(ctx context.Context, userID string, at time.Time) error
+	CreateAlertJob(ctx context.Context, job *domain.AlertJob) error
+	ListAlertJobs(ctx context.Context, limit int) ([]*domain.AlertJob, error)
+	DeleteAlertJob(ctx context.Context, id string) error
+}
+
+// Notifier is an adapter delivering digests through a channel, such as a webhook or email
+type Notifier interface {
+	// ValidateAddress checks that the channel can deliver to the address a user gives
+	ValidateAddress(address string) error
+	Notify(ctx context.Context, address string, d *domain.Digest) error
+}
+
+type Service struct {
+	dbClient  dbClient
+	notifiers map[string]Notifier
+	now       func() time.Time
+}
+
+// New returns an alert service sending digests through notifiers, keyed by the channel name users choose.
+// The default channel must be among them.
+func New(dbClient dbClient, notifiers map[string]Notifier) *Service {
+	return &Service{
+		dbClient:  dbClient,
+		notifiers: notifiers,
+		now:       time.Now,
+	}
+}
+
+func (s *Service) CreateSearch(ctx context.Context, search *domain.SavedSearch) error {
+	if err := search.Validate(); err != nil {
+		return fmt.Errorf("CreateSearch: %w", err)
+	}
+	search.UserID = utils.FromContext(ctx)
+	searches, err := s.dbClient.ListSavedSearchesByUserID(ctx, search.UserID)
+	if err != nil {
+		return fmt.Errorf("CreateSearch: s.dbClient.ListSavedSearchesByUserID: %w", err)
+	}
+	if len(searches) >= maxSearches {
+		return fmt.Errorf("CreateSearch: a user can save at most %d searches: %w", maxSearches, domain.ErrConflict)
+	}
+	if err := s.dbClient.CreateSavedSearch(ctx, search); err != nil {
+		return fmt.Errorf("CreateSearch: s.dbClient.CreateSavedSearch: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) ListSearches(ctx context.Context) ([]*domain.SavedSearch, error) {
+	searches, err := s.dbClient.ListSavedSearchesByUserID(ctx, utils.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("ListSearches: s.dbClient.ListSavedSearchesByUserID: %w", err)
+	}
+	return searches, nil
+}
+
+func (s *Service) DeleteSearch(ctx context.Context, id string) error {
+	search, err := s.dbClient.GetSavedSearchByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("DeleteSearch: s.dbClient.GetSavedSearchByID: %w", err)
+	}
+	if search.UserID != utils.FromContext(ctx) {
+		return fmt.Errorf("DeleteSearch: search (%s) is not owned by the caller: %w", id, domain.ErrForbidden)
+	}
+	if err := s.dbClient.DeleteSavedSearch(ctx, id); err != nil {
+		return fmt.Errorf("DeleteSearch: s.dbClient.DeleteSavedSearch: %w", err)
+	}
+	return nil
+}
+
+// WatchItem adds the item to the caller's watchlist. Price drops are measured from its current price,
+// also when the item was already watched.
+func (s *Service) WatchItem(ctx context.Context, itemID string) (*domain.WatchedItem, error) {
+	item, err := s.dbClient.GetItemByID(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("WatchItem: s.dbClient.GetItemByID: %w", err)
+	}
+	uid := utils.FromContext(ctx)
+	if item.SellerID == uid {
+		return nil, fmt.Errorf("WatchItem: sellers cannot watch their own item (%s): %w", itemID, domain.ErrInvalidArgument)
+	}
+	w := &domain.WatchedItem{UserID: uid, ItemID: itemID, Price: item.Price}
+	if err := s.dbClient.UpsertWatchedItem(ctx, w); err != nil {
+		return nil, fmt.Errorf("WatchItem: s.dbClient.UpsertWatchedItem: %w", err)
+	}
+	return w, nil
+}
+
+func (s *Service) UnwatchItem(ctx context.Context, itemID string) error {
+	if err := s.dbClient.DeleteWatchedItem(ctx, utils.FromContext(ctx), itemID); err != nil {
+		return fmt.Errorf("UnwatchItem: s.dbClient.DeleteWatchedItem: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) ListWatchlist(ctx context.Context) ([]*domain.WatchedItem, error) {
+	watched, err := s.dbClient.ListWatchedItemsByUserID(ctx, utils.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("ListWatchlist: s.dbClient.ListWatchedItemsByUserID: %w", err)
+	}
+	return watched, nil
+}
+
+// ListNotifications returns the caller's latest notifications, sent or not
+func (s *Service) ListNotifications(ctx context.Context) ([]*domain.Notification, error) {
+	notifications, err := s.dbClient.ListNotificationsByUserID(ctx, utils.FromContext(ctx), notificationPage)
+	if err != nil {
+		return nil, fmt.Errorf("ListNotifications: s.dbClient.ListNotificationsByUserID: %w", err)
+	}
+	return notifications, nil
+}
+
+func (s *Service) GetSettings(ctx context.Context) (*domain.AlertSettings, error) {
+	settings, err := s.settings(ctx, utils.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("GetSettings: %w", err)
+	}
+	return settings, nil
+}
+
+func (s *Service) UpdateSettings(ctx context.Context, settings *domain.AlertSettings) error {
+	if !settings.Frequency.Valid() {
+		return fmt.Errorf("UpdateSettings: unknown digest frequency (%s): %w", settings.Frequency, domain.ErrInvalidArgument)
+	}
+	n, ok := s.notifiers[settings.Channel]
+	if !ok {
+		return fmt.Errorf("UpdateSettings: channel (%s) must be one of %v: %w", settings.Channel, s.Channels(), domain.ErrInvalidArgument)
+	}
+	if err := n.ValidateAddress(settings.Address); err != nil {
+		return fmt.Errorf("UpdateSettings: %w", err)
+	}
+
+	settings.UserID = utils.FromContext(ctx)
+	if err := s.dbClient.UpsertAlertSettings(ctx, settings); err != nil {
+		return fmt.Errorf("UpdateSettings: s.dbClient.UpsertAlertSettings: %w", err)
+	}
+	return nil
+}
+
+// settings returns the user's alert settings, or immediate digests to the default channel when they have none
+func (s *Service) settings(ctx context.Context, userID string) (*domain.AlertSettings, error) {
+	settings, err := s.dbClient.GetAlertSettings(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return &domain.AlertSettings{UserID: userID, Frequency: domain.DigestFrequencyImmediate, Channel: domain.DefaultAlertChannel}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("s.dbClient.GetAlertSettings: %w", err)
+	}
+	return settings, nil
+}
+
+// Publish queues new listings and price changes for the matcher run by Run, so that
+// matching many searches does not slow down the request that changed the item
+func (s *Service) Publish(ctx context.Context, eventType domain.EventType, data any) error {
+	if eventType != domain.EventItemListed && eventType != domain.EventItemPriceChanged {
+		return nil
+	}
+	item, ok := data.(*domain.Item)
+	if !ok {
+		return fmt.Errorf("Publish: %s event carries %T instead of an item", eventType, data)
+	}
+	if err := s.dbClient.CreateAlertJob(ctx, &domain.AlertJob{ItemID: item.ID, EventType: eventType}); err != nil {
+		return fmt.Errorf("Publish: s.dbClient.CreateAlertJob: %w", err)
+	}
+	return nil
+}
+
+// Run matches queued listing changes and sends due digests until ctx is cancelled
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.match(ctx); err != nil {
+				log.Printf("Failed to match alerts: %s", err.Error())
+			}
+			if err := s.sendDigests(ctx); err != nil {
+				log.Printf("Failed to send alert digests: %s", err.Error())
+			}
+		}
+	}
+}
+
+func (s *Service) match(ctx context.Context) error {
+	jobs, err := s.dbClient.ListAlertJobs(ctx, jobBatchSize)
+	if err != nil {
+		return fmt.Errorf("s.dbClient.ListAlertJobs: %w", err)
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+	searches, err := s.dbClient.ListSavedSearches(ctx)
+	if err != nil {
+		return fmt.Errorf("s.dbClient.ListSavedSearches: %w", err)
+	}
+	for _, job := range jobs {
+		if err := s.matchItem(ctx, job.ItemID, searches); err != nil {
+			return fmt.Errorf("s.matchItem on job (%s): %w", job.ID, err)
+		}
+		if err := s.dbClient.DeleteAlertJob(ctx, job.ID); err != nil {
+			return fmt.Errorf("s.dbClient.DeleteAlertJob: %w", err)
+		}
+	}
+	return nil
+}
+
+// matchItem notifies the owners of searches the item newly matches, and the watchers of the item
+// when its price went down since they last heard of it
+func (s *Service) matchItem(ctx context.Context, itemID string, searches []*domain.SavedSearch) error {
+	item, err := s.dbClient.GetItemByID(ctx, itemID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("s.dbClient.GetItemByID: %w", err)
+	}
+	// The item may have been sold or cancelled since the job was queued
+	if item.State != domain.ItemStateListed {
+		return nil
+	}
+
+	for _, search := range searches {
+		if search.UserID == item.SellerID || !search.Matches(item) {
+			continue
+		}
+		// A search notifies about an item once, however often it is relisted or repriced
+		if isNew, err := s.dbClient.RecordSearchMatch(ctx, search.ID, item.ID); err != nil {
+			return fmt.Errorf("s.dbClient.RecordSearchMatch: %w", err)
+		} else if !isNew {
+			continue
+		}
+		n := &domain.Notification{
+			UserID:   search.UserID,
+			Kind:     domain.NotificationKindMatch,
+			ItemID:   item.ID,
+			ItemName: item.Name,
+			Price:    item.Price,
+			SearchID: search.ID,
+		}
+		if err := s.dbClient.CreateNotification(ctx, n); err != nil {
+			return fmt.Errorf("s.dbClient.CreateNotification: %w", err)
+		}
+	}
+
+	watched, err := s.dbClient.ListWatchedItemsByItemID(ctx, item.ID)
+	if err != nil {
+		return fmt.Errorf("s.dbClient.ListWatchedItemsByItemID: %w", err)
+	}
+	for _, w := range watched {
+		if w.Price.Currency() != item.Price.Currency() || item.Price.Cmp(w.Price) >= 0 {
+			continue
+		}
+		previous := w.Price
+		n := &domain.Notification{
+			UserID:        w.UserID,
+			Kind:          domain.NotificationKindPriceDrop,
+			ItemID:        item.ID,
+			ItemName:      item.Name,
+			Price:         item.Price,
+			PreviousPrice: &previous,
+		}
+		if err := s.dbClient.CreateNotification(ctx, n); err != nil {
+			return fmt.Errorf("s.dbClient.CreateNotification: %w", err)
+		}
+		if err := s.dbClient.UpdateWatchedItemPrice(ctx, w.UserID, w.ItemID, item.Price); err != nil {
+			return fmt.Errorf("s.dbClient.UpdateWatchedItemPrice: %w", err)
+		}
+	}
+	return nil
+}
+
+// sendDigests sends every user whose digest is due their pending notifications. A user whose
+// channel fails keeps their notifications pending for the next run.
+func (s *Service) sendDigests(ctx context.Context) error {
+	users, err := s.dbClient.ListUsersWithPendingNotifications(ctx)
+	if err != nil {
+		return fmt.Errorf("s.dbClient.ListUsersWithPendingNotifications: %w", err)
+	}
+	now := s.now().UTC()
+	for _, uid := range users {
+		settings, err := s.settings(ctx, uid)
+		if err != nil {
+			return err
+		}
+		if !settings.DigestDue(now) {
+			continue
+		}
+		n, ok := s.notifiers[settings.Channel]
+		if !ok {
+			log.Printf("User (%s) gets alerts through channel (%s) which is no longer configured", uid, settings.Channel)
+			continue
+		}
+		pending, err := s.dbClient.ListPendingNotifications(ctx, uid)
+		if err != nil {
+			return fmt.Errorf("s.dbClient.ListPendingNotifications: %w", err)
+		}
+		if len(pending) == 0 {
+			continue
+		}
+		if err := n.Notify(ctx, settings.Address, &domain.Digest{UserID: uid, Notifications: pending}); err != nil {
+			log.Printf("Failed to send digest to user (%s) through channel (%s): %s", uid, settings.Channel, err.Error())
+			continue
+		}
+
+		ids := make([]string, 0, len(pending))
+		for _, p := range pending {
+			ids = append(ids, p.ID)
+		}
+		if err := s.dbClient.MarkNotificationsSent(ctx, ids, now); err != nil {
+			return fmt.Errorf("s.dbClient.MarkNotificationsSent: %w", err)
+		}
+		if err := s.dbClient.UpdateLastDigestAt(ctx, uid, now); err != nil {
+			return fmt.Errorf("s.dbClient.UpdateLastDigestAt: %w", err)
+		}
+	}
+	return nil
+}
+
+// Channels returns the names of the configured notifiers
+func (s *Service) Channels() []string {
+	channels := make([]string, 0, len(s.notifiers))
+	for name := range s.notifiers {
+		channels = append(channels, name)
+	}
+	slices.Sort(channels)
+	return channels
+}
@@ -0,0 +1,254 @@
+package dispute
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	maxNote     = 4000
+	maxEvidence = 10
+	// releaseInterval is how often sales whose dispute window has passed are paid out
+	releaseInterval = time.Minute
+)
+
+type dbClient interface {
+	GetItemByID(ctx context.Context, id string) (*domain.Item, error)
+	CreateDispute(ctx context.Context, d *domain.Dispute) error
+	GetDisputeByID(ctx context.Context, id string) (*domain.Dispute, error)
+	ListDisputesByState(ctx context.Context, state domain.DisputeState) ([]*domain.Dispute, error)
+	UpdateDisputeState(ctx context.Context, d *domain.Dispute, from domain.DisputeState) error
+	CreateDisputeEntry(ctx context.Context, e *domain.DisputeEntry) error
+	ListDisputeEntries(ctx context.Context, disputeID string) ([]*domain.DisputeEntry, error)
+	ListSalesToRelease(ctx context.Context, completedBefore time.Time) ([]*domain.Sale, error)
+}
+
+type itemService interface {
+	DisputeItem(ctx context.Context, id string) (*domain.Item, error)
+	ResolveDispute(ctx context.Context, id string, refund bool) error
+	SettleSale(ctx context.Context, sale *domain.Sale) error
+}
+
+type eventPublisher interface {
+	Publish(ctx context.Context, eventType domain.EventType, data any) error
+}
+
+type Service struct {
+	dbClient       dbClient
+	itemService    itemService
+	eventPublisher eventPublisher
+	// adminID is the user who arbitrates disputes, whose signing key must be the shared contract's arbiter
+	adminID string
+	// window is how long after receipt the buyer can still dispute, before the seller is paid
+	window time.Duration
+	now    func() time.Time
+}
+
+func New(dbClient dbClient, itemService itemService, eventPublisher eventPublisher, adminID string, window time.Duration) *Service {
+	return &Service{
+		dbClient:       dbClient,
+		itemService:    itemService,
+		eventPublisher: eventPublisher,
+		adminID:        adminID,
+		window:         window,
+		now:            time.Now,
+	}
+}
+
+// OpenDispute is the buyer asking to return an item they have not confirmed receipt of, or received
+// within the dispute window. The sale is frozen on chain until an admin resolves the dispute.
+func (s *Service) OpenDispute(ctx context.Context, itemID, reason string, evidence []string) (*domain.Dispute, error) {
+	if err := validateNote(reason, evidence); err != nil {
+		return nil, fmt.Errorf("OpenDispute: %w", err)
+	}
+	item, err := s.itemService.DisputeItem(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("OpenDispute: s.itemService.DisputeItem: %w", err)
+	}
+
+	d := &domain.Dispute{
+		ItemID:   itemID,
+		BuyerID:  item.BuyerID,
+		SellerID: item.SellerID,
+		Reason:   reason,
+		State:    domain.DisputeStateOpen,
+	}
+	if err := s.dbClient.CreateDispute(ctx, d); err != nil {
+		return nil, fmt.Errorf("OpenDispute: s.dbClient.CreateDispute: %w", err)
+	}
+	if err := s.record(ctx, d, domain.DisputeActionOpened, reason, evidence); err != nil {
+		return nil, fmt.Errorf("OpenDispute: %w", err)
+	}
+	s.publish(ctx, domain.EventDisputeOpened, d)
+	return d, nil
+}
+
+// RespondToDispute is the seller's side of the story, after which the dispute waits for arbitration
+func (s *Service) RespondToDispute(ctx context.Context, id, note string, evidence []string) (*domain.Dispute, error) {
+	if err := validateNote(note, evidence); err != nil {
+		return nil, fmt.Errorf("RespondToDispute: %w", err)
+	}
+	d, err := s.dbClient.GetDisputeByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("RespondToDispute: s.dbClient.GetDisputeByID: %w", err)
+	}
+	if d.SellerID != utils.FromContext(ctx) {
+		return nil, fmt.Errorf("RespondToDispute: only the seller can respond to dispute (%s): %w", id, domain.ErrForbidden)
+	}
+	if d.State != domain.DisputeStateOpen {
+		return nil, fmt.Errorf("RespondToDispute: dispute (%s) is %s: %w", id, d.State, domain.ErrConflict)
+	}
+
+	d.State = domain.DisputeStateResponded
+	if err := s.dbClient.UpdateDisputeState(ctx, d, domain.DisputeStateOpen); err != nil {
+		return nil, fmt.Errorf("RespondToDispute: s.dbClient.UpdateDisputeState: %w", err)
+	}
+	if err := s.record(ctx, d, domain.DisputeActionResponded, note, evidence); err != nil {
+		return nil, fmt.Errorf("RespondToDispute: %w", err)
+	}
+	s.publish(ctx, domain.EventDisputeResponded, d)
+	return d, nil
+}
+
+// ResolveDispute is the admin's ruling. It does not wait for the seller, who could otherwise hold the payment hostage.
+func (s *Service) ResolveDispute(ctx context.Context, id string, outcome domain.DisputeOutcome, note string) (*domain.Dispute, error) {
+	if !s.isAdmin(ctx) {
+		return nil, fmt.Errorf("ResolveDispute: only an admin can resolve dispute (%s): %w", id, domain.ErrForbidden)
+	}
+	if !outcome.Valid() {
+		return nil, fmt.Errorf("ResolveDispute: outcome (%s) must be %s or %s: %w", outcome, domain.DisputeOutcomeRefund, domain.DisputeOutcomeRelease, domain.ErrInvalidArgument)
+	}
+	if err := validateNote(note, nil); err != nil {
+		return nil, fmt.Errorf("ResolveDispute: %w", err)
+	}
+	d, err := s.dbClient.GetDisputeByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("ResolveDispute: s.dbClient.GetDisputeByID: %w", err)
+	}
+	if d.State != domain.DisputeStateOpen && d.State != domain.DisputeStateResponded {
+		return nil, fmt.Errorf("ResolveDispute: dispute (%s) is already %s: %w", id, d.State, domain.ErrConflict)
+	}
+
+	refund := outcome == domain.DisputeOutcomeRefund
+	if err := s.itemService.ResolveDispute(ctx, d.ItemID, refund); err != nil {
+		return nil, fmt.Errorf("ResolveDispute: s.itemService.ResolveDispute: %w", err)
+	}
+	from := d.State
+	d.State = domain.DisputeStateReleased
+	if refund {
+		d.State = domain.DisputeStateRefunded
+	}
+	d.ResolvedBy = utils.FromContext(ctx)
+	if err := s.dbClient.UpdateDisputeState(ctx, d, from); err != nil {
+		return nil, fmt.Errorf("ResolveDispute: s.dbClient.UpdateDisputeState: %w", err)
+	}
+	if err := s.record(ctx, d, domain.DisputeActionResolved, fmt.Sprintf("%s: %s", outcome, note), nil); err != nil {
+		return nil, fmt.Errorf("ResolveDispute: %w", err)
+	}
+	s.publish(ctx, domain.EventDisputeResolved, d)
+	return d, nil
+}
+
+// GetDispute returns the dispute with its audit trail to the buyer, the seller and admins
+func (s *Service) GetDispute(ctx context.Context, id string) (*domain.Dispute, error) {
+	d, err := s.dbClient.GetDisputeByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("GetDispute: s.dbClient.GetDisputeByID: %w", err)
+	}
+	uid := utils.FromContext(ctx)
+	if d.BuyerID != uid && d.SellerID != uid && !s.isAdmin(ctx) {
+		return nil, fmt.Errorf("GetDispute: only the parties and admins can see dispute (%s): %w", id, domain.ErrForbidden)
+	}
+	if d.Trail, err = s.dbClient.ListDisputeEntries(ctx, id); err != nil {
+		return nil, fmt.Errorf("GetDispute: s.dbClient.ListDisputeEntries: %w", err)
+	}
+	return d, nil
+}
+
+// ListDisputes is the admins' queue of disputes in state
+func (s *Service) ListDisputes(ctx context.Context, state domain.DisputeState) ([]*domain.Dispute, error) {
+	if !s.isAdmin(ctx) {
+		return nil, fmt.Errorf("ListDisputes: only an admin can list disputes: %w", domain.ErrForbidden)
+	}
+	disputes, err := s.dbClient.ListDisputesByState(ctx, state)
+	if err != nil {
+		return nil, fmt.Errorf("ListDisputes: s.dbClient.ListDisputesByState: %w", err)
+	}
+	return disputes, nil
+}
+
+func (s *Service) isAdmin(ctx context.Context) bool {
+	return len(s.adminID) > 0 && utils.FromContext(ctx) == s.adminID
+}
+
+// record appends a step by the caller to the dispute's audit trail
+func (s *Service) record(ctx context.Context, d *domain.Dispute, action domain.DisputeAction, note string, evidence []string) error {
+	e := &domain.DisputeEntry{
+		DisputeID: d.ID,
+		ActorID:   utils.FromContext(ctx),
+		Action:    action,
+		Note:      note,
+		Evidence:  evidence,
+	}
+	if err := s.dbClient.CreateDisputeEntry(ctx, e); err != nil {
+		return fmt.Errorf("s.dbClient.CreateDisputeEntry: %w", err)
+	}
+	return nil
+}
+
+func validateNote(note string, evidence []string) error {
+	if len(strings.TrimSpace(note)) == 0 || len(note) > maxNote {
+		return fmt.Errorf("note must be between 1 and %d bytes: %w", maxNote, domain.ErrInvalidArgument)
+	}
+	if len(evidence) > maxEvidence {
+		return fmt.Errorf("at most %d pieces of evidence can be attached: %w", maxEvidence, domain.ErrInvalidArgument)
+	}
+	for _, e := range evidence {
+		u, err := url.Parse(e)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("evidence (%s) must be an absolute http(s) url: %w", e, domain.ErrInvalidArgument)
+		}
+	}
+	return nil
+}
+
+func (s *Service) publish(ctx context.Context, eventType domain.EventType, d *domain.Dispute) {
+	if err := s.eventPublisher.Publish(ctx, eventType, d); err != nil {
+		log.Printf("Failed to publish %s event for dispute (%s): %s", eventType, d.ID, err.Error())
+	}
+}
+
+// Run pays out the sales whose dispute window has passed until ctx is done
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(releaseInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.release(ctx); err != nil {
+				log.Printf("Failed to release payments: %s", err.Error())
+			}
+		}
+	}
+}
+
+func (s *Service) release(ctx context.Context) error {
+	sales, err := s.dbClient.ListSalesToRelease(ctx, s.now().Add(-s.window).UTC())
+	if err != nil {
+		return fmt.Errorf("s.dbClient.ListSalesToRelease: %w", err)
+	}
+	for _, sale := range sales {
+		if err := s.itemService.SettleSale(ctx, sale); err != nil {
+			log.Printf("Failed to release payment of sale (%s): %s", sale.ID, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package estimate
+
+import (
+	"backend/internal/domain"
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"sort"
+	"time"
+)
+
+const (
+	// similarWeight discounts a sale of a similar item against a sale of the item itself
+	similarWeight = 0.5
+	// maxGradeGap is how many condition grades apart a similar item can be. Each grade halves its weight.
+	maxGradeGap = 2
+	// horizon is how many half-lives back sales are looked at, beyond which they would weigh less than 1%
+	horizon = 7
+)
+
+type dbClient interface {
+	GetItemByID(ctx context.Context, id string) (*domain.Item, error)
+	ListSalesByItemID(ctx context.Context, itemID string) ([]*domain.Sale, error)
+	ListSettledSalesByCategory(ctx context.Context, category string, since time.Time) ([]*domain.ComparableSale, error)
+}
+
+type Service struct {
+	dbClient dbClient
+	// halfLife is the age at which a sale counts half as much as one made now
+	halfLife time.Duration
+	now      func() time.Time
+}
+
+func New(dbClient dbClient, halfLife time.Duration) *Service {
+	return &Service{
+		dbClient: dbClient,
+		halfLife: halfLife,
+		now:      time.Now,
+	}
+}
+
+// Estimate predicts the fair price range of an item on record, in the currency of its price
+func (s *Service) Estimate(ctx context.Context, itemID string) (*domain.PriceEstimate, error) {
+	item, err := s.dbClient.GetItemByID(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("Estimate: s.dbClient.GetItemByID: %w", err)
+	}
+	return s.EstimateItem(ctx, item)
+}
+
+// comparable is a past sale weighted by how much it tells about the item's price
+type comparable struct {
+	price  *big.Int
+	weight float64
+}
+
+// EstimateItem predicts the fair price range of an item, which does not have to be stored yet.
+// Settled sales of the item itself and of similar items in its category count, in the currency of the item's
+// price. Similar sales weigh less the further their condition and attributes are from the item's, and older
+// sales count exponentially less.
+func (s *Service) EstimateItem(ctx context.Context, item *domain.Item) (*domain.PriceEstimate, error) {
+	currency := item.Price.Currency()
+	if len(currency) == 0 {
+		return nil, fmt.Errorf("EstimateItem: item has no price to take the currency from: %w", domain.ErrInvalidArgument)
+	}
+	now := s.now()
+	since := now.Add(-horizon * s.halfLife)
+	est := &domain.PriceEstimate{ItemID: item.ID}
+
+	var comps []comparable
+	add := func(sale *domain.Sale, weight float64) bool {
+		// Sales still in escrow may yet be refunded, and refunded or cancelled ones never went through
+		if !sale.Settled() || sale.Price.Currency() != currency || sale.CreatedAt.Before(since) {
+			return false
+		}
+		age := now.Sub(sale.CreatedAt).Hours() / s.halfLife.Hours()
+		comps = append(comps, comparable{price: sale.Price.Amount(), weight: weight * math.Pow(0.5, math.Max(age, 0))})
+		return true
+	}
+	if len(item.ID) > 0 {
+		sales, err := s.dbClient.ListSalesByItemID(ctx, item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("EstimateItem: s.dbClient.ListSalesByItemID: %w", err)
+		}
+		for _, sale := range sales {
+			if add(sale, 1) {
+				est.OwnSales++
+			}
+		}
+	}
+	if len(item.Category) > 0 {
+		sales, err := s.dbClient.ListSettledSalesByCategory(ctx, item.Category, since)
+		if err != nil {
+			return nil, fmt.Errorf("EstimateItem: s.dbClient.ListSettledSalesByCategory: %w", err)
+		}
+		for _, cs := range sales {
+			if cs.Sale.ItemID == item.ID {
+				continue
+			}
+			if weight := similarity(item, cs); weight > 0 && add(cs.Sale, similarWeight*weight) {
+				est.SimilarSales++
+			}
+		}
+	}
+	if len(comps) == 0 {
+		return nil, fmt.Errorf("EstimateItem: no sales in %s to estimate item (%s) from: %w", currency, item.ID, domain.ErrNotFound)
+	}
+
+	sort.SliceStable(comps, func(i, j int) bool { return comps[i].price.Cmp(comps[j].price) < 0 })
+	var err error
+	if est.Low, err = domain.NewMoney(quantile(comps, 0.25), currency); err != nil {
+		return nil, fmt.Errorf("EstimateItem: %w", err)
+	}
+	if est.Suggested, err = domain.NewMoney(quantile(comps, 0.5), currency); err != nil {
+		return nil, fmt.Errorf("EstimateItem: %w", err)
+	}
+	if est.High, err = domain.NewMoney(quantile(comps, 0.75), currency); err != nil {
+		return nil, fmt.Errorf("EstimateItem: %w", err)
+	}
+	est.Confidence = confidence(comps, est)
+	return est, nil
+}
+
+// similarity weighs a sale of another item in the item's category between 0 and 1. Each condition grade between
+// the two halves it, and items more than maxGradeGap grades apart or where only one is graded do not compare. It
+// is scaled by the share of the item's attributes the other item has the same value for, counting one extra match
+// so that a sale sharing none still counts a little.
+func similarity(item *domain.Item, cs *domain.ComparableSale) float64 {
+	gap := 0
+	if item.Condition != cs.Condition {
+		i, j := slices.Index(domain.Conditions, item.Condition), slices.Index(domain.Conditions, cs.Condition)
+		if i < 0 || j < 0 {
+			return 0
+		}
+		if gap = i - j; gap < 0 {
+			gap = -gap
+		}
+		if gap > maxGradeGap {
+			return 0
+		}
+	}
+	matches := 0
+	for name, value := range item.Attributes {
+		if cs.Attributes[name] == value {
+			matches++
+		}
+	}
+	return math.Pow(0.5, float64(gap)) * float64(matches+1) / float64(len(item.Attributes)+1)
+}
+
+// quantile returns the price below which q of the total weight of comps lies. comps must be sorted by price.
+func quantile(comps []comparable, q float64) *big.Int {
+	var total float64
+	for _, c := range comps {
+		total += c.weight
+	}
+	var cum float64
+	for _, c := range comps {
+		cum += c.weight
+		if cum >= q*total {
+			return c.price
+		}
+	}
+	return comps[len(comps)-1].price
+}
+
+// confidence grows with the effective number of sales, which discounts old and similar sales by their weight.
+// It drops a level when the range is wider than half the suggested price, as the sales disagree.
+func confidence(comps []comparable, est *domain.PriceEstimate) domain.EstimateConfidence {
+	var sum, sumSq float64
+	for _, c := range comps {
+		sum += c.weight
+		sumSq += c.weight * c.weight
+	}
+	effective := sum * sum / sumSq
+
+	levels := []domain.EstimateConfidence{domain.EstimateConfidenceLow, domain.EstimateConfidenceMedium, domain.EstimateConfidenceHigh}
+	level := 0
+	switch {
+	case effective >= 8:
+		level = 2
+	case effective >= 3:
+		level = 1
+	}
+	spread := new(big.Int).Sub(est.High.Amount(), est.Low.Amount())
+	if level > 0 && new(big.Int).Mul(spread, big.NewInt(2)).Cmp(est.Suggested.Amount()) > 0 {
+		level--
+	}
+	return levels[level]
+}
@@ -0,0 +1,99 @@
+package event
+
+import (
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const subscriberBuffer = 32
+
+type publisher interface {
+	Publish(ctx context.Context, eventType domain.EventType, data any) error
+}
+
+// Broker fans events out to in-process subscribers, such as streaming RPCs,
+// and forwards them to downstream publishers, such as webhooks
+type Broker struct {
+	mu         sync.RWMutex
+	subs       map[chan *domain.Event]struct{}
+	closed     bool
+	publishers []publisher
+}
+
+func NewBroker(publishers ...publisher) *Broker {
+	return &Broker{
+		subs:       make(map[chan *domain.Event]struct{}),
+		publishers: publishers,
+	}
+}
+
+func (b *Broker) Publish(ctx context.Context, eventType domain.EventType, data any) error {
+	e := &domain.Event{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      data,
+	}
+
+	b.mu.RLock()
+	for ch := range b.subs {
+		// A slow subscriber must not block the caller, so it misses the event instead
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, p := range b.publishers {
+		if err := p.Publish(ctx, eventType, data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("Publish: %w", err)
+	}
+	return nil
+}
+
+// Subscribe returns a channel receiving every event published from now on.
+// The channel is closed once ctx is done or the broker is closed.
+func (b *Broker) Subscribe(ctx context.Context) <-chan *domain.Event {
+	ch := make(chan *domain.Event, subscriberBuffer)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch
+	}
+	b.subs[ch] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}()
+	return ch
+}
+
+// Close ends every subscription so that long-lived streams can finish on shutdown
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for ch := range b.subs {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,154 @@
+package fee
+
+import (
+	"backend/internal/domain"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Engine splits the price of every sale into the platform fee, the creator royalty and the seller's proceeds
+type Engine struct {
+	schedule   domain.FeeSchedule
+	royaltyBPS int64
+}
+
+func New(schedule domain.FeeSchedule, royaltyBPS int64) (*Engine, error) {
+	if royaltyBPS < 0 || royaltyBPS > domain.BasisPoints {
+		return nil, fmt.Errorf("royalty of %d basis points is out of range: %w", royaltyBPS, domain.ErrInvalidArgument)
+	}
+	return &Engine{
+		schedule:   schedule,
+		royaltyBPS: royaltyBPS,
+	}, nil
+}
+
+// Split computes the sale of item to buyerID. The creator earns a royalty only on resale,
+// and the royalty is taken from what is left after the platform fee.
+func (e *Engine) Split(item *domain.Item, buyerID string) (*domain.Sale, error) {
+	sale := &domain.Sale{
+		ItemID:    item.ID,
+		SellerID:  item.SellerID,
+		BuyerID:   buyerID,
+		CreatorID: item.CreatorID,
+		Price:     item.Price,
+	}
+	sale.PlatformFee = e.schedule.Fee(item.Price)
+	rest, err := item.Price.Sub(sale.PlatformFee)
+	if err != nil {
+		return nil, fmt.Errorf("price after platform fee: %w", err)
+	}
+	sale.Royalty = domain.ZeroMoney(item.Price.Currency())
+	if len(item.CreatorID) > 0 && item.CreatorID != item.SellerID {
+		sale.Royalty = item.Price.MulBasisPoints(e.royaltyBPS).Min(rest)
+	}
+	if sale.SellerProceeds, err = rest.Sub(sale.Royalty); err != nil {
+		return nil, fmt.Errorf("price after royalty: %w", err)
+	}
+	return sale, nil
+}
+
+// ParseSchedule reads a fee schedule in one of the forms
+//
+//	percentage:<basis points>
+//	flat:<currency>=<amount>,...
+//	tiered:<currency>:<up to>/...=<basis points>,...,*=<basis points>
+//
+// Flat fees and tier bounds are in minor units of their currency, such as flat:USD=100,KLD=5 or
+// tiered:USD:10000/KLD:500=250,USD:100000=150,*=100. Tiers must be in ascending order in every currency,
+// a price in a currency a tier has no bound for falls through to the next one, and * matches any price.
+func ParseSchedule(s string) (domain.FeeSchedule, error) {
+	kind, value, ok := strings.Cut(s, ":")
+	if !ok {
+		return domain.FeeSchedule{}, fmt.Errorf("fee schedule (%s) is not <kind>:<value>: %w", s, domain.ErrInvalidArgument)
+	}
+
+	schedule := domain.FeeSchedule{Kind: domain.FeeKind(kind)}
+	switch schedule.Kind {
+	case domain.FeeKindPercentage:
+		bps, err := parseBasisPoints(value)
+		if err != nil {
+			return domain.FeeSchedule{}, err
+		}
+		schedule.BasisPoints = bps
+	case domain.FeeKindFlat:
+		schedule.Flat = make(map[string]domain.Money)
+		for _, entry := range strings.Split(value, ",") {
+			currency, amount, ok := strings.Cut(entry, "=")
+			if !ok {
+				return domain.FeeSchedule{}, fmt.Errorf("flat fee (%s) is not <currency>=<amount>: %w", entry, domain.ErrInvalidArgument)
+			}
+			if _, dup := schedule.Flat[currency]; dup {
+				return domain.FeeSchedule{}, fmt.Errorf("flat fee in %s is given twice: %w", currency, domain.ErrInvalidArgument)
+			}
+			flat, err := domain.ParseMoney(amount, currency)
+			if err != nil {
+				return domain.FeeSchedule{}, fmt.Errorf("flat fee (%s): %w", entry, err)
+			}
+			schedule.Flat[currency] = flat
+		}
+	case domain.FeeKindTiered:
+		prev := make(map[string]domain.Money)
+		tiers := strings.Split(value, ",")
+		for i, tier := range tiers {
+			bounds, rate, ok := strings.Cut(tier, "=")
+			if !ok {
+				return domain.FeeSchedule{}, fmt.Errorf("fee tier (%s) is not <up to>=<basis points>: %w", tier, domain.ErrInvalidArgument)
+			}
+			bps, err := parseBasisPoints(rate)
+			if err != nil {
+				return domain.FeeSchedule{}, err
+			}
+			t := domain.FeeTier{BasisPoints: bps}
+			if bounds == "*" {
+				if i != len(tiers)-1 {
+					return domain.FeeSchedule{}, fmt.Errorf("fee tier * must be the last tier: %w", domain.ErrInvalidArgument)
+				}
+			} else if t.UpTo, err = parseTierBounds(bounds, prev); err != nil {
+				return domain.FeeSchedule{}, fmt.Errorf("fee tier %d: %w", i, err)
+			}
+			schedule.Tiers = append(schedule.Tiers, t)
+		}
+		if last := schedule.Tiers[len(schedule.Tiers)-1]; len(last.UpTo) != 0 {
+			return domain.FeeSchedule{}, fmt.Errorf("fee tiers must end with *=<basis points>: %w", domain.ErrInvalidArgument)
+		}
+	default:
+		return domain.FeeSchedule{}, fmt.Errorf("unknown fee kind (%s): %w", kind, domain.ErrInvalidArgument)
+	}
+	return schedule, nil
+}
+
+// parseTierBounds reads the bounds of a tier, <currency>:<amount>/..., each of which must be above the bound
+// in prev of the same currency. prev is updated to the tier's bounds.
+func parseTierBounds(s string, prev map[string]domain.Money) (map[string]domain.Money, error) {
+	upTo := make(map[string]domain.Money)
+	for _, bound := range strings.Split(s, "/") {
+		currency, amount, ok := strings.Cut(bound, ":")
+		if !ok {
+			return nil, fmt.Errorf("bound (%s) is not <currency>:<amount>: %w", bound, domain.ErrInvalidArgument)
+		}
+		if _, dup := upTo[currency]; dup {
+			return nil, fmt.Errorf("bound in %s is given twice: %w", currency, domain.ErrInvalidArgument)
+		}
+		m, err := domain.ParseMoney(amount, currency)
+		if err != nil {
+			return nil, fmt.Errorf("bound (%s): %w", bound, err)
+		}
+		if p, ok := prev[currency]; ok && m.Cmp(p) <= 0 {
+			return nil, fmt.Errorf("bound (%s) must be above the previous bound of %s in %s: %w", bound, p.Amount(), currency, domain.ErrInvalidArgument)
+		}
+		upTo[currency] = m
+	}
+	for currency, m := range upTo {
+		prev[currency] = m
+	}
+	return upTo, nil
+}
+
+func parseBasisPoints(s string) (int64, error) {
+	bps, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || bps < 0 || bps > domain.BasisPoints {
+		return 0, fmt.Errorf("fee (%s) must be between 0 and %d basis points: %w", s, domain.BasisPoints, domain.ErrInvalidArgument)
+	}
+	return bps, nil
+}
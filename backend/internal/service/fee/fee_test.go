@@ -0,0 +1,153 @@
+package fee
+
+import (
+	"backend/internal/domain"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func money(t *testing.T, amount int64, currency string) domain.Money {
+	t.Helper()
+	m, err := domain.NewMoney(big.NewInt(amount), currency)
+	if err != nil {
+		t.Fatalf("NewMoney(%d, %s): %v", amount, currency, err)
+	}
+	return m
+}
+
+func mustEngine(t *testing.T, schedule string, royaltyBPS int64) *Engine {
+	t.Helper()
+	s, err := ParseSchedule(schedule)
+	if err != nil {
+		t.Fatalf("ParseSchedule(%q): %v", schedule, err)
+	}
+	e, err := New(s, royaltyBPS)
+	if err != nil {
+		t.Fatalf("New(%q, %d): %v", schedule, royaltyBPS, err)
+	}
+	return e
+}
+
+func TestSplitPlatformFee(t *testing.T) {
+	const tiered = "tiered:USD:10000/KLD:500=500,USD:100000=250,*=100"
+	tests := []struct {
+		name     string
+		schedule string
+		price    domain.Money
+		wantFee  domain.Money
+	}{
+		{name: "percentage", schedule: "percentage:250", price: money(t, 10000, "USD"), wantFee: money(t, 250, "USD")},
+		{name: "percentage rounds down", schedule: "percentage:250", price: money(t, 39, "USD"), wantFee: money(t, 0, "USD")},
+		{name: "zero percentage", schedule: "percentage:0", price: money(t, 10000, "USD"), wantFee: money(t, 0, "USD")},
+		{name: "flat", schedule: "flat:USD=100,KLD=5", price: money(t, 10000, "USD"), wantFee: money(t, 100, "USD")},
+		{name: "flat in another currency", schedule: "flat:USD=100,KLD=5", price: money(t, 10000, "KLD"), wantFee: money(t, 5, "KLD")},
+		{name: "flat without a fee in the currency", schedule: "flat:USD=100", price: money(t, 10000, "KLD"), wantFee: money(t, 0, "KLD")},
+		{name: "flat capped at the price", schedule: "flat:USD=100", price: money(t, 40, "USD"), wantFee: money(t, 40, "USD")},
+		{name: "first tier", schedule: tiered, price: money(t, 10000, "USD"), wantFee: money(t, 500, "USD")},
+		{name: "second tier", schedule: tiered, price: money(t, 10001, "USD"), wantFee: money(t, 250, "USD")},
+		{name: "last tier", schedule: tiered, price: money(t, 100001, "USD"), wantFee: money(t, 1000, "USD")},
+		{name: "tier bounds of the currency", schedule: tiered, price: money(t, 500, "KLD"), wantFee: money(t, 25, "KLD")},
+		{name: "currency without a bound falls through", schedule: tiered, price: money(t, 5000, "KLD"), wantFee: money(t, 50, "KLD")},
+		{name: "currency without any bound", schedule: tiered, price: money(t, 100, "ETH"), wantFee: money(t, 1, "ETH")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := mustEngine(t, tt.schedule, 0)
+			sale, err := e.Split(&domain.Item{ID: "item", SellerID: "alice", Price: tt.price}, "bob")
+			if err != nil {
+				t.Fatalf("Split: %v", err)
+			}
+			if sale.PlatformFee.Currency() != tt.wantFee.Currency() || sale.PlatformFee.Cmp(tt.wantFee) != 0 {
+				t.Errorf("platform fee = %s %s, want %s %s", sale.PlatformFee.Amount(), sale.PlatformFee.Currency(), tt.wantFee.Amount(), tt.wantFee.Currency())
+			}
+			total, err := sale.PlatformFee.Add(sale.Royalty)
+			if err == nil {
+				total, err = total.Add(sale.SellerProceeds)
+			}
+			if err != nil || total.Cmp(tt.price) != 0 {
+				t.Errorf("fee, royalty and proceeds add up to %s (%v), want the price %s", total.Amount(), err, tt.price.Amount())
+			}
+		})
+	}
+}
+
+func TestSplitRoyalty(t *testing.T) {
+	e := mustEngine(t, "percentage:1000", 500)
+	tests := []struct {
+		name         string
+		item         domain.Item
+		wantRoyalty  int64
+		wantProceeds int64
+	}{
+		{name: "first sale by the creator", item: domain.Item{SellerID: "alice", CreatorID: "alice"}, wantRoyalty: 0, wantProceeds: 9000},
+		{name: "resale", item: domain.Item{SellerID: "bob", CreatorID: "alice"}, wantRoyalty: 500, wantProceeds: 8500},
+		{name: "no creator", item: domain.Item{SellerID: "bob"}, wantRoyalty: 0, wantProceeds: 9000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.item.ID, tt.item.Price = "item", money(t, 10000, "USD")
+			sale, err := e.Split(&tt.item, "carol")
+			if err != nil {
+				t.Fatalf("Split: %v", err)
+			}
+			if sale.CreatorID != tt.item.CreatorID || sale.SellerID != tt.item.SellerID || sale.BuyerID != "carol" {
+				t.Errorf("sale parties = %s, %s, %s, want %s, %s, carol", sale.CreatorID, sale.SellerID, sale.BuyerID, tt.item.CreatorID, tt.item.SellerID)
+			}
+			if sale.PlatformFee.Cmp(money(t, 1000, "USD")) != 0 {
+				t.Errorf("platform fee = %s, want 1000", sale.PlatformFee.Amount())
+			}
+			if sale.Royalty.Cmp(money(t, tt.wantRoyalty, "USD")) != 0 {
+				t.Errorf("royalty = %s, want %d", sale.Royalty.Amount(), tt.wantRoyalty)
+			}
+			if sale.SellerProceeds.Cmp(money(t, tt.wantProceeds, "USD")) != 0 {
+				t.Errorf("seller proceeds = %s, want %d", sale.SellerProceeds.Amount(), tt.wantProceeds)
+			}
+		})
+	}
+}
+
+func TestSplitRoyaltyCappedAfterFee(t *testing.T) {
+	// A fee of the whole price leaves nothing for the royalty
+	e := mustEngine(t, "flat:USD=10000", 500)
+	sale, err := e.Split(&domain.Item{ID: "item", SellerID: "bob", CreatorID: "alice", Price: money(t, 10000, "USD")}, "carol")
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	if !sale.Royalty.IsZero() || !sale.SellerProceeds.IsZero() {
+		t.Errorf("royalty = %s, seller proceeds = %s, want 0 and 0", sale.Royalty.Amount(), sale.SellerProceeds.Amount())
+	}
+}
+
+func TestParseScheduleRejects(t *testing.T) {
+	for _, schedule := range []string{
+		"",
+		"percentage",
+		"percentage:-1",
+		"percentage:10001",
+		"flat:100",
+		"flat:USD=1.5",
+		"flat:USD=100,USD=200",
+		"flat:usd=100",
+		"tiered:*=100,USD:100=200",
+		"tiered:USD:100=200",
+		"tiered:100=200,*=100",
+		"tiered:USD:100=200,USD:100=100,*=50",
+		"tiered:USD:100/USD:200=200,*=100",
+		"bogus:1",
+	} {
+		t.Run(schedule, func(t *testing.T) {
+			if _, err := ParseSchedule(schedule); !errors.Is(err, domain.ErrInvalidArgument) {
+				t.Errorf("ParseSchedule(%q) = %v, want %v", schedule, err, domain.ErrInvalidArgument)
+			}
+		})
+	}
+}
+
+func TestNewRejectsRoyaltyOutOfRange(t *testing.T) {
+	for _, bps := range []int64{-1, domain.BasisPoints + 1} {
+		if _, err := New(domain.FeeSchedule{Kind: domain.FeeKindPercentage}, bps); !errors.Is(err, domain.ErrInvalidArgument) {
+			t.Errorf("New with royalty %d = %v, want %v", bps, err, domain.ErrInvalidArgument)
+		}
+	}
+}
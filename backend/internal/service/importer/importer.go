@@ -0,0 +1,306 @@
+package importer
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const pollInterval = time.Second * 5
+
+type dbClient interface {
+	GetItemByID(ctx context.Context, id string) (*domain.Item, error)
+	CreateImportJob(ctx context.Context, job *domain.ImportJob) error
+	CreateImportRows(ctx context.Context, rows []*domain.ImportRow) error
+	GetImportJobByID(ctx context.Context, id string) (*domain.ImportJob, error)
+	ListImportJobsBySellerID(ctx context.Context, sellerID string) ([]*domain.ImportJob, error)
+	ListImportJobsByState(ctx context.Context, state domain.ImportJobState) ([]*domain.ImportJob, error)
+	UpdateImportJobState(ctx context.Context, id string, state domain.ImportJobState) error
+	ListImportRows(ctx context.Context, jobID string) ([]*domain.ImportRow, error)
+	ListImportRowsByState(ctx context.Context, jobID string, state domain.ImportRowState) ([]*domain.ImportRow, error)
+	UpdateImportRow(ctx context.Context, r *domain.ImportRow) error
+	RetryImportJob(ctx context.Context, id string) (int64, error)
+	RecordNFTCreator(ctx context.Context, poolName, nftID, creatorID string) (string, error)
+}
+
+type itemService interface {
+	ListItem(ctx context.Context, item *domain.Item) error
+}
+
+// minter mints an NFT for rows that do not name one
+type minter interface {
+	Pool(name string) (*domain.TokenPool, error)
+	MintToken(ctx context.Context, poolName, uri string) (string, error)
+}
+
+type Service struct {
+	dbClient    dbClient
+	itemService itemService
+	minter      minter
+	// maxRows is the most rows a file can have
+	maxRows int
+	// concurrency is how many rows of a job are listed at once
+	concurrency int
+	// wake starts Run on a new job without waiting for the next poll
+	wake chan struct{}
+}
+
+func New(dbClient dbClient, itemService itemService, minter minter, maxRows, concurrency int) *Service {
+	return &Service{
+		dbClient:    dbClient,
+		itemService: itemService,
+		minter:      minter,
+		maxRows:     maxRows,
+		concurrency: concurrency,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Import validates every row of the file and queues a job listing the valid ones through ListItem.
+// Invalid rows are reported on the job rather than failing the whole file.
+func (s *Service) Import(ctx context.Context, format domain.ImportFormat, data []byte) (*domain.ImportJob, error) {
+	rows, err := parse(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("Import: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("Import: file has no rows: %w", domain.ErrInvalidArgument)
+	}
+	if len(rows) > s.maxRows {
+		return nil, fmt.Errorf("Import: file has %d rows, at most %d can be imported at once: %w", len(rows), s.maxRows, domain.ErrInvalidArgument)
+	}
+	validate(rows)
+
+	job := &domain.ImportJob{SellerID: utils.FromContext(ctx), Format: format, State: domain.ImportJobStatePending}
+	job.Count(rows)
+	if job.Pending == 0 {
+		job.State = domain.ImportJobStateCompleted
+	}
+	if err := s.dbClient.CreateImportJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("Import: s.dbClient.CreateImportJob: %w", err)
+	}
+	for _, r := range rows {
+		r.JobID = job.ID
+	}
+	if err := s.dbClient.CreateImportRows(ctx, rows); err != nil {
+		return nil, fmt.Errorf("Import: s.dbClient.CreateImportRows: %w", err)
+	}
+	job.Rows = rows
+	s.notify()
+	return job, nil
+}
+
+// validate marks rows whose item ListItem would reject as invalid, and gives rows without an item ID a new one,
+// so that every attempt at a row lists the same item
+func validate(rows []*domain.ImportRow) {
+	ids := make(map[string]int, len(rows))
+	for _, r := range rows {
+		if r.State != domain.ImportRowStatePending {
+			continue
+		}
+		if len(r.Item.ID) == 0 {
+			r.Item.ID = uuid.NewString()
+		}
+		err := validateItem(r.Item)
+		if line, ok := ids[r.Item.ID]; ok {
+			err = fmt.Errorf("item (%s) is already imported on line %d: %w", r.Item.ID, line, domain.ErrInvalidArgument)
+		}
+		if err != nil {
+			r.State, r.Item, r.Error = domain.ImportRowStateInvalid, nil, err.Error()
+			continue
+		}
+		ids[r.Item.ID] = r.Line
+	}
+}
+
+// validateItem runs the checks of ListItem that need no lookups. Whether the images were uploaded is checked on listing.
+func validateItem(item *domain.Item) error {
+	if len(item.Price.Currency()) == 0 {
+		return fmt.Errorf("item has no price: %w", domain.ErrInvalidArgument)
+	}
+	if err := item.ValidateDescription(); err != nil {
+		return err
+	}
+	return item.ValidateImages()
+}
+
+func (s *Service) GetImport(ctx context.Context, id string) (*domain.ImportJob, error) {
+	job, err := s.getOwnJob(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("GetImport: %w", err)
+	}
+	if job.Rows, err = s.dbClient.ListImportRows(ctx, id); err != nil {
+		return nil, fmt.Errorf("GetImport: s.dbClient.ListImportRows: %w", err)
+	}
+	return job, nil
+}
+
+func (s *Service) ListImports(ctx context.Context) ([]*domain.ImportJob, error) {
+	jobs, err := s.dbClient.ListImportJobsBySellerID(ctx, utils.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("ListImports: s.dbClient.ListImportJobsBySellerID: %w", err)
+	}
+	return jobs, nil
+}
+
+// RetryImport lists the failed rows of a job again. Rows that were listed, or are still pending, are left alone.
+func (s *Service) RetryImport(ctx context.Context, id string) (*domain.ImportJob, error) {
+	if _, err := s.getOwnJob(ctx, id); err != nil {
+		return nil, fmt.Errorf("RetryImport: %w", err)
+	}
+	n, err := s.dbClient.RetryImportJob(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("RetryImport: s.dbClient.RetryImportJob: %w", err)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("RetryImport: job (%s) has no failed rows: %w", id, domain.ErrConflict)
+	}
+	s.notify()
+	return s.GetImport(ctx, id)
+}
+
+func (s *Service) getOwnJob(ctx context.Context, id string) (*domain.ImportJob, error) {
+	job, err := s.dbClient.GetImportJobByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("s.dbClient.GetImportJobByID: %w", err)
+	}
+	if job.SellerID != utils.FromContext(ctx) {
+		return nil, fmt.Errorf("import job (%s) is not owned by the caller: %w", id, domain.ErrForbidden)
+	}
+	return job, nil
+}
+
+func (s *Service) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run lists the rows of pending jobs until ctx is cancelled. Jobs left pending by a restart are picked up again.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		if err := s.runPending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to run import jobs: %s", err.Error())
+		}
+	}
+}
+
+func (s *Service) runPending(ctx context.Context) error {
+	jobs, err := s.dbClient.ListImportJobsByState(ctx, domain.ImportJobStatePending)
+	if err != nil {
+		return fmt.Errorf("s.dbClient.ListImportJobsByState: %w", err)
+	}
+	for _, job := range jobs {
+		if err := s.runJob(ctx, job); err != nil {
+			return fmt.Errorf("s.runJob on job (%s): %w", job.ID, err)
+		}
+	}
+	return nil
+}
+
+// runJob lists the pending rows of the job as its seller, a few at a time
+func (s *Service) runJob(ctx context.Context, job *domain.ImportJob) error {
+	rows, err := s.dbClient.ListImportRowsByState(ctx, job.ID, domain.ImportRowStatePending)
+	if err != nil {
+		return fmt.Errorf("s.dbClient.ListImportRowsByState: %w", err)
+	}
+
+	sellerCtx := utils.NewContext(ctx, job.SellerID)
+	sem := make(chan struct{}, s.concurrency)
+	var wg sync.WaitGroup
+	for _, r := range rows {
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.listRow(sellerCtx, r)
+		}()
+	}
+	wg.Wait()
+	// Rows interrupted by shutdown stay pending, so the job is finished after the restart
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := s.dbClient.UpdateImportJobState(ctx, job.ID, domain.ImportJobStateCompleted); err != nil {
+		return fmt.Errorf("s.dbClient.UpdateImportJobState: %w", err)
+	}
+	return nil
+}
+
+// listRow mints the row's NFT if it names none, lists the item and records the outcome on the row
+func (s *Service) listRow(ctx context.Context, r *domain.ImportRow) {
+	err := s.list(ctx, r)
+	if ctx.Err() != nil {
+		return
+	}
+	r.State, r.Error = domain.ImportRowStateListed, ""
+	if err != nil {
+		r.State, r.Error = domain.ImportRowStateFailed, err.Error()
+	}
+	if err := s.dbClient.UpdateImportRow(ctx, r); err != nil {
+		log.Printf("Failed to record the outcome of line (%d) of import job (%s): %s", r.Line, r.JobID, err.Error())
+	}
+}
+
+func (s *Service) list(ctx context.Context, r *domain.ImportRow) error {
+	if len(r.Item.NFTID) == 0 {
+		pool, err := s.minter.Pool(r.Item.PoolName)
+		if err != nil {
+			return fmt.Errorf("s.minter.Pool: %w", err)
+		}
+		uri := tokenURI(ctx, pool, r.Item)
+		nftID, err := s.minter.MintToken(ctx, r.Item.PoolName, uri)
+		if err != nil {
+			return fmt.Errorf("s.minter.MintToken: %w", err)
+		}
+		if _, err := s.dbClient.RecordNFTCreator(ctx, pool.Name, nftID, utils.FromContext(ctx)); err != nil {
+			return fmt.Errorf("s.dbClient.RecordNFTCreator: %w", err)
+		}
+		// Kept before listing, so that retrying the row lists the minted NFT instead of minting another
+		r.Item.NFTID = nftID
+		if err := s.dbClient.UpdateImportRow(ctx, r); err != nil {
+			return fmt.Errorf("s.dbClient.UpdateImportRow: %w", err)
+		}
+	}
+
+	err := s.itemService.ListItem(ctx, r.Item)
+	if !errors.Is(err, domain.ErrInvalidTransition) {
+		return err
+	}
+	// The row was listed before a restart cut off recording it
+	item, getErr := s.dbClient.GetItemByID(ctx, r.Item.ID)
+	if getErr == nil && item.State == domain.ItemStateListed && item.SellerID == utils.FromContext(ctx) && item.NFTID == r.Item.NFTID {
+		r.Item = item
+		return nil
+	}
+	return err
+}
+
+// tokenURI returns the URI to mint the item's NFT in pool with, which carries the hash of the item as it will
+// be listed. The user minting it is the creator of the new NFT.
+func tokenURI(ctx context.Context, pool *domain.TokenPool, item *domain.Item) string {
+	listed := *item
+	listed.CreatorID = utils.FromContext(ctx)
+	return listed.Metadata(pool.Name).URI()
+}
@@ -0,0 +1,161 @@
+package importer
+
+import (
+	"backend/internal/domain"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+const (
+	// attributePrefix marks a CSV column holding an attribute, such as attributes.brand
+	attributePrefix = "attributes."
+	// maxLineSize is the longest line a JSON lines file can have
+	maxLineSize = 1 << 20
+)
+
+// csvColumns are the columns a CSV header can have besides attributes. price and currency are required.
+var csvColumns = []string{"item_id", "item_name", "price", "currency", "nft_id", "pool_name", "category", "condition", "images"}
+
+// parse reads the rows of an import file. A row that cannot be read is kept as invalid, while a file that
+// cannot be read at all, such as a CSV with an unknown column, is rejected.
+func parse(format domain.ImportFormat, data []byte) ([]*domain.ImportRow, error) {
+	switch format {
+	case domain.ImportFormatCSV:
+		return parseCSV(data)
+	case domain.ImportFormatJSONL:
+		return parseJSONL(data)
+	}
+	return nil, fmt.Errorf("format (%s) must be %s or %s: %w", format, domain.ImportFormatCSV, domain.ImportFormatJSONL, domain.ErrInvalidArgument)
+}
+
+func parseCSV(data []byte) ([]*domain.ImportRow, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("csv header: %w: %w", domain.ErrInvalidArgument, err)
+	}
+	seen := make(map[string]bool, len(header))
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		header[i] = column
+		if seen[column] {
+			return nil, fmt.Errorf("csv column (%s) is given twice: %w", column, domain.ErrInvalidArgument)
+		}
+		seen[column] = true
+		if !isCSVColumn(column) {
+			return nil, fmt.Errorf("unknown csv column (%s), columns are %v and %s<name>: %w", column, csvColumns, attributePrefix, domain.ErrInvalidArgument)
+		}
+	}
+	if !seen["price"] || !seen["currency"] {
+		return nil, fmt.Errorf("csv header needs the price and currency columns: %w", domain.ErrInvalidArgument)
+	}
+
+	var rows []*domain.ImportRow
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		// A row with the wrong number of fields leaves the rest of the file readable, while a syntax error
+		// such as an unterminated quote does not. Its error names the line, and there is no record to ask.
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, fmt.Errorf("csv: %w: %w", domain.ErrInvalidArgument, err)
+		}
+		line, _ := r.FieldPos(0)
+		if err != nil {
+			rows = append(rows, invalidRow(line, fmt.Errorf("row has %d fields instead of %d: %w", len(record), len(header), domain.ErrInvalidArgument)))
+			continue
+		}
+		item, err := csvItem(header, record)
+		if err != nil {
+			rows = append(rows, invalidRow(line, err))
+			continue
+		}
+		rows = append(rows, &domain.ImportRow{Line: line, State: domain.ImportRowStatePending, Item: item})
+	}
+}
+
+func isCSVColumn(column string) bool {
+	return slices.Contains(csvColumns, column) || strings.HasPrefix(column, attributePrefix) && len(column) > len(attributePrefix)
+}
+
+// csvItem reads a CSV record, in which empty fields are left unset
+func csvItem(header, record []string) (*domain.Item, error) {
+	item := &domain.Item{}
+	var amount, currency string
+	for i, value := range record {
+		value = strings.TrimSpace(value)
+		if len(value) == 0 {
+			continue
+		}
+		switch column := header[i]; column {
+		case "item_id":
+			item.ID = value
+		case "item_name":
+			item.Name = value
+		case "price":
+			amount = value
+		case "currency":
+			currency = value
+		case "nft_id":
+			item.NFTID = value
+		case "pool_name":
+			item.PoolName = value
+		case "category":
+			item.Category = value
+		case "condition":
+			item.Condition = domain.Condition(value)
+		case "images":
+			item.Images = strings.Fields(value)
+		default:
+			if item.Attributes == nil {
+				item.Attributes = make(map[string]string)
+			}
+			item.Attributes[strings.TrimPrefix(column, attributePrefix)] = value
+		}
+	}
+	if len(amount) == 0 || len(currency) == 0 {
+		return nil, fmt.Errorf("item has no price: %w", domain.ErrInvalidArgument)
+	}
+	price, err := domain.ParseMoney(amount, currency)
+	if err != nil {
+		return nil, err
+	}
+	item.Price = price
+	return item, nil
+}
+
+// parseJSONL reads one item per line, skipping blank lines
+func parseJSONL(data []byte) ([]*domain.ImportRow, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	var rows []*domain.ImportRow
+	for line := 1; scanner.Scan(); line++ {
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		var item domain.Item
+		if err := json.Unmarshal(b, &item); err != nil {
+			rows = append(rows, invalidRow(line, fmt.Errorf("json.Unmarshal item: %w: %w", domain.ErrInvalidArgument, err)))
+			continue
+		}
+		rows = append(rows, &domain.ImportRow{Line: line, State: domain.ImportRowStatePending, Item: &item})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("json lines: %w: %w", domain.ErrInvalidArgument, err)
+	}
+	return rows, nil
+}
+
+func invalidRow(line int, err error) *domain.ImportRow {
+	return &domain.ImportRow{Line: line, State: domain.ImportRowStateInvalid, Error: err.Error()}
+}
@@ -0,0 +1,206 @@
+package importer
+
+import (
+	"backend/internal/domain"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func price(t *testing.T, amount, currency string) domain.Money {
+	t.Helper()
+	m, err := domain.ParseMoney(amount, currency)
+	if err != nil {
+		t.Fatalf("ParseMoney(%q, %q): %v", amount, currency, err)
+	}
+	return m
+}
+
+// wantRow is a parsed row: an item for a pending row, or nil for an invalid one
+type wantRow struct {
+	line int
+	item *domain.Item
+}
+
+func checkRows(t *testing.T, got []*domain.ImportRow, want []wantRow) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("parsed %d rows, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		row := got[i]
+		if row.Line != w.line {
+			t.Errorf("row %d line = %d, want %d", i, row.Line, w.line)
+		}
+		if w.item == nil {
+			if row.State != domain.ImportRowStateInvalid || row.Item != nil || len(row.Error) == 0 {
+				t.Errorf("row %d = %s with item %+v and error %q, want invalid with an error", i, row.State, row.Item, row.Error)
+			}
+			continue
+		}
+		if row.State != domain.ImportRowStatePending || len(row.Error) > 0 {
+			t.Errorf("row %d = %s with error %q, want %s", i, row.State, row.Error, domain.ImportRowStatePending)
+			continue
+		}
+		// Items are compared by their JSON, as Money holds a big.Int
+		gotJSON, _ := json.Marshal(row.Item)
+		wantJSON, _ := json.Marshal(w.item)
+		if string(gotJSON) != string(wantJSON) {
+			t.Errorf("row %d item = %s, want %s", i, gotJSON, wantJSON)
+		}
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []wantRow
+	}{
+		{
+			name: "all columns",
+			data: "item_id,item_name,price,currency,nft_id,pool_name,category,condition,images\n" +
+				"item-1,Jacket,12000,USD,7,limited,clothing,used,\n",
+			want: []wantRow{{line: 2, item: &domain.Item{ID: "item-1", Name: "Jacket", Price: price(t, "12000", "USD"), NFTID: "7", PoolName: "limited", Category: "clothing", Condition: "used"}}},
+		},
+		{
+			name: "attribute columns",
+			data: "item_name,price,currency,attributes.brand,attributes.size\n" +
+				"Sneakers,5000,USD,Acme,42\n" +
+				"Boots,7000,USD,,43\n",
+			want: []wantRow{
+				{line: 2, item: &domain.Item{Name: "Sneakers", Price: price(t, "5000", "USD"), Attributes: map[string]string{"brand": "Acme", "size": "42"}}},
+				{line: 3, item: &domain.Item{Name: "Boots", Price: price(t, "7000", "USD"), Attributes: map[string]string{"size": "43"}}},
+			},
+		},
+		{
+			name: "space separated images",
+			data: "item_name,price,currency,images\n" +
+				"Lamp,900,KLD,\" aaa  bbb\tccc \"\n",
+			want: []wantRow{{line: 2, item: &domain.Item{Name: "Lamp", Price: price(t, "900", "KLD"), Images: []string{"aaa", "bbb", "ccc"}}}},
+		},
+		{
+			name: "header and fields with spaces",
+			data: " item_name , price , currency\n Chair , 100 , USD\n",
+			want: []wantRow{{line: 2, item: &domain.Item{Name: "Chair", Price: price(t, "100", "USD")}}},
+		},
+		{
+			name: "invalid rows are kept",
+			data: "item_name,price,currency\n" +
+				"Table,100,USD\n" +
+				"No price,,USD\n" +
+				"Decimal price,1.5,USD\n" +
+				"Negative price,-1,USD\n" +
+				"Lower case currency,100,usd\n" +
+				"Too,many,fields,here\n" +
+				"Too few\n" +
+				"Sofa,200,USD\n",
+			want: []wantRow{
+				{line: 2, item: &domain.Item{Name: "Table", Price: price(t, "100", "USD")}},
+				{line: 3},
+				{line: 4},
+				{line: 5},
+				{line: 6},
+				{line: 7},
+				{line: 8},
+				{line: 9, item: &domain.Item{Name: "Sofa", Price: price(t, "200", "USD")}},
+			},
+		},
+		{
+			name: "header only",
+			data: "item_name,price,currency\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parse(domain.ImportFormatCSV, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			checkRows(t, rows, tt.want)
+		})
+	}
+}
+
+func TestParseCSVRejectsFile(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "empty", data: ""},
+		{name: "unknown column", data: "item_name,price,currency,colour\nHat,100,USD,red\n"},
+		{name: "attribute without a name", data: "item_name,price,currency,attributes.\nHat,100,USD,red\n"},
+		{name: "column given twice", data: "item_name,price,currency,price\nHat,100,USD,200\n"},
+		{name: "no price column", data: "item_name,currency\nHat,USD\n"},
+		{name: "no currency column", data: "item_name,price\nHat,100\n"},
+		{name: "unterminated quote", data: "item_name,price,currency\n\"Hat,100,USD\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rows, err := parse(domain.ImportFormatCSV, []byte(tt.data)); !errors.Is(err, domain.ErrInvalidArgument) {
+				t.Errorf("parse = %d rows, %v, want %v", len(rows), err, domain.ErrInvalidArgument)
+			}
+		})
+	}
+}
+
+func TestParseJSONL(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []wantRow
+	}{
+		{
+			name: "all fields",
+			data: `{"item_id":"item-1","item_name":"Jacket","item_price":{"amount":"12000","currency":"USD"},"nft_id":"7","pool_name":"limited",` +
+				`"category":"clothing","condition":"used","attributes":{"brand":"Acme"},"images":["aaa","bbb"]}` + "\n",
+			want: []wantRow{{line: 1, item: &domain.Item{ID: "item-1", Name: "Jacket", Price: price(t, "12000", "USD"), NFTID: "7", PoolName: "limited",
+				Category: "clothing", Condition: "used", Attributes: map[string]string{"brand": "Acme"}, Images: []string{"aaa", "bbb"}}}},
+		},
+		{
+			name: "blank lines are skipped but counted",
+			data: `{"item_name":"Chair","item_price":{"amount":"100","currency":"USD"}}` + "\n\n  \n" +
+				`{"item_name":"Sofa","item_price":{"amount":"200","currency":"KLD"}}`,
+			want: []wantRow{
+				{line: 1, item: &domain.Item{Name: "Chair", Price: price(t, "100", "USD")}},
+				{line: 4, item: &domain.Item{Name: "Sofa", Price: price(t, "200", "KLD")}},
+			},
+		},
+		{
+			name: "invalid rows are kept",
+			data: `{"item_name":"Table","item_price":{"amount":"100","currency":"USD"}}` + "\n" +
+				`{"item_name":"Broken"` + "\n" +
+				`{"item_name":"Decimal price","item_price":{"amount":"1.5","currency":"USD"}}` + "\n" +
+				`{"item_name":"Number price","item_price":{"amount":100,"currency":"USD"}}` + "\n" +
+				`["not", "an", "item"]` + "\n" +
+				`{"item_name":"Sofa","item_price":{"amount":"200","currency":"USD"}}` + "\n",
+			want: []wantRow{
+				{line: 1, item: &domain.Item{Name: "Table", Price: price(t, "100", "USD")}},
+				{line: 2},
+				{line: 3},
+				{line: 4},
+				{line: 5},
+				{line: 6, item: &domain.Item{Name: "Sofa", Price: price(t, "200", "USD")}},
+			},
+		},
+		{
+			name: "empty",
+			data: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parse(domain.ImportFormatJSONL, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			checkRows(t, rows, tt.want)
+		})
+	}
+}
+
+func TestParseRejectsFormat(t *testing.T) {
+	if _, err := parse(domain.ImportFormat("xml"), []byte("<items/>")); !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Errorf("parse of xml = %v, want %v", err, domain.ErrInvalidArgument)
+	}
+}
@@ -2,35 +2,85 @@ package item
 
 import (
 	"backend/internal/domain"
+	"backend/internal/utils"
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"slices"
 	"time"
 )
 
-type fireflyClient interface {
-	DeploySmartContract(ctx context.Context, item *domain.Item) (string, error)
-	ApproveTokenTransfer(ctx context.Context, item *domain.Item) error
-	BuyNFT(ctx context.Context, contractAddress string) error
-	MintToken(ctx context.Context) (string, error)
-	GetSmartContractLocation(ctx context.Context, trxID string) (string, error)
+// marketplace hides whether listings live in one contract per listing or in a single shared contract
+type marketplace interface {
+	List(ctx context.Context, item *domain.Item) (*domain.ContractDeployment, error)
+	// SettlesOnBuy reports whether a purchase already hands the NFT over, rather than receipt
+	SettlesOnBuy() bool
+	Buy(ctx context.Context, item *domain.Item) error
+	Delist(ctx context.Context, item *domain.Item) error
+	SetPrice(ctx context.Context, item *domain.Item, price domain.Money) error
+	Ship(ctx context.Context, item *domain.Item) error
+	Receive(ctx context.Context, item *domain.Item) error
+	Cancel(ctx context.Context, item *domain.Item) error
+	Dispute(ctx context.Context, item *domain.Item) error
+	Resolve(ctx context.Context, item *domain.Item, refund bool) error
+	// DisputeWindow is how long after receipt the buyer can still dispute, zero when they cannot
+	DisputeWindow() time.Duration
+	ReleasePayment(ctx context.Context, item *domain.Item) error
+	AcceptOffer(ctx context.Context, item *domain.Item, buyerID string, price domain.Money) error
+}
+
+// tokenPools resolves the pool of an item's NFT, an empty name being the default pool
+type tokenPools interface {
+	Pool(name string) (*domain.TokenPool, error)
 }
 
 type dbClient interface {
 	GetItemByID(ctx context.Context, id string) (*domain.Item, error)
 	CreateItem(ctx context.Context, item *domain.Item) error
-	UpdateItem(ctx context.Context, item *domain.Item) error
+	UpdateItem(ctx context.Context, item *domain.Item, from domain.ItemState) error
 	CreateOrUpdateItem(ctx context.Context, item *domain.Item) error
+	CreateContractDeployment(ctx context.Context, d *domain.ContractDeployment) error
+	GetContractDeploymentByAddress(ctx context.Context, address string) (*domain.ContractDeployment, error)
+	CreateSale(ctx context.Context, sale *domain.Sale) error
+	CompleteSale(ctx context.Context, itemID, buyerID string) error
+	RefundSale(ctx context.Context, itemID, buyerID string) error
+	ReleaseSale(ctx context.Context, itemID, buyerID string) error
+	ListSalesByItemID(ctx context.Context, itemID string) ([]*domain.Sale, error)
+	CreatePriceChange(ctx context.Context, pc *domain.PriceChange) error
+	ListPriceChanges(ctx context.Context, itemID string) ([]*domain.PriceChange, error)
+	GetMediaByHash(ctx context.Context, hash string) (*domain.Media, error)
+	RecordNFTCreator(ctx context.Context, poolName, nftID, creatorID string) (string, error)
+}
+
+type eventPublisher interface {
+	Publish(ctx context.Context, eventType domain.EventType, data any) error
+}
+
+type feeEngine interface {
+	Split(item *domain.Item, buyerID string) (*domain.Sale, error)
 }
 
 type Service struct {
-	fireflyClient fireflyClient
-	dbClient      dbClient
+	marketplace    marketplace
+	pools          tokenPools
+	dbClient       dbClient
+	eventPublisher eventPublisher
+	feeEngine      feeEngine
+	// priceChangeInterval is the minimum time between two price changes of a listing
+	priceChangeInterval time.Duration
+	now                 func() time.Time
 }
 
-func New(fireflyClient fireflyClient, dbClient dbClient) *Service {
+func New(marketplace marketplace, pools tokenPools, dbClient dbClient, eventPublisher eventPublisher, feeEngine feeEngine, priceChangeInterval time.Duration) *Service {
 	return &Service{
-		fireflyClient: fireflyClient,
-		dbClient:      dbClient,
+		marketplace:         marketplace,
+		pools:               pools,
+		dbClient:            dbClient,
+		eventPublisher:      eventPublisher,
+		feeEngine:           feeEngine,
+		priceChangeInterval: priceChangeInterval,
+		now:                 time.Now,
 	}
 }
 
@@ -42,50 +92,412 @@ func (s *Service) GetItem(ctx context.Context, id string) (*domain.Item, error)
 	return resp, nil
 }
 
+// GetItemContract returns the registered contract version backing the item's listing
+func (s *Service) GetItemContract(ctx context.Context, id string) (*domain.ContractDeployment, error) {
+	resp, err := s.dbClient.GetItemByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("GetItemContract: s.dbClient.GetItemByID: %w", err)
+	}
+	if len(resp.SmartContractAddress) == 0 {
+		return nil, fmt.Errorf("GetItemContract: item (%s) has no contract: %w", id, domain.ErrNotFound)
+	}
+	d, err := s.dbClient.GetContractDeploymentByAddress(ctx, resp.SmartContractAddress)
+	if err != nil {
+		return nil, fmt.Errorf("GetItemContract: s.dbClient.GetContractDeploymentByAddress: %w", err)
+	}
+	return d, nil
+}
+
 // ListItem can be used for listing a new item or/and re-listing an existing item
 func (s *Service) ListItem(ctx context.Context, item *domain.Item) error {
+	if len(item.Price.Currency()) == 0 {
+		return fmt.Errorf("ListItem: item has no price: %w", domain.ErrInvalidArgument)
+	}
+	if err := item.ValidateDescription(); err != nil {
+		return fmt.Errorf("ListItem: %w", err)
+	}
+	if err := s.validateImages(ctx, item); err != nil {
+		return fmt.Errorf("ListItem: %w", err)
+	}
+	uid := utils.FromContext(ctx)
+	current := &domain.Item{ID: item.ID}
+	if existing, err := s.dbClient.GetItemByID(ctx, item.ID); err == nil {
+		current = existing
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("ListItem: s.dbClient.GetItemByID: %w", err)
+	}
+	t, err := current.Transition(domain.ItemActionList, uid)
+	if err != nil {
+		return fmt.Errorf("ListItem: %w", err)
+	}
+	if current.State == domain.ItemStateReceived {
+		if closes, open, err := s.disputeWindow(ctx, current); err != nil {
+			return fmt.Errorf("ListItem: %w", err)
+		} else if open {
+			return fmt.Errorf("ListItem: item (%s) can be listed again once its dispute window closes at %s: %w", item.ID, closes.Format(time.RFC3339), domain.ErrConflict)
+		}
+	}
+	// The NFT vouches for the name and images it was minted with
+	if len(current.NFTID) > 0 && current.NFTID == item.NFTID && current.PoolName == item.PoolName &&
+		(current.Name != item.Name || !slices.Equal(current.Images, item.Images)) {
+		return fmt.Errorf("ListItem: the name and images of item (%s) cannot change once its NFT is minted: %w", item.ID, domain.ErrConflict)
+	}
+
+	// The state and the creator cannot be set by the request
+	item.State = current.State
+	item.SellerID = uid
+	item.BuyerID = ""
+	if item.CreatorID, err = s.creator(ctx, item, uid); err != nil {
+		return fmt.Errorf("ListItem: %w", err)
+	}
 	if err := s.dbClient.CreateOrUpdateItem(ctx, item); err != nil {
 		return fmt.Errorf("ListItem: s.dbClient.CreateOrUpdateItem: %w", err)
 	}
 
-	trxID, err := s.fireflyClient.DeploySmartContract(ctx, item)
+	deployment, err := s.marketplace.List(ctx, item)
 	if err != nil {
-		return fmt.Errorf("ListItem: s.fireflyClient.DeploySmartContract: %w", err)
+		return fmt.Errorf("ListItem: s.marketplace.List: %w", err)
 	}
-
-	// TODO: Use event listener
-	time.Sleep(time.Second * 5)
-	clocation, err := s.fireflyClient.GetSmartContractLocation(ctx, trxID)
-	if err != nil {
-		return fmt.Errorf("ListItem: s.fireflyClient.GetSmartContractLocation: %w", err)
+	if deployment != nil {
+		if err := s.dbClient.CreateContractDeployment(ctx, deployment); err != nil {
+			return fmt.Errorf("ListItem: s.dbClient.CreateContractDeployment: %w", err)
+		}
 	}
 
-	item.SmartContractAddress = clocation
-	if err := s.dbClient.UpdateItem(ctx, item); err != nil {
+	if err := t.Apply(item); err != nil {
+		return fmt.Errorf("ListItem: %w", err)
+	}
+	if err := s.dbClient.UpdateItem(ctx, item, t.From); err != nil {
 		return fmt.Errorf("ListItem: s.dbClient.UpdateItem: %w", err)
 	}
+	s.publish(ctx, t.Event, item)
+	return nil
+}
 
-	if err := s.fireflyClient.ApproveTokenTransfer(ctx, item); err != nil {
-		return fmt.Errorf("s.fireflyClient.ApproveTokenTransfer: %w", err)
+// creator returns the creator of the item's NFT, who minted or first listed it. Listing the same NFT under
+// another item keeps its creator, so that a buyer cannot relist it to avoid the royalty.
+func (s *Service) creator(ctx context.Context, item *domain.Item, seller string) (string, error) {
+	if len(item.NFTID) == 0 {
+		return seller, nil
+	}
+	pool, err := s.pools.Pool(item.PoolName)
+	if err != nil {
+		return "", fmt.Errorf("s.pools.Pool: %w", err)
+	}
+	creator, err := s.dbClient.RecordNFTCreator(ctx, pool.Name, item.NFTID, seller)
+	if err != nil {
+		return "", fmt.Errorf("s.dbClient.RecordNFTCreator: %w", err)
+	}
+	return creator, nil
+}
+
+// validateImages checks that every image of the item was uploaded, so that its NFT only vouches for stored images
+func (s *Service) validateImages(ctx context.Context, item *domain.Item) error {
+	if err := item.ValidateImages(); err != nil {
+		return err
+	}
+	for _, hash := range item.Images {
+		if _, err := s.dbClient.GetMediaByHash(ctx, hash); errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("image (%s) was not uploaded: %w", hash, domain.ErrInvalidArgument)
+		} else if err != nil {
+			return fmt.Errorf("s.dbClient.GetMediaByHash: %w", err)
+		}
 	}
 	return nil
 }
 
-func (s *Service) PurchaseItem(ctx context.Context, item *domain.Item) error {
+// PurchaseItem buys a listed item, and returns how its price is split between the platform, the creator and the seller
+func (s *Service) PurchaseItem(ctx context.Context, item *domain.Item) (*domain.Sale, error) {
 	resp, err := s.dbClient.GetItemByID(ctx, item.ID)
 	if err != nil {
-		return fmt.Errorf("s.dbClient.GetItemByID: %w", err)
+		return nil, fmt.Errorf("s.dbClient.GetItemByID: %w", err)
+	}
+	return s.purchase(ctx, resp, s.marketplace.Buy)
+}
+
+// PurchaseOffer buys a listed item for buyerID at the amount of an offer the seller agreed to. The seller sells it
+// on chain to that buyer alone, out of the offer the buyer holds in escrow, so the listed price never changes.
+func (s *Service) PurchaseOffer(ctx context.Context, id, buyerID string, price domain.Money) (*domain.Sale, error) {
+	resp, err := s.dbClient.GetItemByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("PurchaseOffer: s.dbClient.GetItemByID: %w", err)
+	}
+	if price.Currency() != resp.Price.Currency() {
+		return nil, fmt.Errorf("PurchaseOffer: item (%s) is priced in %s, not %s: %w", id, resp.Price.Currency(), price.Currency(), domain.ErrInvalidArgument)
+	}
+	accept := func(ctx context.Context, item *domain.Item) error {
+		if err := s.marketplace.AcceptOffer(utils.NewContext(ctx, item.SellerID), item, buyerID, price); err != nil {
+			return err
+		}
+		// The sale is recorded at the agreed price
+		item.Price = price
+		return nil
+	}
+	sale, err := s.purchase(utils.NewContext(ctx, buyerID), resp, accept)
+	if err != nil {
+		return nil, fmt.Errorf("PurchaseOffer: %w", err)
+	}
+	return sale, nil
+}
+
+// purchase buys resp for the user in ctx with buy, which does it on chain
+func (s *Service) purchase(ctx context.Context, resp *domain.Item, buy func(context.Context, *domain.Item) error) (*domain.Sale, error) {
+	t, err := resp.Transition(domain.ItemActionPurchase, utils.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err := buy(ctx, resp); err != nil {
+		return nil, fmt.Errorf("%s on chain: %w", t.Action, err)
+	}
+	sale, err := s.feeEngine.Split(resp, utils.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("s.feeEngine.Split: %w", err)
+	}
+	if err := t.Apply(resp); err != nil {
+		return nil, err
+	}
+	resp.BuyerID = sale.BuyerID
+	if err := s.dbClient.UpdateItem(ctx, resp, t.From); err != nil {
+		return nil, fmt.Errorf("s.dbClient.UpdateItem: %w", err)
+	}
+	if err := s.dbClient.CreateSale(ctx, sale); err != nil {
+		return nil, fmt.Errorf("s.dbClient.CreateSale: %w", err)
 	}
-	if resp.State == domain.ItemStateSold {
-		return fmt.Errorf("item cannot be called when state is ItemStateSold")
+	// Nothing is left to settle when the NFT changed hands with the purchase, so the buyer can review the seller
+	if s.marketplace.SettlesOnBuy() {
+		if err := s.dbClient.CompleteSale(ctx, resp.ID, resp.BuyerID); err != nil {
+			return nil, fmt.Errorf("s.dbClient.CompleteSale: %w", err)
+		}
+		now := s.now().UTC()
+		sale.CompletedAt = &now
 	}
+	s.publish(ctx, t.Event, resp)
+	return sale, nil
+}
 
-	if err := s.fireflyClient.BuyNFT(ctx, resp.SmartContractAddress); err != nil {
-		return fmt.Errorf("s.fireflyClient.TransferToken: %w", err)
+// ShipItem is called by the seller once a sold item has been handed to a carrier
+func (s *Service) ShipItem(ctx context.Context, id string) error {
+	if _, err := s.transition(ctx, id, domain.ItemActionShip, s.marketplace.Ship); err != nil {
+		return fmt.Errorf("ShipItem: %w", err)
 	}
-	resp.State = domain.ItemStateSold
-	if err := s.dbClient.UpdateItem(ctx, resp); err != nil {
-		return fmt.Errorf("s.dbClient.UpdateItem: %w", err)
+	return nil
+}
+
+// ReceiveItem is called by the buyer on delivery, which hands the NFT over to them
+func (s *Service) ReceiveItem(ctx context.Context, id string) error {
+	if _, err := s.transition(ctx, id, domain.ItemActionReceive, s.marketplace.Receive); err != nil {
+		return fmt.Errorf("ReceiveItem: %w", err)
+	}
+	return nil
+}
+
+// CancelItem withdraws a listing, or a sale that has not been shipped yet.
+// Either the seller or the buyer may cancel.
+func (s *Service) CancelItem(ctx context.Context, id string) error {
+	if _, err := s.transition(ctx, id, domain.ItemActionCancel, s.marketplace.Cancel); err != nil {
+		return fmt.Errorf("CancelItem: %w", err)
+	}
+	return nil
+}
+
+// DelistItem takes a listing down before it is sold. The listing is cancelled on chain and the contract
+// loses its approval over the NFT, so it can no longer be bought there either.
+func (s *Service) DelistItem(ctx context.Context, id string) error {
+	if _, err := s.transition(ctx, id, domain.ItemActionDelist, s.marketplace.Delist); err != nil {
+		return fmt.Errorf("DelistItem: %w", err)
 	}
 	return nil
 }
+
+// DisputeItem freezes a sale the buyer has not received yet, or received within the dispute window.
+// The payment stays in escrow until ResolveDispute, and the NFT with the seller or, once received, the contract.
+func (s *Service) DisputeItem(ctx context.Context, id string) (*domain.Item, error) {
+	dispute := func(ctx context.Context, item *domain.Item) error {
+		if item.State == domain.ItemStateReceived {
+			closes, open, err := s.disputeWindow(ctx, item)
+			if err != nil {
+				return err
+			}
+			if !open {
+				return fmt.Errorf("the dispute window of item (%s) closed at %s: %w", item.ID, closes.Format(time.RFC3339), domain.ErrConflict)
+			}
+		}
+		return s.marketplace.Dispute(ctx, item)
+	}
+	resp, err := s.transition(ctx, id, domain.ItemActionDispute, dispute)
+	if err != nil {
+		return nil, fmt.Errorf("DisputeItem: %w", err)
+	}
+	return resp, nil
+}
+
+// disputeWindow returns when the buyer of a received item can no longer dispute it, and whether they still can.
+// The window starts when the sale was completed, and is closed once the seller has been paid.
+func (s *Service) disputeWindow(ctx context.Context, item *domain.Item) (time.Time, bool, error) {
+	sales, err := s.dbClient.ListSalesByItemID(ctx, item.ID)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("s.dbClient.ListSalesByItemID: %w", err)
+	}
+	for i := len(sales) - 1; i >= 0; i-- {
+		sale := sales[i]
+		if sale.BuyerID != item.BuyerID || sale.CompletedAt == nil {
+			continue
+		}
+		closes := sale.CompletedAt.Add(s.marketplace.DisputeWindow())
+		return closes, sale.ReleasedAt == nil && sale.RefundedAt == nil && s.now().Before(closes), nil
+	}
+	return time.Time{}, false, nil
+}
+
+// SettleSale pays the seller of a completed sale out of escrow once the buyer can no longer dispute it.
+// A sale under dispute is left for the arbiter to settle.
+func (s *Service) SettleSale(ctx context.Context, sale *domain.Sale) error {
+	item, err := s.dbClient.GetItemByID(ctx, sale.ItemID)
+	if err != nil {
+		return fmt.Errorf("SettleSale: s.dbClient.GetItemByID: %w", err)
+	}
+	if item.State == domain.ItemStateDisputed && item.BuyerID == sale.BuyerID {
+		return nil
+	}
+	if err := s.marketplace.ReleasePayment(ctx, item); err != nil {
+		return fmt.Errorf("SettleSale: s.marketplace.ReleasePayment: %w", err)
+	}
+	if err := s.dbClient.ReleaseSale(ctx, sale.ItemID, sale.BuyerID); err != nil {
+		return fmt.Errorf("SettleSale: s.dbClient.ReleaseSale: %w", err)
+	}
+	return nil
+}
+
+// ResolveDispute ends a dispute on the arbiter's behalf. A refund returns the price to the buyer and leaves
+// the NFT with the seller, otherwise the sale is finalized as if the buyer had received the item.
+// The caller must be an admin, which the dispute service checks.
+func (s *Service) ResolveDispute(ctx context.Context, id string, refund bool) error {
+	action := domain.ItemActionRelease
+	if refund {
+		action = domain.ItemActionRefund
+	}
+	resolve := func(ctx context.Context, item *domain.Item) error {
+		return s.marketplace.Resolve(ctx, item, refund)
+	}
+	if _, err := s.transition(ctx, id, action, resolve); err != nil {
+		return fmt.Errorf("ResolveDispute: %w", err)
+	}
+	return nil
+}
+
+// transition takes action on the item for the user in ctx. The contract call runs once the state machine
+// has allowed the action, and the new state is only stored once the call has succeeded.
+func (s *Service) transition(ctx context.Context, id string, action domain.ItemAction, call func(context.Context, *domain.Item) error) (*domain.Item, error) {
+	resp, err := s.dbClient.GetItemByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("s.dbClient.GetItemByID: %w", err)
+	}
+	t, err := resp.Transition(action, utils.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := call(ctx, resp); err != nil {
+		return nil, fmt.Errorf("%s on chain: %w", action, err)
+	}
+	if err := t.Apply(resp); err != nil {
+		return nil, err
+	}
+	// A cancelled or refunded sale no longer has a buyer, who got the price back
+	buyerID := resp.BuyerID
+	refunded := t.To == domain.ItemStateCancelled || t.To == domain.ItemStateRefunded
+	if refunded {
+		resp.BuyerID = ""
+	}
+	if err := s.dbClient.UpdateItem(ctx, resp, t.From); err != nil {
+		return nil, fmt.Errorf("s.dbClient.UpdateItem: %w", err)
+	}
+	if refunded && len(buyerID) > 0 {
+		if err := s.dbClient.RefundSale(ctx, resp.ID, buyerID); err != nil {
+			return nil, fmt.Errorf("s.dbClient.RefundSale: %w", err)
+		}
+	}
+	// Receipt settles the sale, which lets the buyer review the seller
+	if t.To == domain.ItemStateReceived {
+		if err := s.dbClient.CompleteSale(ctx, resp.ID, resp.BuyerID); err != nil {
+			return nil, fmt.Errorf("s.dbClient.CompleteSale: %w", err)
+		}
+	}
+	// The arbiter releasing a dispute pays the seller, and the sale cannot be disputed again
+	if action == domain.ItemActionRelease {
+		if err := s.dbClient.ReleaseSale(ctx, resp.ID, resp.BuyerID); err != nil {
+			return nil, fmt.Errorf("s.dbClient.ReleaseSale: %w", err)
+		}
+	}
+	s.publish(ctx, t.Event, resp)
+	return resp, nil
+}
+
+// UpdatePrice changes the price of an active listing on chain and records the change in the price history.
+// Changes are rate limited so that a seller cannot switch the price just before a purchase.
+func (s *Service) UpdatePrice(ctx context.Context, id string, price domain.Money) (*domain.PriceChange, error) {
+	resp, err := s.dbClient.GetItemByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("UpdatePrice: s.dbClient.GetItemByID: %w", err)
+	}
+	uid := utils.FromContext(ctx)
+	t, err := resp.Transition(domain.ItemActionReprice, uid)
+	if err != nil {
+		return nil, fmt.Errorf("UpdatePrice: %w", err)
+	}
+	if price.Currency() != resp.Price.Currency() {
+		return nil, fmt.Errorf("UpdatePrice: item (%s) is priced in %s, not %s: %w", id, resp.Price.Currency(), price.Currency(), domain.ErrInvalidArgument)
+	}
+
+	history, err := s.dbClient.ListPriceChanges(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("UpdatePrice: s.dbClient.ListPriceChanges: %w", err)
+	}
+	if len(history) > 0 {
+		if wait := history[0].CreatedAt.Add(s.priceChangeInterval).Sub(s.now()); wait > 0 {
+			return nil, fmt.Errorf("UpdatePrice: price of item (%s) can change again in %s: %w", id, wait.Round(time.Second), domain.ErrRateLimited)
+		}
+	}
+
+	if err := s.marketplace.SetPrice(ctx, resp, price); err != nil {
+		return nil, fmt.Errorf("UpdatePrice: s.marketplace.SetPrice: %w", err)
+	}
+	change := &domain.PriceChange{
+		ItemID:    id,
+		OldPrice:  resp.Price,
+		NewPrice:  price,
+		ChangedBy: uid,
+	}
+	resp.Price = price
+	if err := t.Apply(resp); err != nil {
+		return nil, fmt.Errorf("UpdatePrice: %w", err)
+	}
+	if err := s.dbClient.UpdateItem(ctx, resp, t.From); err != nil {
+		return nil, fmt.Errorf("UpdatePrice: s.dbClient.UpdateItem: %w", err)
+	}
+	if err := s.dbClient.CreatePriceChange(ctx, change); err != nil {
+		return nil, fmt.Errorf("UpdatePrice: s.dbClient.CreatePriceChange: %w", err)
+	}
+	s.publish(ctx, t.Event, resp)
+	return change, nil
+}
+
+// ListPriceChanges returns the price history of an item, newest first
+func (s *Service) ListPriceChanges(ctx context.Context, id string) ([]*domain.PriceChange, error) {
+	if _, err := s.dbClient.GetItemByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("ListPriceChanges: s.dbClient.GetItemByID: %w", err)
+	}
+	changes, err := s.dbClient.ListPriceChanges(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("ListPriceChanges: s.dbClient.ListPriceChanges: %w", err)
+	}
+	return changes, nil
+}
+
+// publish notifies subscribers of a state change. The state change has already
+// been committed at this point, so a failure is logged rather than returned.
+func (s *Service) publish(ctx context.Context, eventType domain.EventType, item *domain.Item) {
+	if err := s.eventPublisher.Publish(ctx, eventType, item); err != nil {
+		log.Printf("Failed to publish %s event for item (%s): %s", eventType, item.ID, err.Error())
+	}
+}
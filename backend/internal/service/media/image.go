@@ -0,0 +1,219 @@
+package media
+
+import (
+	"backend/internal/domain"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	// maxPixels keeps a small file that decodes to a huge image from taking the server's memory
+	maxPixels = 40_000_000
+	// thumbnailSize is the longest side of a thumbnail
+	thumbnailSize = 320
+	jpegQuality   = 90
+)
+
+// formats are the content types images can be uploaded as, by the type sniffed from their bytes
+var formats = map[string]func(*bytes.Buffer, image.Image) error{
+	"image/jpeg": func(w *bytes.Buffer, img image.Image) error {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	},
+	"image/png": func(w *bytes.Buffer, img image.Image) error {
+		return png.Encode(w, img)
+	},
+}
+
+// processed is an upload after its metadata was stripped, with its thumbnail
+type processed struct {
+	contentType   string
+	data          []byte
+	width, height int
+	thumbnail     []byte
+}
+
+// process decodes an upload and encodes it again. The encoders write pixels only, so everything else the
+// upload carried, such as EXIF with the GPS position of the camera, is dropped. The EXIF orientation is applied
+// to the pixels first, so that photos still show the right way up.
+func process(data []byte) (*processed, error) {
+	contentType := http.DetectContentType(data)
+	encode, ok := formats[contentType]
+	if !ok {
+		return nil, fmt.Errorf("images must be JPEG or PNG, got %s: %w", contentType, domain.ErrInvalidArgument)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("image is corrupt: %w", domain.ErrInvalidArgument)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("image of %dx%d must have at most %d pixels: %w", cfg.Width, cfg.Height, maxPixels, domain.ErrInvalidArgument)
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("image is corrupt: %w", domain.ErrInvalidArgument)
+	}
+
+	img := toRGBA(decoded)
+	if contentType == "image/jpeg" {
+		img = orient(img, exifOrientation(data))
+	}
+	p := &processed{
+		contentType: contentType,
+		width:       img.Rect.Dx(),
+		height:      img.Rect.Dy(),
+	}
+	var buf bytes.Buffer
+	if err := encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode %s: %w", contentType, err)
+	}
+	p.data = bytes.Clone(buf.Bytes())
+	buf.Reset()
+	if err := encode(&buf, thumbnail(img, thumbnailSize)); err != nil {
+		return nil, fmt.Errorf("encode %s thumbnail: %w", contentType, err)
+	}
+	p.thumbnail = buf.Bytes()
+	return p, nil
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Rect, img, b.Min, draw.Src)
+	return dst
+}
+
+// thumbnail scales img down to fit in a size by size square, averaging the pixels each thumbnail pixel covers.
+// Images that already fit are kept at their size.
+func thumbnail(img *image.RGBA, size int) *image.RGBA {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for ty := 0; ty < th; ty++ {
+		y0, y1 := ty*h/th, max((ty+1)*h/th, ty*h/th+1)
+		for tx := 0; tx < tw; tx++ {
+			x0, x1 := tx*w/tw, max((tx+1)*w/tw, tx*w/tw+1)
+			var sum [4]int
+			for y := y0; y < y1; y++ {
+				row := img.Pix[y*img.Stride:]
+				for x := x0; x < x1; x++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += int(row[x*4+c])
+					}
+				}
+			}
+			n := (x1 - x0) * (y1 - y0)
+			i := dst.PixOffset(tx, ty)
+			for c := 0; c < 4; c++ {
+				dst.Pix[i+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return dst
+}
+
+// orient turns img the way the EXIF orientation tag says the camera was held
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	// Orientations 5 to 8 swap width and height
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], img.Pix[img.PixOffset(x, y):][:4])
+		}
+	}
+	return dst
+}
+
+// exifOrientation reads the orientation tag from a JPEG's EXIF segment, and returns 1, upright, when there is none
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+		marker := data[i+1]
+		// Image data starts at the start of scan, after all metadata segments
+		if marker == 0xDA {
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			break
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+const orientationTag = 0x0112
+
+// tiffOrientation finds the orientation tag in the first IFD of an EXIF TIFF structure
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for e := 0; e < entries; e++ {
+		entry := ifd + 2 + e*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == orientationTag {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 1
+}
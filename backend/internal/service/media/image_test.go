@@ -0,0 +1,159 @@
+package media
+
+import (
+	"backend/internal/domain"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+type memoryDB struct {
+	media map[string]*domain.Media
+}
+
+func (d *memoryDB) CreateMedia(_ context.Context, m *domain.Media) error {
+	d.media[m.Hash] = m
+	return nil
+}
+
+func (d *memoryDB) GetMediaByHash(_ context.Context, hash string) (*domain.Media, error) {
+	if m, ok := d.media[hash]; ok {
+		return m, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (d *memoryDB) GetMediaByThumbnailHash(_ context.Context, hash string) (*domain.Media, error) {
+	for _, m := range d.media {
+		if m.ThumbnailHash == hash {
+			return m, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+type memoryStorage map[string][]byte
+
+func (s memoryStorage) Put(_ context.Context, key string, data []byte) error {
+	s[key] = data
+	return nil
+}
+
+func (s memoryStorage) Get(_ context.Context, key string) ([]byte, error) {
+	if data, ok := s[key]; ok {
+		return data, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+// gpsLatitude is the latitude the test photo was taken at, 52° 31' 12.34", as EXIF rationals
+var gpsLatitude = []uint32{52, 1, 31, 1, 1234, 100}
+
+// exifSegment is an APP1 segment with the given orientation in IFD0 and a GPS IFD with a northern latitude
+func exifSegment(orientation uint16) []byte {
+	order := binary.BigEndian
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	entry := func(tag, typ uint16, count, value uint32) {
+		tiff = order.AppendUint16(tiff, tag)
+		tiff = order.AppendUint16(tiff, typ)
+		tiff = order.AppendUint32(tiff, count)
+		tiff = order.AppendUint32(tiff, value)
+	}
+	const gpsIFD = 8 + 2 + 2*12 + 4
+	const latitude = gpsIFD + 2 + 2*12 + 4
+	// IFD0: orientation and the pointer to the GPS IFD
+	tiff = order.AppendUint16(tiff, 2)
+	entry(orientationTag, 3, 1, uint32(orientation)<<16)
+	entry(0x8825, 4, 1, gpsIFD)
+	tiff = order.AppendUint32(tiff, 0)
+	// GPS IFD: GPSLatitudeRef and GPSLatitude
+	tiff = order.AppendUint16(tiff, 2)
+	entry(0x0001, 2, 2, uint32('N')<<24)
+	entry(0x0002, 5, 3, latitude)
+	tiff = order.AppendUint32(tiff, 0)
+	for _, v := range gpsLatitude {
+		tiff = order.AppendUint32(tiff, v)
+	}
+
+	segment := []byte{0xFF, 0xE1}
+	segment = order.AppendUint16(segment, uint16(2+6+len(tiff)))
+	segment = append(segment, "Exif\x00\x00"...)
+	return append(segment, tiff...)
+}
+
+// photo is a JPEG of width by height pixels carrying the EXIF segment right after its start of image
+func photo(t *testing.T, width, height int, exif []byte) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 16), G: uint8(y * 16), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("jpeg.Encode: %v", err)
+	}
+	data := buf.Bytes()
+	return append(append(bytes.Clone(data[:2]), exif...), data[2:]...)
+}
+
+// metadataMarkers returns the APPn and comment markers of a JPEG's segments before its image data
+func metadataMarkers(data []byte) []byte {
+	var markers []byte
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+		marker := data[i+1]
+		if marker == 0xDA {
+			break
+		}
+		if (marker >= 0xE0 && marker <= 0xEF) || marker == 0xFE {
+			markers = append(markers, marker)
+		}
+		i += 2 + int(binary.BigEndian.Uint16(data[i+2:]))
+	}
+	return markers
+}
+
+func TestUploadStripsGPS(t *testing.T) {
+	upload := photo(t, 8, 4, exifSegment(6))
+	if got := exifOrientation(upload); got != 6 {
+		t.Fatalf("test photo orientation = %d, want 6", got)
+	}
+	var latitude []byte
+	for _, v := range gpsLatitude {
+		latitude = binary.BigEndian.AppendUint32(latitude, v)
+	}
+	if !bytes.Contains(upload, latitude) {
+		t.Fatal("test photo does not carry its GPS latitude")
+	}
+
+	storage := memoryStorage{}
+	s := New(&memoryDB{media: map[string]*domain.Media{}}, storage, 1<<20)
+	m, err := s.Upload(context.Background(), upload)
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	// Orientation 6 is a photo taken with the camera turned, so it is stored turned upright
+	if m.Width != 4 || m.Height != 8 {
+		t.Errorf("stored size = %dx%d, want 4x8", m.Width, m.Height)
+	}
+	for name, key := range map[string]string{"image": m.Hash, "thumbnail": m.ThumbnailHash} {
+		data, ok := storage[key]
+		if !ok {
+			t.Fatalf("%s (%s) was not stored", name, key)
+		}
+		if markers := metadataMarkers(data); len(markers) > 0 {
+			t.Errorf("stored %s has metadata segments % X", name, markers)
+		}
+		if bytes.Contains(data, []byte("Exif")) || bytes.Contains(data, latitude) {
+			t.Errorf("stored %s still carries the EXIF GPS position", name)
+		}
+		if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+			t.Errorf("stored %s does not decode: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package media
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+type dbClient interface {
+	CreateMedia(ctx context.Context, m *domain.Media) error
+	GetMediaByHash(ctx context.Context, hash string) (*domain.Media, error)
+	GetMediaByThumbnailHash(ctx context.Context, hash string) (*domain.Media, error)
+}
+
+// Storage keeps blobs under a key, such as files in a local directory or objects in a bucket
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+type Service struct {
+	dbClient dbClient
+	storage  Storage
+	// maxSize is the most bytes an upload can have
+	maxSize int64
+}
+
+func New(dbClient dbClient, storage Storage, maxSize int64) *Service {
+	return &Service{
+		dbClient: dbClient,
+		storage:  storage,
+		maxSize:  maxSize,
+	}
+}
+
+// MaxSize is the most bytes an upload can have, which callers can stop reading at
+func (s *Service) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Upload strips the image's metadata, makes its thumbnail and stores both under their SHA-256.
+// Uploading the same image again returns the media stored the first time.
+func (s *Service) Upload(ctx context.Context, data []byte) (*domain.Media, error) {
+	if len(data) == 0 || int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("Upload: image must be between 1 and %d bytes: %w", s.maxSize, domain.ErrInvalidArgument)
+	}
+	p, err := process(data)
+	if err != nil {
+		return nil, fmt.Errorf("Upload: %w", err)
+	}
+	m := &domain.Media{
+		Hash:          hash(p.data),
+		ContentType:   p.contentType,
+		Size:          int64(len(p.data)),
+		Width:         p.width,
+		Height:        p.height,
+		ThumbnailHash: hash(p.thumbnail),
+		UploaderID:    utils.FromContext(ctx),
+	}
+	if existing, err := s.dbClient.GetMediaByHash(ctx, m.Hash); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("Upload: s.dbClient.GetMediaByHash: %w", err)
+	}
+
+	// Blobs are stored before the record, so that a recorded hash can always be served
+	if err := s.storage.Put(ctx, m.Hash, p.data); err != nil {
+		return nil, fmt.Errorf("Upload: s.storage.Put image: %w", err)
+	}
+	if err := s.storage.Put(ctx, m.ThumbnailHash, p.thumbnail); err != nil {
+		return nil, fmt.Errorf("Upload: s.storage.Put thumbnail: %w", err)
+	}
+	if err := s.dbClient.CreateMedia(ctx, m); err != nil {
+		return nil, fmt.Errorf("Upload: s.dbClient.CreateMedia: %w", err)
+	}
+	return m, nil
+}
+
+// Open returns the image or thumbnail stored under hash, with its content type
+func (s *Service) Open(ctx context.Context, hash string) ([]byte, string, error) {
+	if err := domain.ValidateMediaHash(hash); err != nil {
+		return nil, "", fmt.Errorf("Open: %w", err)
+	}
+	m, err := s.dbClient.GetMediaByHash(ctx, hash)
+	if errors.Is(err, domain.ErrNotFound) {
+		m, err = s.dbClient.GetMediaByThumbnailHash(ctx, hash)
+		if err != nil {
+			return nil, "", fmt.Errorf("Open: s.dbClient.GetMediaByThumbnailHash: %w", err)
+		}
+	} else if err != nil {
+		return nil, "", fmt.Errorf("Open: s.dbClient.GetMediaByHash: %w", err)
+	}
+	data, err := s.storage.Get(ctx, hash)
+	if err != nil {
+		return nil, "", fmt.Errorf("Open: s.storage.Get: %w", err)
+	}
+	return data, m.ContentType, nil
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,315 @@
+package offer
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	defaultTTL     = 48 * time.Hour
+	minTTL         = time.Minute
+	maxTTL         = 7 * 24 * time.Hour
+	expireInterval = time.Minute
+)
+
+type dbClient interface {
+	GetItemByID(ctx context.Context, id string) (*domain.Item, error)
+	CreateOffer(ctx context.Context, o *domain.Offer) error
+	GetOfferByID(ctx context.Context, id string) (*domain.Offer, error)
+	ListOffersByItemID(ctx context.Context, itemID string) ([]*domain.Offer, error)
+	ListOpenOffersByBuyerID(ctx context.Context, buyerID string, now time.Time) ([]*domain.Offer, error)
+	ListPendingOffersByItemID(ctx context.Context, itemID string) ([]*domain.Offer, error)
+	ListExpiredOffers(ctx context.Context, now time.Time) ([]*domain.Offer, error)
+	UpdateOfferState(ctx context.Context, o *domain.Offer, state domain.OfferState) error
+}
+
+type itemService interface {
+	PurchaseOffer(ctx context.Context, id, buyerID string, price domain.Money) (*domain.Sale, error)
+}
+
+// escrow holds the buyer's side of a negotiation on chain. Both calls act for the buyer in ctx.
+type escrow interface {
+	HoldOffer(ctx context.Context, item *domain.Item, amount domain.Money) error
+	ReleaseOffer(ctx context.Context, item *domain.Item) error
+}
+
+type eventPublisher interface {
+	Publish(ctx context.Context, eventType domain.EventType, data any) error
+}
+
+type Service struct {
+	dbClient       dbClient
+	itemService    itemService
+	escrow         escrow
+	eventPublisher eventPublisher
+	now            func() time.Time
+}
+
+func New(dbClient dbClient, itemService itemService, escrow escrow, eventPublisher eventPublisher) *Service {
+	return &Service{
+		dbClient:       dbClient,
+		itemService:    itemService,
+		escrow:         escrow,
+		eventPublisher: eventPublisher,
+		now:            time.Now,
+	}
+}
+
+// MakeOffer opens a negotiation on a listed item with an amount below its list price.
+// The amount is held in escrow on chain until the negotiation ends, when the buyer gets it back unless they bought the item.
+func (s *Service) MakeOffer(ctx context.Context, itemID string, amount domain.Money, ttl time.Duration) (*domain.Offer, error) {
+	item, err := s.dbClient.GetItemByID(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("MakeOffer: s.dbClient.GetItemByID: %w", err)
+	}
+	buyerID := utils.FromContext(ctx)
+	if item.SellerID == buyerID {
+		return nil, fmt.Errorf("MakeOffer: the seller cannot make an offer on item (%s): %w", itemID, domain.ErrForbidden)
+	}
+	if item.State != domain.ItemStateListed {
+		return nil, fmt.Errorf("MakeOffer: item (%s) cannot take offers when %s: %w", itemID, item.State, domain.ErrConflict)
+	}
+	if amount.Currency() != item.Price.Currency() || amount.IsZero() || amount.Cmp(item.Price) >= 0 {
+		return nil, fmt.Errorf("MakeOffer: offer (%s) must be above zero and below the list price (%s): %w", amount, item.Price, domain.ErrInvalidArgument)
+	}
+	expiresAt, err := s.expiry(ttl)
+	if err != nil {
+		return nil, fmt.Errorf("MakeOffer: %w", err)
+	}
+
+	open, err := s.dbClient.ListOpenOffersByBuyerID(ctx, buyerID, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("MakeOffer: s.dbClient.ListOpenOffersByBuyerID: %w", err)
+	}
+	for _, o := range open {
+		if o.ItemID == itemID {
+			return nil, fmt.Errorf("MakeOffer: offer (%s) on item (%s) is still open: %w", o.ID, itemID, domain.ErrConflict)
+		}
+	}
+	if err := s.escrow.HoldOffer(ctx, item, amount); err != nil {
+		return nil, fmt.Errorf("MakeOffer: s.escrow.HoldOffer: %w", err)
+	}
+
+	o := &domain.Offer{
+		ItemID:     itemID,
+		BuyerID:    buyerID,
+		SellerID:   item.SellerID,
+		ProposedBy: buyerID,
+		Amount:     amount,
+		State:      domain.OfferStatePending,
+		ExpiresAt:  expiresAt,
+	}
+	if err := s.dbClient.CreateOffer(ctx, o); err != nil {
+		if err := s.escrow.ReleaseOffer(ctx, item); err != nil {
+			log.Printf("Failed to release the offer of user (%s) on item (%s): %s", buyerID, itemID, err.Error())
+		}
+		return nil, fmt.Errorf("MakeOffer: s.dbClient.CreateOffer: %w", err)
+	}
+	s.publish(ctx, domain.EventOfferMade, o)
+	return o, nil
+}
+
+// CounterOffer replaces an open offer with a new amount proposed by the party the offer was made to
+func (s *Service) CounterOffer(ctx context.Context, id string, amount domain.Money, ttl time.Duration) (*domain.Offer, error) {
+	o, item, err := s.respondable(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("CounterOffer: %w", err)
+	}
+	if amount.Currency() != item.Price.Currency() || amount.IsZero() || amount.Cmp(item.Price) > 0 {
+		return nil, fmt.Errorf("CounterOffer: counter-offer (%s) must be above zero and at most the list price (%s): %w", amount, item.Price, domain.ErrInvalidArgument)
+	}
+	expiresAt, err := s.expiry(ttl)
+	if err != nil {
+		return nil, fmt.Errorf("CounterOffer: %w", err)
+	}
+
+	// The buyer's escrow follows their own offers, a seller's counter-offer is only held once the buyer accepts it
+	uid := utils.FromContext(ctx)
+	if uid == o.BuyerID {
+		if err := s.escrow.HoldOffer(ctx, item, amount); err != nil {
+			return nil, fmt.Errorf("CounterOffer: s.escrow.HoldOffer: %w", err)
+		}
+	}
+
+	if err := s.dbClient.UpdateOfferState(ctx, o, domain.OfferStateCountered); err != nil {
+		return nil, fmt.Errorf("CounterOffer: s.dbClient.UpdateOfferState: %w", err)
+	}
+	counter := &domain.Offer{
+		ItemID:     o.ItemID,
+		BuyerID:    o.BuyerID,
+		SellerID:   o.SellerID,
+		ProposedBy: uid,
+		Amount:     amount,
+		State:      domain.OfferStatePending,
+		ParentID:   o.ID,
+		ExpiresAt:  expiresAt,
+	}
+	if err := s.dbClient.CreateOffer(ctx, counter); err != nil {
+		return nil, fmt.Errorf("CounterOffer: s.dbClient.CreateOffer: %w", err)
+	}
+	s.publish(ctx, domain.EventOfferCountered, counter)
+	return counter, nil
+}
+
+// AcceptOffer sells the item to the buyer at the offered amount, out of the amount they hold in escrow.
+// Every other open offer on the item is rejected, and the funds held for them released.
+func (s *Service) AcceptOffer(ctx context.Context, id string) (*domain.Sale, error) {
+	o, item, err := s.respondable(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("AcceptOffer: %w", err)
+	}
+	// A buyer accepting the seller's counter-offer first brings their escrow to its amount
+	if o.ProposedBy == o.SellerID {
+		if err := s.escrow.HoldOffer(ctx, item, o.Amount); err != nil {
+			return nil, fmt.Errorf("AcceptOffer: s.escrow.HoldOffer: %w", err)
+		}
+	}
+
+	sale, err := s.itemService.PurchaseOffer(ctx, o.ItemID, o.BuyerID, o.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("AcceptOffer: s.itemService.PurchaseOffer: %w", err)
+	}
+	if err := s.dbClient.UpdateOfferState(ctx, o, domain.OfferStateAccepted); err != nil {
+		return nil, fmt.Errorf("AcceptOffer: s.dbClient.UpdateOfferState: %w", err)
+	}
+	s.publish(ctx, domain.EventOfferAccepted, o)
+
+	others, err := s.dbClient.ListPendingOffersByItemID(ctx, o.ItemID)
+	if err != nil {
+		return nil, fmt.Errorf("AcceptOffer: s.dbClient.ListPendingOffersByItemID: %w", err)
+	}
+	for _, other := range others {
+		// An offer that cannot be released now stays pending, and is released once it expires
+		if err := s.end(ctx, other, item, domain.OfferStateRejected); err != nil {
+			log.Printf("Failed to reject offer (%s) on sold item (%s): %s", other.ID, o.ItemID, err.Error())
+		}
+	}
+	return sale, nil
+}
+
+// RejectOffer ends the negotiation, releasing the funds held for the offer
+func (s *Service) RejectOffer(ctx context.Context, id string) error {
+	o, item, err := s.respondable(ctx, id)
+	if err != nil {
+		return fmt.Errorf("RejectOffer: %w", err)
+	}
+	if err := s.end(ctx, o, item, domain.OfferStateRejected); err != nil {
+		return fmt.Errorf("RejectOffer: %w", err)
+	}
+	s.publish(ctx, domain.EventOfferRejected, o)
+	return nil
+}
+
+// ListOffers returns every offer on the item to its seller, and only their own offers to anyone else
+func (s *Service) ListOffers(ctx context.Context, itemID string) ([]*domain.Offer, error) {
+	item, err := s.dbClient.GetItemByID(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("ListOffers: s.dbClient.GetItemByID: %w", err)
+	}
+	offers, err := s.dbClient.ListOffersByItemID(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("ListOffers: s.dbClient.ListOffersByItemID: %w", err)
+	}
+	uid := utils.FromContext(ctx)
+	visible := make([]*domain.Offer, 0, len(offers))
+	for _, o := range offers {
+		if uid == item.SellerID || uid == o.BuyerID {
+			visible = append(visible, o)
+		}
+	}
+	return visible, nil
+}
+
+// Run expires offers past their expiry until ctx is done, releasing the funds held for them
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(expireInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.expire(ctx)
+		}
+	}
+}
+
+func (s *Service) expire(ctx context.Context) {
+	offers, err := s.dbClient.ListExpiredOffers(ctx, s.now())
+	if err != nil {
+		log.Printf("Failed to list expired offers: %s", err.Error())
+		return
+	}
+	for _, o := range offers {
+		item, err := s.dbClient.GetItemByID(ctx, o.ItemID)
+		if err != nil {
+			log.Printf("Failed to get item (%s) of expired offer (%s): %s", o.ItemID, o.ID, err.Error())
+			continue
+		}
+		if err := s.end(ctx, o, item, domain.OfferStateExpired); err != nil {
+			log.Printf("Failed to expire offer (%s): %s", o.ID, err.Error())
+		}
+	}
+	if len(offers) > 0 {
+		log.Printf("Expired %d offers", len(offers))
+	}
+}
+
+// end releases the buyer's escrow on the item, then moves the pending offer to state. The escrow is released first,
+// so that an offer whose funds could not be released stays pending and is tried again once it expires.
+func (s *Service) end(ctx context.Context, o *domain.Offer, item *domain.Item, state domain.OfferState) error {
+	if err := s.escrow.ReleaseOffer(utils.NewContext(ctx, o.BuyerID), item); err != nil {
+		return fmt.Errorf("s.escrow.ReleaseOffer: %w", err)
+	}
+	if err := s.dbClient.UpdateOfferState(ctx, o, state); err != nil {
+		return fmt.Errorf("s.dbClient.UpdateOfferState: %w", err)
+	}
+	return nil
+}
+
+// respondable returns the offer when the caller may respond to it, expiring it when it is past its expiry
+func (s *Service) respondable(ctx context.Context, id string) (*domain.Offer, *domain.Item, error) {
+	o, err := s.dbClient.GetOfferByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("s.dbClient.GetOfferByID: %w", err)
+	}
+	if o.Respondent() != utils.FromContext(ctx) {
+		return nil, nil, fmt.Errorf("only the other party can respond to offer (%s): %w", id, domain.ErrForbidden)
+	}
+	item, err := s.dbClient.GetItemByID(ctx, o.ItemID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("s.dbClient.GetItemByID: %w", err)
+	}
+	if !o.Open(s.now()) {
+		if o.State == domain.OfferStatePending {
+			if err := s.end(ctx, o, item, domain.OfferStateExpired); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, fmt.Errorf("offer (%s) is %s: %w", id, o.State, domain.ErrConflict)
+	}
+	if item.State != domain.ItemStateListed {
+		return nil, nil, fmt.Errorf("item (%s) is no longer listed: %w", o.ItemID, domain.ErrConflict)
+	}
+	return o, item, nil
+}
+
+func (s *Service) expiry(ttl time.Duration) (time.Time, error) {
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	if ttl < minTTL || ttl > maxTTL {
+		return time.Time{}, fmt.Errorf("offer expiry (%s) must be between %s and %s: %w", ttl, minTTL, maxTTL, domain.ErrInvalidArgument)
+	}
+	return s.now().Add(ttl).UTC(), nil
+}
+
+func (s *Service) publish(ctx context.Context, eventType domain.EventType, o *domain.Offer) {
+	if err := s.eventPublisher.Publish(ctx, eventType, o); err != nil {
+		log.Printf("Failed to publish %s event for offer (%s): %s", eventType, o.ID, err.Error())
+	}
+}
@@ -0,0 +1,61 @@
+package pool
+
+import (
+	"backend/internal/domain"
+	"context"
+	"fmt"
+	"log"
+)
+
+type fireflyClient interface {
+	EnsurePool(ctx context.Context, name string, poolType domain.TokenPoolType) (*domain.TokenPool, error)
+	SetDefaultPool(name string)
+}
+
+type dbClient interface {
+	UpsertTokenPool(ctx context.Context, p *domain.TokenPool) error
+}
+
+type Service struct {
+	fireflyClient fireflyClient
+	dbClient      dbClient
+}
+
+func New(fireflyClient fireflyClient, dbClient dbClient) *Service {
+	return &Service{
+		fireflyClient: fireflyClient,
+		dbClient:      dbClient,
+	}
+}
+
+// Bootstrap resolves every named NFT pool and the payment pool, creating the missing ones, and records them.
+// The first NFT pool becomes the default pool for items that do not name one. The payment pool is optional.
+func (s *Service) Bootstrap(ctx context.Context, names []string, paymentPool string) error {
+	if len(names) == 0 {
+		return fmt.Errorf("Bootstrap: no token pool configured: %w", domain.ErrInvalidArgument)
+	}
+	for _, name := range names {
+		if err := s.ensure(ctx, name, domain.TokenPoolTypeNonFungible); err != nil {
+			return fmt.Errorf("Bootstrap: %w", err)
+		}
+	}
+	if len(paymentPool) > 0 {
+		if err := s.ensure(ctx, paymentPool, domain.TokenPoolTypeFungible); err != nil {
+			return fmt.Errorf("Bootstrap: %w", err)
+		}
+	}
+	s.fireflyClient.SetDefaultPool(names[0])
+	return nil
+}
+
+func (s *Service) ensure(ctx context.Context, name string, poolType domain.TokenPoolType) error {
+	p, err := s.fireflyClient.EnsurePool(ctx, name, poolType)
+	if err != nil {
+		return fmt.Errorf("s.fireflyClient.EnsurePool: %w", err)
+	}
+	if err := s.dbClient.UpsertTokenPool(ctx, p); err != nil {
+		return fmt.Errorf("s.dbClient.UpsertTokenPool: %w", err)
+	}
+	log.Printf("%s token pool (%s) is confirmed at %s", p.Type, p.Name, p.Address)
+	return nil
+}
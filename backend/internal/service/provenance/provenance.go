@@ -0,0 +1,159 @@
+package provenance
+
+import (
+	"backend/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+type dbClient interface {
+	ListItemsByNFTID(ctx context.Context, nftID string) ([]*domain.Item, error)
+	ListContractDeploymentsByNFTID(ctx context.Context, nftID string) ([]*domain.ContractDeployment, error)
+}
+
+// tokens reads the history of NFTs in Firefly's token pools
+type tokens interface {
+	Pool(name string) (*domain.TokenPool, error)
+	TokenTransfers(ctx context.Context, poolName, tokenIndex string) ([]*domain.ProvenanceEntry, error)
+}
+
+// marketplace reads the listings and sales of NFTs from the marketplace contract's events.
+// contracts are the addresses of the contracts the registry records as deployed for the token.
+type marketplace interface {
+	History(ctx context.Context, tokenIndex string, contracts []string) ([]*domain.ProvenanceEntry, error)
+}
+
+type signer interface {
+	Algorithm() string
+	Sign(payload []byte) []byte
+	PublicKey() []byte
+}
+
+type Service struct {
+	dbClient    dbClient
+	tokens      tokens
+	marketplace marketplace
+	signer      signer
+	now         func() time.Time
+}
+
+func New(dbClient dbClient, tokens tokens, marketplace marketplace, signer signer) *Service {
+	return &Service{
+		dbClient:    dbClient,
+		tokens:      tokens,
+		marketplace: marketplace,
+		signer:      signer,
+		now:         time.Now,
+	}
+}
+
+// GetProvenance assembles the timeline of the NFT with the token index in the named pool, or in the default pool
+func (s *Service) GetProvenance(ctx context.Context, poolName, tokenIndex string) (*domain.Provenance, error) {
+	if n, ok := new(big.Int).SetString(tokenIndex, 10); !ok || n.Sign() < 0 {
+		return nil, fmt.Errorf("GetProvenance: token index (%s) must be a non-negative integer: %w", tokenIndex, domain.ErrInvalidArgument)
+	}
+	pool, err := s.tokens.Pool(poolName)
+	if err != nil {
+		return nil, fmt.Errorf("GetProvenance: s.tokens.Pool: %w", err)
+	}
+
+	entries, err := s.tokens.TokenTransfers(ctx, pool.Name, tokenIndex)
+	if err != nil {
+		return nil, fmt.Errorf("GetProvenance: s.tokens.TokenTransfers: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("GetProvenance: token (%s) was never minted in pool (%s): %w", tokenIndex, pool.Name, domain.ErrNotFound)
+	}
+	contracts, err := s.contracts(ctx, pool, tokenIndex)
+	if err != nil {
+		return nil, fmt.Errorf("GetProvenance: %w", err)
+	}
+	history, err := s.marketplace.History(ctx, tokenIndex, contracts)
+	if err != nil {
+		return nil, fmt.Errorf("GetProvenance: s.marketplace.History: %w", err)
+	}
+	entries = append(entries, history...)
+	// Entries of the same block keep their order, transfers first and contract events after them
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].BlockNumber < entries[j].BlockNumber })
+
+	items, err := s.items(ctx, pool, tokenIndex)
+	if err != nil {
+		return nil, fmt.Errorf("GetProvenance: %w", err)
+	}
+	return &domain.Provenance{
+		Pool:        pool.Name,
+		Contract:    pool.Address,
+		TokenIndex:  tokenIndex,
+		Items:       items,
+		Entries:     entries,
+		GeneratedAt: s.now().UTC(),
+	}, nil
+}
+
+// ExportProvenance returns the timeline signed with the marketplace's key
+func (s *Service) ExportProvenance(ctx context.Context, poolName, tokenIndex string) (*domain.SignedProvenance, error) {
+	p, err := s.GetProvenance(ctx, poolName, tokenIndex)
+	if err != nil {
+		return nil, fmt.Errorf("ExportProvenance: %w", err)
+	}
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("ExportProvenance: json.Marshal: %w", err)
+	}
+	return &domain.SignedProvenance{
+		Algorithm: s.signer.Algorithm(),
+		Payload:   payload,
+		Signature: s.signer.Sign(payload),
+		PublicKey: s.signer.PublicKey(),
+	}, nil
+}
+
+// PublicKey is the key exports are signed with, for buyers to pin instead of trusting the one in an export
+func (s *Service) PublicKey() *domain.PublicKey {
+	return &domain.PublicKey{
+		Algorithm: s.signer.Algorithm(),
+		Key:       s.signer.PublicKey(),
+	}
+}
+
+// contracts returns the addresses of the contracts deployed per listing for the NFT
+func (s *Service) contracts(ctx context.Context, pool *domain.TokenPool, tokenIndex string) ([]string, error) {
+	deployments, err := s.dbClient.ListContractDeploymentsByNFTID(ctx, tokenIndex)
+	if err != nil {
+		return nil, fmt.Errorf("s.dbClient.ListContractDeploymentsByNFTID: %w", err)
+	}
+	var addresses []string
+	for _, d := range deployments {
+		p, err := s.tokens.Pool(d.PoolName)
+		if err != nil || p.ID != pool.ID {
+			continue
+		}
+		addresses = append(addresses, d.Address)
+	}
+	return addresses, nil
+}
+
+// items returns the off-chain records of the NFT. Items without a pool name are in the default pool.
+func (s *Service) items(ctx context.Context, pool *domain.TokenPool, tokenIndex string) ([]domain.ProvenanceItem, error) {
+	candidates, err := s.dbClient.ListItemsByNFTID(ctx, tokenIndex)
+	if err != nil {
+		return nil, fmt.Errorf("s.dbClient.ListItemsByNFTID: %w", err)
+	}
+	items := []domain.ProvenanceItem{}
+	for _, item := range candidates {
+		p, err := s.tokens.Pool(item.PoolName)
+		if err != nil || p.ID != pool.ID {
+			continue
+		}
+		items = append(items, domain.ProvenanceItem{
+			ItemID:    item.ID,
+			Name:      item.Name,
+			CreatorID: item.CreatorID,
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,145 @@
+package review
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+type dbClient interface {
+	ListSalesByItemID(ctx context.Context, itemID string) ([]*domain.Sale, error)
+	CreateReview(ctx context.Context, r *domain.Review) error
+	GetReviewByID(ctx context.Context, id string) (*domain.Review, error)
+	GetReviewBySaleID(ctx context.Context, saleID string) (*domain.Review, error)
+	ListReviewsBySellerID(ctx context.Context, sellerID string) ([]*domain.Review, error)
+}
+
+// anchor keeps review hashes on chain through Firefly
+type anchor interface {
+	AnchorReview(ctx context.Context, anchor *domain.ReviewAnchor) (string, error)
+	ReviewAnchor(ctx context.Context, messageID string) (*domain.ReviewAnchor, error)
+}
+
+type Service struct {
+	dbClient dbClient
+	anchor   anchor
+	// halfLife is the age at which a review counts half as much towards a reputation as one left now
+	halfLife time.Duration
+	now      func() time.Time
+}
+
+func New(dbClient dbClient, anchor anchor, halfLife time.Duration) *Service {
+	return &Service{
+		dbClient: dbClient,
+		anchor:   anchor,
+		halfLife: halfLife,
+		now:      time.Now,
+	}
+}
+
+// CreateReview is the buyer rating the seller of their latest completed purchase of the item.
+// The review's hash is anchored before the review is stored, so a stored review always has an anchor.
+func (s *Service) CreateReview(ctx context.Context, itemID string, rating int, text string) (*domain.Review, error) {
+	uid := utils.FromContext(ctx)
+	sale, err := s.completedPurchase(ctx, itemID, uid)
+	if err != nil {
+		return nil, fmt.Errorf("CreateReview: %w", err)
+	}
+	if _, err := s.dbClient.GetReviewBySaleID(ctx, sale.ID); err == nil {
+		return nil, fmt.Errorf("CreateReview: sale (%s) is already reviewed: %w", sale.ID, domain.ErrConflict)
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("CreateReview: s.dbClient.GetReviewBySaleID: %w", err)
+	}
+
+	r := &domain.Review{
+		SaleID:   sale.ID,
+		ItemID:   sale.ItemID,
+		SellerID: sale.SellerID,
+		BuyerID:  uid,
+		Rating:   rating,
+		Text:     text,
+		// The database keeps microseconds, and the hash must match the stored review
+		CreatedAt: s.now().UTC().Truncate(time.Microsecond),
+	}
+	if err := r.Validate(); err != nil {
+		return nil, fmt.Errorf("CreateReview: %w", err)
+	}
+	r.Hash = r.ContentHash()
+	if r.AnchorID, err = s.anchor.AnchorReview(ctx, &domain.ReviewAnchor{SaleID: r.SaleID, Hash: r.Hash}); err != nil {
+		return nil, fmt.Errorf("CreateReview: s.anchor.AnchorReview: %w", err)
+	}
+	if err := s.dbClient.CreateReview(ctx, r); err != nil {
+		return nil, fmt.Errorf("CreateReview: s.dbClient.CreateReview: %w", err)
+	}
+	return r, nil
+}
+
+// completedPurchase returns the buyer's latest purchase of the item that is settled
+func (s *Service) completedPurchase(ctx context.Context, itemID, buyerID string) (*domain.Sale, error) {
+	sales, err := s.dbClient.ListSalesByItemID(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("s.dbClient.ListSalesByItemID: %w", err)
+	}
+	for i := len(sales) - 1; i >= 0; i-- {
+		if sales[i].BuyerID == buyerID && sales[i].CompletedAt != nil {
+			return sales[i], nil
+		}
+	}
+	return nil, fmt.Errorf("only buyers of item (%s) whose purchase is completed can review its seller: %w", itemID, domain.ErrForbidden)
+}
+
+// GetReview returns the review with whether its content still matches the hash anchored on chain.
+// Changing the review in the database, or its stored hash, makes it show as not intact.
+func (s *Service) GetReview(ctx context.Context, id string) (*domain.CheckedReview, error) {
+	r, err := s.dbClient.GetReviewByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("GetReview: s.dbClient.GetReviewByID: %w", err)
+	}
+	anchored, err := s.anchor.ReviewAnchor(ctx, r.AnchorID)
+	if err != nil {
+		return nil, fmt.Errorf("GetReview: s.anchor.ReviewAnchor: %w", err)
+	}
+	hash := r.ContentHash()
+	return &domain.CheckedReview{
+		Review: r,
+		Intact: hash == r.Hash && hash == anchored.Hash && anchored.SaleID == r.SaleID,
+	}, nil
+}
+
+func (s *Service) ListSellerReviews(ctx context.Context, sellerID string) ([]*domain.Review, error) {
+	reviews, err := s.dbClient.ListReviewsBySellerID(ctx, sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("ListSellerReviews: s.dbClient.ListReviewsBySellerID: %w", err)
+	}
+	return reviews, nil
+}
+
+// Reputation sums up the seller's reviews, with recent reviews counting more towards the score
+func (s *Service) Reputation(ctx context.Context, sellerID string) (*domain.Reputation, error) {
+	reviews, err := s.dbClient.ListReviewsBySellerID(ctx, sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("Reputation: s.dbClient.ListReviewsBySellerID: %w", err)
+	}
+	rep := &domain.Reputation{SellerID: sellerID, Reviews: len(reviews)}
+	now := s.now()
+	var sum, total float64
+	for _, r := range reviews {
+		if r.Rating < domain.MinRating || r.Rating > domain.MaxRating {
+			continue
+		}
+		rep.Ratings[r.Rating-1]++
+		age := now.Sub(r.CreatedAt).Hours() / s.halfLife.Hours()
+		weight := math.Pow(0.5, math.Max(age, 0))
+		sum += weight * float64(r.Rating)
+		total += weight
+	}
+	if total > 0 {
+		// Rounded so that the score does not change on every request as reviews age
+		rep.Score = math.Round(sum/total*100) / 100
+	}
+	return rep, nil
+}
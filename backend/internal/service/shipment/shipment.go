@@ -0,0 +1,309 @@
+package shipment
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const trackInterval = time.Minute
+
+type dbClient interface {
+	GetItemByID(ctx context.Context, id string) (*domain.Item, error)
+	CreateShipment(ctx context.Context, sh *domain.Shipment) error
+	GetLatestShipmentByItemID(ctx context.Context, itemID string) (*domain.Shipment, error)
+	ListShipmentsByStatus(ctx context.Context, statuses ...domain.ShipmentStatus) ([]*domain.Shipment, error)
+	UpdateShipment(ctx context.Context, sh *domain.Shipment, from domain.ShipmentStatus) error
+	UpsertShippingAddress(ctx context.Context, itemID, buyerID string, sealed []byte) error
+	GetShippingAddress(ctx context.Context, itemID, buyerID string) ([]byte, error)
+}
+
+type itemService interface {
+	ShipItem(ctx context.Context, id string) error
+	ReceiveItem(ctx context.Context, id string) error
+}
+
+// Carrier is an adapter to a shipping carrier's tracking API
+type Carrier interface {
+	Track(ctx context.Context, trackingNumber string) (*domain.TrackingUpdate, error)
+}
+
+// sealer encrypts shipping addresses at rest
+type sealer interface {
+	Seal(plaintext, aad []byte) ([]byte, error)
+	Open(ciphertext, aad []byte) ([]byte, error)
+}
+
+type eventPublisher interface {
+	Publish(ctx context.Context, eventType domain.EventType, data any) error
+}
+
+type Service struct {
+	dbClient       dbClient
+	itemService    itemService
+	carriers       map[string]Carrier
+	sealer         sealer
+	eventPublisher eventPublisher
+	// settleAfter is how long after delivery the buyer has to confirm or dispute before the sale settles on its own
+	settleAfter time.Duration
+	now         func() time.Time
+}
+
+// New returns a shipment service tracking parcels through carriers, keyed by the name sellers record shipments with
+func New(dbClient dbClient, itemService itemService, carriers map[string]Carrier, sealer sealer, eventPublisher eventPublisher, settleAfter time.Duration) *Service {
+	return &Service{
+		dbClient:       dbClient,
+		itemService:    itemService,
+		carriers:       carriers,
+		sealer:         sealer,
+		eventPublisher: eventPublisher,
+		settleAfter:    settleAfter,
+		now:            time.Now,
+	}
+}
+
+// SetShippingAddress stores where the buyer wants a sold item delivered, until it has been shipped
+func (s *Service) SetShippingAddress(ctx context.Context, itemID string, addr *domain.ShippingAddress) error {
+	item, err := s.dbClient.GetItemByID(ctx, itemID)
+	if err != nil {
+		return fmt.Errorf("SetShippingAddress: s.dbClient.GetItemByID: %w", err)
+	}
+	if item.BuyerID != utils.FromContext(ctx) {
+		return fmt.Errorf("SetShippingAddress: only the buyer can give a shipping address for item (%s): %w", itemID, domain.ErrForbidden)
+	}
+	if item.State != domain.ItemStateSold {
+		return fmt.Errorf("SetShippingAddress: shipping address of item (%s) cannot be changed when %s: %w", itemID, item.State, domain.ErrConflict)
+	}
+	if err := addr.Validate(); err != nil {
+		return fmt.Errorf("SetShippingAddress: %w", err)
+	}
+
+	plaintext, err := json.Marshal(addr)
+	if err != nil {
+		return fmt.Errorf("SetShippingAddress: json.Marshal: %w", err)
+	}
+	sealed, err := s.sealer.Seal(plaintext, addressAAD(itemID, item.BuyerID))
+	if err != nil {
+		return fmt.Errorf("SetShippingAddress: s.sealer.Seal: %w", err)
+	}
+	if err := s.dbClient.UpsertShippingAddress(ctx, itemID, item.BuyerID, sealed); err != nil {
+		return fmt.Errorf("SetShippingAddress: s.dbClient.UpsertShippingAddress: %w", err)
+	}
+	return nil
+}
+
+// GetShippingAddress shows the buyer's address to the seller of the sale, and to nobody else,
+// for as long as the item is waiting to be shipped or on its way
+func (s *Service) GetShippingAddress(ctx context.Context, itemID string) (*domain.ShippingAddress, error) {
+	item, err := s.dbClient.GetItemByID(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("GetShippingAddress: s.dbClient.GetItemByID: %w", err)
+	}
+	if item.SellerID != utils.FromContext(ctx) {
+		return nil, fmt.Errorf("GetShippingAddress: only the seller can see the shipping address of item (%s): %w", itemID, domain.ErrForbidden)
+	}
+	if item.State != domain.ItemStateSold && item.State != domain.ItemStateShipped {
+		return nil, fmt.Errorf("GetShippingAddress: item (%s) has no sale to ship when %s: %w", itemID, item.State, domain.ErrNotFound)
+	}
+	return s.shippingAddress(ctx, item)
+}
+
+func (s *Service) shippingAddress(ctx context.Context, item *domain.Item) (*domain.ShippingAddress, error) {
+	sealed, err := s.dbClient.GetShippingAddress(ctx, item.ID, item.BuyerID)
+	if err != nil {
+		return nil, fmt.Errorf("s.dbClient.GetShippingAddress: %w", err)
+	}
+	plaintext, err := s.sealer.Open(sealed, addressAAD(item.ID, item.BuyerID))
+	if err != nil {
+		return nil, fmt.Errorf("s.sealer.Open: %w", err)
+	}
+	var addr domain.ShippingAddress
+	if err := json.Unmarshal(plaintext, &addr); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	return &addr, nil
+}
+
+// RecordShipment is called by the seller with the carrier and tracking number once a sold item
+// has been handed over. The item moves to Shipped, and the carrier is polled from then on.
+func (s *Service) RecordShipment(ctx context.Context, itemID, carrierName, trackingNumber string) (*domain.Shipment, error) {
+	if _, ok := s.carriers[carrierName]; !ok {
+		return nil, fmt.Errorf("RecordShipment: unknown carrier (%s): %w", carrierName, domain.ErrInvalidArgument)
+	}
+	trackingNumber = strings.TrimSpace(trackingNumber)
+	if len(trackingNumber) == 0 {
+		return nil, fmt.Errorf("RecordShipment: tracking number is required: %w", domain.ErrInvalidArgument)
+	}
+	item, err := s.dbClient.GetItemByID(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("RecordShipment: s.dbClient.GetItemByID: %w", err)
+	}
+	if item.SellerID != utils.FromContext(ctx) {
+		return nil, fmt.Errorf("RecordShipment: only the seller can ship item (%s): %w", itemID, domain.ErrForbidden)
+	}
+	// The seller cannot have shipped anywhere before the buyer said where to
+	if _, err := s.dbClient.GetShippingAddress(ctx, itemID, item.BuyerID); err != nil {
+		return nil, fmt.Errorf("RecordShipment: buyer of item (%s) has not given a shipping address: %w", itemID, domain.ErrConflict)
+	}
+
+	if err := s.itemService.ShipItem(ctx, itemID); err != nil {
+		return nil, fmt.Errorf("RecordShipment: s.itemService.ShipItem: %w", err)
+	}
+	sh := &domain.Shipment{
+		ItemID:         itemID,
+		SellerID:       item.SellerID,
+		BuyerID:        item.BuyerID,
+		Carrier:        carrierName,
+		TrackingNumber: trackingNumber,
+		Status:         domain.ShipmentStatusInTransit,
+	}
+	if err := s.dbClient.CreateShipment(ctx, sh); err != nil {
+		return nil, fmt.Errorf("RecordShipment: s.dbClient.CreateShipment: %w", err)
+	}
+	s.publish(ctx, sh)
+	return sh, nil
+}
+
+// GetShipment returns the item's latest shipment to its seller or buyer
+func (s *Service) GetShipment(ctx context.Context, itemID string) (*domain.Shipment, error) {
+	sh, err := s.dbClient.GetLatestShipmentByItemID(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("GetShipment: s.dbClient.GetLatestShipmentByItemID: %w", err)
+	}
+	uid := utils.FromContext(ctx)
+	if sh.SellerID != uid && sh.BuyerID != uid {
+		return nil, fmt.Errorf("GetShipment: only the seller or buyer can track item (%s): %w", itemID, domain.ErrForbidden)
+	}
+	return sh, nil
+}
+
+// ConfirmDelivery is called by the buyer once the item has arrived. It settles the sale,
+// releasing the payment to the seller and transferring the NFT to the buyer.
+func (s *Service) ConfirmDelivery(ctx context.Context, itemID string) (*domain.Shipment, error) {
+	sh, err := s.dbClient.GetLatestShipmentByItemID(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("ConfirmDelivery: s.dbClient.GetLatestShipmentByItemID: %w", err)
+	}
+	if sh.BuyerID != utils.FromContext(ctx) {
+		return nil, fmt.Errorf("ConfirmDelivery: only the buyer can confirm delivery of item (%s): %w", itemID, domain.ErrForbidden)
+	}
+	if sh.Status == domain.ShipmentStatusConfirmed {
+		return nil, fmt.Errorf("ConfirmDelivery: shipment (%s) is already confirmed: %w", sh.ID, domain.ErrConflict)
+	}
+	if err := s.confirm(ctx, sh); err != nil {
+		return nil, fmt.Errorf("ConfirmDelivery: %w", err)
+	}
+	return sh, nil
+}
+
+// confirm settles the sale of the shipment for its buyer, who is the user in ctx, and marks the shipment confirmed
+func (s *Service) confirm(ctx context.Context, sh *domain.Shipment) error {
+	if err := s.itemService.ReceiveItem(ctx, sh.ItemID); err != nil {
+		return fmt.Errorf("s.itemService.ReceiveItem: %w", err)
+	}
+	from := sh.Status
+	now := s.now().UTC()
+	sh.Status = domain.ShipmentStatusConfirmed
+	sh.ConfirmedAt = &now
+	if err := s.dbClient.UpdateShipment(ctx, sh, from); err != nil {
+		return fmt.Errorf("s.dbClient.UpdateShipment: %w", err)
+	}
+	s.publish(ctx, sh)
+	return nil
+}
+
+// Run polls the carriers of shipments on their way, and settles delivered ones the buyer left alone, until ctx is done
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(trackInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.track(ctx); err != nil {
+				log.Printf("Failed to track shipments: %s", err.Error())
+			}
+			if err := s.settle(ctx); err != nil {
+				log.Printf("Failed to settle delivered shipments: %s", err.Error())
+			}
+		}
+	}
+}
+
+func (s *Service) track(ctx context.Context) error {
+	shipments, err := s.dbClient.ListShipmentsByStatus(ctx, domain.ShipmentStatusInTransit, domain.ShipmentStatusException)
+	if err != nil {
+		return fmt.Errorf("s.dbClient.ListShipmentsByStatus: %w", err)
+	}
+	for _, sh := range shipments {
+		c, ok := s.carriers[sh.Carrier]
+		if !ok {
+			log.Printf("Shipment (%s) uses carrier (%s) which is no longer configured", sh.ID, sh.Carrier)
+			continue
+		}
+		update, err := c.Track(ctx, sh.TrackingNumber)
+		if err != nil {
+			log.Printf("Failed to track shipment (%s) with %s: %s", sh.ID, sh.Carrier, err.Error())
+			continue
+		}
+		if update.Status == sh.Status {
+			continue
+		}
+
+		from := sh.Status
+		sh.Status = update.Status
+		if update.Status == domain.ShipmentStatusDelivered {
+			deliveredAt := update.DeliveredAt
+			sh.DeliveredAt = &deliveredAt
+		}
+		if err := s.dbClient.UpdateShipment(ctx, sh, from); err != nil {
+			log.Printf("Failed to update shipment (%s): %s", sh.ID, err.Error())
+			continue
+		}
+		s.publish(ctx, sh)
+	}
+	return nil
+}
+
+// settle confirms the shipments delivered longer than settleAfter ago on their buyer's behalf. A buyer who
+// disputed in time has moved the item out of Shipped, and an arbiter settles the sale instead.
+func (s *Service) settle(ctx context.Context) error {
+	shipments, err := s.dbClient.ListShipmentsByStatus(ctx, domain.ShipmentStatusDelivered)
+	if err != nil {
+		return fmt.Errorf("s.dbClient.ListShipmentsByStatus: %w", err)
+	}
+	for _, sh := range shipments {
+		if sh.DeliveredAt == nil || s.now().Before(sh.DeliveredAt.Add(s.settleAfter)) {
+			continue
+		}
+		item, err := s.dbClient.GetItemByID(ctx, sh.ItemID)
+		if err != nil {
+			log.Printf("Failed to get item (%s) of shipment (%s): %s", sh.ItemID, sh.ID, err.Error())
+			continue
+		}
+		if item.State != domain.ItemStateShipped || item.BuyerID != sh.BuyerID {
+			continue
+		}
+		if err := s.confirm(utils.NewContext(ctx, sh.BuyerID), sh); err != nil {
+			log.Printf("Failed to settle shipment (%s): %s", sh.ID, err.Error())
+		}
+	}
+	return nil
+}
+
+// addressAAD binds an encrypted address to its sale, so that it cannot be copied to another row and decrypted there
+func addressAAD(itemID, buyerID string) []byte {
+	return []byte(itemID + "/" + buyerID)
+}
+
+func (s *Service) publish(ctx context.Context, sh *domain.Shipment) {
+	if err := s.eventPublisher.Publish(ctx, domain.EventShipmentUpdated, sh); err != nil {
+		log.Printf("Failed to publish %s event for shipment (%s): %s", domain.EventShipmentUpdated, sh.ID, err.Error())
+	}
+}
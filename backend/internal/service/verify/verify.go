@@ -0,0 +1,150 @@
+package verify
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+type dbClient interface {
+	ListItemsByNFTID(ctx context.Context, nftID string) ([]*domain.Item, error)
+}
+
+// tokens reads NFTs and the keys users hold them with from Firefly
+type tokens interface {
+	Pool(name string) (*domain.TokenPool, error)
+	TokenHolder(ctx context.Context, poolName, tokenIndex string) (*domain.TokenHolder, error)
+	SigningKey(ctx context.Context) (string, error)
+}
+
+type Service struct {
+	dbClient dbClient
+	tokens   tokens
+	now      func() time.Time
+}
+
+func New(dbClient dbClient, tokens tokens) *Service {
+	return &Service{
+		dbClient: dbClient,
+		tokens:   tokens,
+		now:      time.Now,
+	}
+}
+
+// VerifyCode verifies the NFT a QR code printed on an item points to
+func (s *Service) VerifyCode(ctx context.Context, payload string) (*domain.Verification, error) {
+	poolName, tokenIndex, err := domain.ParseVerificationCode(payload)
+	if err != nil {
+		return nil, fmt.Errorf("VerifyCode: %w", err)
+	}
+	return s.Verify(ctx, poolName, tokenIndex)
+}
+
+// Verify compares who holds the NFT on chain with the item the marketplace has on record for it.
+// Anyone may call it, so it only returns what is public on chain and the item's name.
+func (s *Service) Verify(ctx context.Context, poolName, tokenIndex string) (*domain.Verification, error) {
+	if n, ok := new(big.Int).SetString(tokenIndex, 10); !ok || n.Sign() < 0 {
+		return nil, fmt.Errorf("Verify: token index (%s) must be a non-negative integer: %w", tokenIndex, domain.ErrInvalidArgument)
+	}
+	pool, err := s.tokens.Pool(poolName)
+	if err != nil {
+		return nil, fmt.Errorf("Verify: s.tokens.Pool: %w", err)
+	}
+	holder, err := s.tokens.TokenHolder(ctx, pool.Name, tokenIndex)
+	if err != nil {
+		return nil, fmt.Errorf("Verify: s.tokens.TokenHolder: %w", err)
+	}
+	items, err := s.items(ctx, pool, tokenIndex)
+	if err != nil {
+		return nil, fmt.Errorf("Verify: %w", err)
+	}
+
+	v := &domain.Verification{
+		Pool:       pool.Name,
+		Contract:   pool.Address,
+		TokenIndex: tokenIndex,
+		Flags:      []domain.VerificationFlag{},
+		CheckedAt:  s.now().UTC(),
+	}
+	if holder == nil {
+		v.Flags = append(v.Flags, domain.VerificationFlagNotMinted)
+	} else {
+		v.Owner, v.TokenURI = holder.Owner, holder.URI
+	}
+	switch {
+	case len(items) == 0:
+		v.Flags = append(v.Flags, domain.VerificationFlagNoRecord)
+	case len(items) > 1:
+		v.Flags = append(v.Flags, domain.VerificationFlagDuplicateRecord)
+	}
+
+	if len(items) > 0 {
+		// The latest item is the one the NFT was last listed as
+		item := items[len(items)-1]
+		v.ItemID, v.ItemName = item.ID, item.Name
+		v.MetadataHash = item.Metadata(pool.Name).Hash()
+
+		if holder != nil {
+			// Tokens are minted with a URI carrying the hash, so one without a URI does not vouch for the record either
+			if !strings.Contains(holder.URI, v.MetadataHash) {
+				v.Flags = append(v.Flags, domain.VerificationFlagMetadataMismatch)
+			}
+			held, err := s.heldByExpectedOwner(ctx, item, holder.Owner)
+			if err != nil {
+				return nil, fmt.Errorf("Verify: %w", err)
+			}
+			if !held {
+				v.Flags = append(v.Flags, domain.VerificationFlagOwnerMismatch)
+			}
+		}
+	}
+	v.Matches = len(v.Flags) == 0
+	return v, nil
+}
+
+// heldByExpectedOwner reports whether owner is the key of a user the item's state says holds the NFT.
+// The NFT moves to the buyer on receipt with the shared contract, but already on purchase with a per-listing one.
+func (s *Service) heldByExpectedOwner(ctx context.Context, item *domain.Item, owner string) (bool, error) {
+	expected := []string{item.SellerID}
+	switch item.State {
+	case domain.ItemStateReceived:
+		expected = []string{item.BuyerID}
+	case domain.ItemStateSold, domain.ItemStateShipped:
+		expected = append(expected, item.BuyerID)
+	}
+	for _, uid := range expected {
+		if len(uid) == 0 {
+			continue
+		}
+		key, err := s.tokens.SigningKey(utils.NewContext(ctx, uid))
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return false, fmt.Errorf("s.tokens.SigningKey of user (%s): %w", uid, err)
+		}
+		if strings.EqualFold(key, owner) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// items returns the items backed by the NFT. Items without a pool name are in the default pool.
+func (s *Service) items(ctx context.Context, pool *domain.TokenPool, tokenIndex string) ([]*domain.Item, error) {
+	candidates, err := s.dbClient.ListItemsByNFTID(ctx, tokenIndex)
+	if err != nil {
+		return nil, fmt.Errorf("s.dbClient.ListItemsByNFTID: %w", err)
+	}
+	var items []*domain.Item
+	for _, item := range candidates {
+		if p, err := s.tokens.Pool(item.PoolName); err == nil && p.ID == pool.ID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,291 @@
+package webhook
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SignatureHeader = "X-Marketplace-Signature"
+	TimestampHeader = "X-Marketplace-Timestamp"
+	EventTypeHeader = "X-Marketplace-Event"
+
+	maxAttempts   = 8
+	baseBackoff   = time.Second * 10
+	maxBackoff    = time.Hour
+	pollInterval  = time.Second * 5
+	pollBatchSize = 50
+)
+
+type dbClient interface {
+	CreateWebhookSubscription(ctx context.Context, sub *domain.WebhookSubscription) error
+	GetWebhookSubscriptionByID(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	ListWebhookSubscriptionsByUserID(ctx context.Context, userID string) ([]*domain.WebhookSubscription, error)
+	ListWebhookSubscriptionsByEventType(ctx context.Context, eventType domain.EventType) ([]*domain.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id string) error
+	CreateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetWebhookDeliveryByID(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	ListWebhookDeliveriesBySubscriptionID(ctx context.Context, subscriptionID string) ([]*domain.WebhookDelivery, error)
+	ListDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
+
+// httpClient delivers to the URLs subscribers give. It must only reach public addresses, so that
+// subscribers cannot have the server call into its own network.
+type httpClient interface {
+	ValidateURL(address string) error
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type Service struct {
+	dbClient   dbClient
+	httpClient httpClient
+	now        func() time.Time
+}
+
+func New(dbClient dbClient, httpClient httpClient) *Service {
+	return &Service{
+		dbClient:   dbClient,
+		httpClient: httpClient,
+		now:        time.Now,
+	}
+}
+
+func (s *Service) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	if err := s.httpClient.ValidateURL(sub.URL); err != nil {
+		return fmt.Errorf("CreateSubscription: %w", err)
+	}
+	if len(sub.Secret) == 0 {
+		return fmt.Errorf("CreateSubscription: secret is required: %w", domain.ErrInvalidArgument)
+	}
+	if len(sub.EventTypes) == 0 {
+		return fmt.Errorf("CreateSubscription: at least one event type is required: %w", domain.ErrInvalidArgument)
+	}
+	for _, et := range sub.EventTypes {
+		if !et.Valid() {
+			return fmt.Errorf("CreateSubscription: unknown event type (%s): %w", et, domain.ErrInvalidArgument)
+		}
+	}
+
+	sub.UserID = utils.FromContext(ctx)
+	if err := s.dbClient.CreateWebhookSubscription(ctx, sub); err != nil {
+		return fmt.Errorf("CreateSubscription: s.dbClient.CreateWebhookSubscription: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	subs, err := s.dbClient.ListWebhookSubscriptionsByUserID(ctx, utils.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("s.dbClient.ListWebhookSubscriptionsByUserID: %w", err)
+	}
+	for _, sub := range subs {
+		sub.Secret = ""
+	}
+	return subs, nil
+}
+
+func (s *Service) DeleteSubscription(ctx context.Context, id string) error {
+	if _, err := s.getOwnSubscription(ctx, id); err != nil {
+		return fmt.Errorf("DeleteSubscription: %w", err)
+	}
+	if err := s.dbClient.DeleteWebhookSubscription(ctx, id); err != nil {
+		return fmt.Errorf("DeleteSubscription: s.dbClient.DeleteWebhookSubscription: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, subscriptionID string) ([]*domain.WebhookDelivery, error) {
+	if _, err := s.getOwnSubscription(ctx, subscriptionID); err != nil {
+		return nil, fmt.Errorf("ListDeliveries: %w", err)
+	}
+	deliveries, err := s.dbClient.ListWebhookDeliveriesBySubscriptionID(ctx, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("ListDeliveries: s.dbClient.ListWebhookDeliveriesBySubscriptionID: %w", err)
+	}
+	return deliveries, nil
+}
+
+// Redeliver queues a delivery again regardless of its current state, resetting the retry budget
+func (s *Service) Redeliver(ctx context.Context, deliveryID string) error {
+	d, err := s.dbClient.GetWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return fmt.Errorf("Redeliver: s.dbClient.GetWebhookDeliveryByID: %w", err)
+	}
+	if _, err := s.getOwnSubscription(ctx, d.SubscriptionID); err != nil {
+		return fmt.Errorf("Redeliver: %w", err)
+	}
+
+	d.State = domain.WebhookDeliveryStatePending
+	d.Attempts = 0
+	d.NextAttemptAt = s.now()
+	d.LastError = ""
+	if err := s.dbClient.UpdateWebhookDelivery(ctx, d); err != nil {
+		return fmt.Errorf("Redeliver: s.dbClient.UpdateWebhookDelivery: %w", err)
+	}
+	return nil
+}
+
+// Publish queues a delivery for every subscription listening to the event type whose owner is a party
+// to the event, such as the seller or buyer of the item. Deliveries are sent asynchronously by Run.
+func (s *Service) Publish(ctx context.Context, eventType domain.EventType, data any) error {
+	subs, err := s.dbClient.ListWebhookSubscriptionsByEventType(ctx, eventType)
+	if err != nil {
+		return fmt.Errorf("Publish: s.dbClient.ListWebhookSubscriptionsByEventType: %w", err)
+	}
+	parties := domain.EventParties(data)
+	subs = slices.DeleteFunc(subs, func(sub *domain.WebhookSubscription) bool { return !slices.Contains(parties, sub.UserID) })
+	if len(subs) == 0 {
+		return nil
+	}
+
+	now := s.now()
+	event := domain.Event{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		CreatedAt: now,
+		Data:      data,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("Publish: json.Marshal type domain.Event: %w", err)
+	}
+
+	for _, sub := range subs {
+		d := &domain.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventType:      eventType,
+			Payload:        payload,
+			State:          domain.WebhookDeliveryStatePending,
+			NextAttemptAt:  now,
+		}
+		if err := s.dbClient.CreateWebhookDelivery(ctx, d); err != nil {
+			return fmt.Errorf("Publish: s.dbClient.CreateWebhookDelivery: %w", err)
+		}
+	}
+	return nil
+}
+
+// Run polls for due deliveries and sends them until ctx is cancelled
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.deliverDue(ctx); err != nil {
+				log.Printf("Failed to deliver webhooks: %s", err.Error())
+			}
+		}
+	}
+}
+
+// deliverDue attempts every due delivery. One that cannot be attempted is logged and left due, so that it
+// does not hold up the others.
+func (s *Service) deliverDue(ctx context.Context) error {
+	deliveries, err := s.dbClient.ListDueWebhookDeliveries(ctx, s.now(), pollBatchSize)
+	if err != nil {
+		return fmt.Errorf("s.dbClient.ListDueWebhookDeliveries: %w", err)
+	}
+	for _, d := range deliveries {
+		if err := s.attempt(ctx, d); err != nil {
+			log.Printf("Failed to attempt webhook delivery (%s): %s", d.ID, err.Error())
+		}
+	}
+	return nil
+}
+
+func (s *Service) attempt(ctx context.Context, d *domain.WebhookDelivery) error {
+	sub, err := s.dbClient.GetWebhookSubscriptionByID(ctx, d.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("s.dbClient.GetWebhookSubscriptionByID: %w", err)
+	}
+
+	d.Attempts++
+	if err := s.send(ctx, sub, d); err != nil {
+		d.LastError = err.Error()
+		if d.Attempts >= maxAttempts {
+			d.State = domain.WebhookDeliveryStateDeadLetter
+		} else {
+			d.NextAttemptAt = s.now().Add(backoff(d.Attempts))
+		}
+	} else {
+		d.State = domain.WebhookDeliveryStateDelivered
+		d.LastError = ""
+	}
+
+	if err := s.dbClient.UpdateWebhookDelivery(ctx, d); err != nil {
+		return fmt.Errorf("s.dbClient.UpdateWebhookDelivery: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) send(ctx context.Context, sub *domain.WebhookSubscription, d *domain.WebhookDelivery) error {
+	ts := strconv.FormatInt(s.now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext to (%s): %w", sub.URL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventTypeHeader, string(d.EventType))
+	req.Header.Set(TimestampHeader, ts)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(sub.Secret, ts, d.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("s.httpClient.Do to (%s): %w", sub.URL, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code (%d) from (%s)", resp.StatusCode, sub.URL)
+	}
+	return nil
+}
+
+func (s *Service) getOwnSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	sub, err := s.dbClient.GetWebhookSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("s.dbClient.GetWebhookSubscriptionByID: %w", err)
+	}
+	if sub.UserID != utils.FromContext(ctx) {
+		return nil, fmt.Errorf("subscription (%s) is not owned by the caller: %w", id, domain.ErrForbidden)
+	}
+	return sub, nil
+}
+
+// Sign computes the hex encoded HMAC-SHA256 of "<timestamp>.<payload>".
+// Receivers should recompute it with their secret and compare in constant time.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func backoff(attempts int) time.Duration {
+	d := baseBackoff << (attempts - 1)
+	if d > maxBackoff || d <= 0 {
+		return maxBackoff
+	}
+	return d
+}
@@ -0,0 +1,188 @@
+package grpc
+
+import (
+	marketplacev1 "backend/api/marketplace/v1"
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"context"
+	"errors"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type itemService interface {
+	GetItem(ctx context.Context, id string) (*domain.Item, error)
+	ListItem(ctx context.Context, item *domain.Item) error
+	PurchaseItem(ctx context.Context, item *domain.Item) (*domain.Sale, error)
+}
+
+type eventSubscriber interface {
+	Subscribe(ctx context.Context) <-chan *domain.Event
+}
+
+type Server struct {
+	marketplacev1.UnimplementedMarketplaceServiceServer
+	iSvc   itemService
+	events eventSubscriber
+}
+
+func New(iSvc itemService, events eventSubscriber) *Server {
+	return &Server{
+		iSvc:   iSvc,
+		events: events,
+	}
+}
+
+func (s *Server) GetItem(ctx context.Context, req *marketplacev1.GetItemRequest) (*marketplacev1.GetItemResponse, error) {
+	item, err := s.iSvc.GetItem(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &marketplacev1.GetItemResponse{Item: toProtoItem(item)}, nil
+}
+
+func (s *Server) ListItem(ctx context.Context, req *marketplacev1.ListItemRequest) (*marketplacev1.ListItemResponse, error) {
+	price, err := domain.ParseMoney(req.GetPrice().GetAmount(), req.GetPrice().GetCurrency())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	item := &domain.Item{
+		ID:         req.GetId(),
+		Name:       req.GetName(),
+		Price:      price,
+		NFTID:      req.GetNftId(),
+		Category:   req.GetCategory(),
+		Condition:  domain.Condition(req.GetCondition()),
+		Attributes: req.GetAttributes(),
+		Images:     req.GetImages(),
+		PoolName:   req.GetPoolName(),
+	}
+	if err := s.iSvc.ListItem(ctx, item); err != nil {
+		return nil, toStatus(err)
+	}
+	return &marketplacev1.ListItemResponse{Item: toProtoItem(item)}, nil
+}
+
+func (s *Server) PurchaseItem(ctx context.Context, req *marketplacev1.PurchaseItemRequest) (*marketplacev1.PurchaseItemResponse, error) {
+	sale, err := s.iSvc.PurchaseItem(ctx, &domain.Item{ID: req.GetId()})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &marketplacev1.PurchaseItemResponse{Sale: toProtoSale(sale)}, nil
+}
+
+// WatchItemUpdates streams the updates of items the caller is a party to, like webhook deliveries
+func (s *Server) WatchItemUpdates(req *marketplacev1.WatchItemUpdatesRequest, stream marketplacev1.MarketplaceService_WatchItemUpdatesServer) error {
+	uid := utils.FromContext(stream.Context())
+	filter := make(map[string]struct{}, len(req.GetItemIds()))
+	for _, id := range req.GetItemIds() {
+		filter[id] = struct{}{}
+	}
+
+	for e := range s.events.Subscribe(stream.Context()) {
+		item, ok := e.Data.(*domain.Item)
+		if !ok {
+			continue
+		}
+		if _, ok := filter[item.ID]; len(filter) > 0 && !ok {
+			continue
+		}
+		if !slices.Contains(domain.EventParties(item), uid) {
+			continue
+		}
+		update := &marketplacev1.ItemUpdate{
+			EventId:    e.ID,
+			EventType:  string(e.Type),
+			Item:       toProtoItem(item),
+			OccurredAt: timestamppb.New(e.CreatedAt),
+		}
+		if err := stream.Send(update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toProtoItem(item *domain.Item) *marketplacev1.Item {
+	return &marketplacev1.Item{
+		Id:                   item.ID,
+		Name:                 item.Name,
+		State:                toProtoItemState(item.State),
+		Price:                toProtoMoney(item.Price),
+		NftId:                item.NFTID,
+		SmartContractAddress: item.SmartContractAddress,
+		SellerId:             item.SellerID,
+		BuyerId:              item.BuyerID,
+		CreatorId:            item.CreatorID,
+		Category:             item.Category,
+		Condition:            string(item.Condition),
+		Attributes:           item.Attributes,
+		Images:               item.Images,
+		PoolName:             item.PoolName,
+	}
+}
+
+func toProtoMoney(m domain.Money) *marketplacev1.Money {
+	return &marketplacev1.Money{
+		Amount:   m.Amount().String(),
+		Currency: m.Currency(),
+	}
+}
+
+func toProtoSale(sale *domain.Sale) *marketplacev1.Sale {
+	return &marketplacev1.Sale{
+		Id:             sale.ID,
+		ItemId:         sale.ItemID,
+		SellerId:       sale.SellerID,
+		BuyerId:        sale.BuyerID,
+		CreatorId:      sale.CreatorID,
+		Price:          toProtoMoney(sale.Price),
+		PlatformFee:    toProtoMoney(sale.PlatformFee),
+		Royalty:        toProtoMoney(sale.Royalty),
+		SellerProceeds: toProtoMoney(sale.SellerProceeds),
+		CreatedAt:      timestamppb.New(sale.CreatedAt),
+	}
+}
+
+func toProtoItemState(state domain.ItemState) marketplacev1.ItemState {
+	switch state {
+	case domain.ItemStateListed:
+		return marketplacev1.ItemState_ITEM_STATE_LISTED
+	case domain.ItemStateSold:
+		return marketplacev1.ItemState_ITEM_STATE_SOLD
+	case domain.ItemStateShipped:
+		return marketplacev1.ItemState_ITEM_STATE_SHIPPED
+	case domain.ItemStateReceived:
+		return marketplacev1.ItemState_ITEM_STATE_RECEIVED
+	case domain.ItemStateCancelled:
+		return marketplacev1.ItemState_ITEM_STATE_CANCELLED
+	case domain.ItemStateDisputed:
+		return marketplacev1.ItemState_ITEM_STATE_DISPUTED
+	case domain.ItemStateRefunded:
+		return marketplacev1.ItemState_ITEM_STATE_REFUNDED
+	}
+	return marketplacev1.ItemState_ITEM_STATE_UNSPECIFIED
+}
+
+// toStatus maps domain errors to gRPC status codes, mirroring writeError on the HTTP side
+func toStatus(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, domain.ErrInvalidArgument):
+		code = codes.InvalidArgument
+	case errors.Is(err, domain.ErrForbidden):
+		code = codes.PermissionDenied
+	case errors.Is(err, domain.ErrConflict), errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, domain.ErrInsufficientFunds):
+		code = codes.FailedPrecondition
+	case errors.Is(err, domain.ErrUnsupported):
+		code = codes.Unimplemented
+	case errors.Is(err, domain.ErrRateLimited):
+		code = codes.ResourceExhausted
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"backend/internal/domain"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	var search domain.SavedSearch
+	if err := json.NewDecoder(r.Body).Decode(&search); err != nil {
+		writeError(w, fmt.Errorf("json decode saved search: %w", domain.ErrInvalidArgument))
+		return
+	}
+	if err := s.aSvc.CreateSearch(r.Context(), &search); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, search)
+}
+
+func (s *Server) ListSavedSearches(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.aSvc.ListSearches(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	if err := s.aSvc.DeleteSearch(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) WatchItem(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.aSvc.WatchItem(r.Context(), mux.Vars(r)["item_id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) UnwatchItem(w http.ResponseWriter, r *http.Request) {
+	if err := s.aSvc.UnwatchItem(r.Context(), mux.Vars(r)["item_id"]); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) ListWatchlist(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.aSvc.ListWatchlist(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) GetAlertSettings(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.aSvc.GetSettings(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) UpdateAlertSettings(w http.ResponseWriter, r *http.Request) {
+	var settings domain.AlertSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		writeError(w, fmt.Errorf("json decode alert settings: %w", domain.ErrInvalidArgument))
+		return
+	}
+	if err := s.aSvc.UpdateSettings(r.Context(), &settings); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := s.aSvc.GetSettings(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.aSvc.ListNotifications(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"backend/internal/domain"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type disputeRequest struct {
+	// Note is the buyer's reason when opening a dispute, and the seller's or admin's statement afterwards
+	Note     string   `json:"note"`
+	Evidence []string `json:"evidence,omitempty"`
+	// Outcome is the admin's ruling, refund or release
+	Outcome domain.DisputeOutcome `json:"outcome,omitempty"`
+}
+
+func decodeDisputeRequest(r *http.Request) (*disputeRequest, error) {
+	var req disputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("json decode dispute: %w", domain.ErrInvalidArgument)
+	}
+	return &req, nil
+}
+
+func (s *Server) OpenDispute(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeDisputeRequest(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := s.dSvc.OpenDispute(r.Context(), mux.Vars(r)["id"], req.Note, req.Evidence)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (s *Server) RespondToDispute(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeDisputeRequest(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := s.dSvc.RespondToDispute(r.Context(), mux.Vars(r)["id"], req.Note, req.Evidence)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) ResolveDispute(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeDisputeRequest(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := s.dSvc.ResolveDispute(r.Context(), mux.Vars(r)["id"], req.Outcome, req.Note)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) GetDispute(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.dSvc.GetDispute(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ListDisputes defaults to the disputes waiting for arbitration
+func (s *Server) ListDisputes(w http.ResponseWriter, r *http.Request) {
+	state := domain.DisputeState(r.URL.Query().Get("state"))
+	if len(state) == 0 {
+		state = domain.DisputeStateResponded
+	}
+	resp, err := s.dSvc.ListDisputes(r.Context(), state)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
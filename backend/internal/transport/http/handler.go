@@ -4,66 +4,334 @@ import (
 	"backend/internal/domain"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
 )
 
 type itemService interface {
 	GetItem(ctx context.Context, id string) (*domain.Item, error)
 	ListItem(ctx context.Context, item *domain.Item) error
-	PurchaseItem(ctx context.Context, item *domain.Item) error
+	PurchaseItem(ctx context.Context, item *domain.Item) (*domain.Sale, error)
+	ShipItem(ctx context.Context, id string) error
+	ReceiveItem(ctx context.Context, id string) error
+	CancelItem(ctx context.Context, id string) error
+	DelistItem(ctx context.Context, id string) error
+	UpdatePrice(ctx context.Context, id string, price domain.Money) (*domain.PriceChange, error)
+	ListPriceChanges(ctx context.Context, id string) ([]*domain.PriceChange, error)
+	GetItemContract(ctx context.Context, id string) (*domain.ContractDeployment, error)
+}
+
+type webhookService interface {
+	CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error
+	ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, subscriptionID string) ([]*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, deliveryID string) error
+}
+
+type offerService interface {
+	MakeOffer(ctx context.Context, itemID string, amount domain.Money, ttl time.Duration) (*domain.Offer, error)
+	CounterOffer(ctx context.Context, id string, amount domain.Money, ttl time.Duration) (*domain.Offer, error)
+	AcceptOffer(ctx context.Context, id string) (*domain.Sale, error)
+	RejectOffer(ctx context.Context, id string) error
+	ListOffers(ctx context.Context, itemID string) ([]*domain.Offer, error)
+}
+
+type shipmentService interface {
+	SetShippingAddress(ctx context.Context, itemID string, addr *domain.ShippingAddress) error
+	GetShippingAddress(ctx context.Context, itemID string) (*domain.ShippingAddress, error)
+	RecordShipment(ctx context.Context, itemID, carrier, trackingNumber string) (*domain.Shipment, error)
+	GetShipment(ctx context.Context, itemID string) (*domain.Shipment, error)
+	ConfirmDelivery(ctx context.Context, itemID string) (*domain.Shipment, error)
+}
+
+type disputeService interface {
+	OpenDispute(ctx context.Context, itemID, reason string, evidence []string) (*domain.Dispute, error)
+	RespondToDispute(ctx context.Context, id, note string, evidence []string) (*domain.Dispute, error)
+	ResolveDispute(ctx context.Context, id string, outcome domain.DisputeOutcome, note string) (*domain.Dispute, error)
+	GetDispute(ctx context.Context, id string) (*domain.Dispute, error)
+	ListDisputes(ctx context.Context, state domain.DisputeState) ([]*domain.Dispute, error)
+}
+
+type provenanceService interface {
+	GetProvenance(ctx context.Context, poolName, tokenIndex string) (*domain.Provenance, error)
+	ExportProvenance(ctx context.Context, poolName, tokenIndex string) (*domain.SignedProvenance, error)
+	PublicKey() *domain.PublicKey
+}
+
+type verifyService interface {
+	Verify(ctx context.Context, poolName, tokenIndex string) (*domain.Verification, error)
+	VerifyCode(ctx context.Context, payload string) (*domain.Verification, error)
+}
+
+type estimateService interface {
+	Estimate(ctx context.Context, itemID string) (*domain.PriceEstimate, error)
+	EstimateItem(ctx context.Context, item *domain.Item) (*domain.PriceEstimate, error)
+}
+
+type mediaService interface {
+	Upload(ctx context.Context, data []byte) (*domain.Media, error)
+	Open(ctx context.Context, hash string) ([]byte, string, error)
+	MaxSize() int64
+}
+
+type reviewService interface {
+	CreateReview(ctx context.Context, itemID string, rating int, text string) (*domain.Review, error)
+	GetReview(ctx context.Context, id string) (*domain.CheckedReview, error)
+	ListSellerReviews(ctx context.Context, sellerID string) ([]*domain.Review, error)
+	Reputation(ctx context.Context, sellerID string) (*domain.Reputation, error)
+}
+
+type alertService interface {
+	CreateSearch(ctx context.Context, search *domain.SavedSearch) error
+	ListSearches(ctx context.Context) ([]*domain.SavedSearch, error)
+	DeleteSearch(ctx context.Context, id string) error
+	WatchItem(ctx context.Context, itemID string) (*domain.WatchedItem, error)
+	UnwatchItem(ctx context.Context, itemID string) error
+	ListWatchlist(ctx context.Context) ([]*domain.WatchedItem, error)
+	GetSettings(ctx context.Context) (*domain.AlertSettings, error)
+	UpdateSettings(ctx context.Context, settings *domain.AlertSettings) error
+	ListNotifications(ctx context.Context) ([]*domain.Notification, error)
+}
+
+type importService interface {
+	Import(ctx context.Context, format domain.ImportFormat, data []byte) (*domain.ImportJob, error)
+	ListImports(ctx context.Context) ([]*domain.ImportJob, error)
+	GetImport(ctx context.Context, id string) (*domain.ImportJob, error)
+	RetryImport(ctx context.Context, id string) (*domain.ImportJob, error)
 }
 
 type Server struct {
-	iSvc itemService
+	iSvc  itemService
+	wSvc  webhookService
+	oSvc  offerService
+	shSvc shipmentService
+	dSvc  disputeService
+	pSvc  provenanceService
+	vSvc  verifyService
+	eSvc  estimateService
+	mSvc  mediaService
+	rSvc  reviewService
+	aSvc  alertService
+	imSvc importService
 }
 
-func New(iSvc itemService) *Server {
+func New(iSvc itemService, wSvc webhookService, oSvc offerService, shSvc shipmentService, dSvc disputeService, pSvc provenanceService, vSvc verifyService, eSvc estimateService, mSvc mediaService, rSvc reviewService, aSvc alertService, imSvc importService) *Server {
 	return &Server{
-		iSvc: iSvc,
+		iSvc:  iSvc,
+		wSvc:  wSvc,
+		oSvc:  oSvc,
+		shSvc: shSvc,
+		dSvc:  dSvc,
+		pSvc:  pSvc,
+		vSvc:  vSvc,
+		eSvc:  eSvc,
+		mSvc:  mSvc,
+		rSvc:  rSvc,
+		aSvc:  aSvc,
+		imSvc: imSvc,
 	}
 }
 
 func (s *Server) ListItem(w http.ResponseWriter, r *http.Request) {
 	var item domain.Item
-	s.unmarshalItem(&item, w, r)
+	if !s.unmarshalItem(&item, w, r) {
+		return
+	}
 	if err := s.iSvc.ListItem(r.Context(), &item); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(fmt.Sprintf("500 - Something bad happened!: %s", err.Error())))
+		writeError(w, err)
+		return
+	}
+	// The listing stands either way, the estimate is only a suggestion for repricing it
+	resp := listItemResponse{Item: &item}
+	estimate, err := s.eSvc.EstimateItem(r.Context(), &item)
+	if err == nil {
+		resp.SuggestedPrice = estimate
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		log.Printf("Failed to estimate the price of item (%s): %s", item.ID, err.Error())
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type listItemResponse struct {
+	Item *domain.Item `json:"item"`
+	// SuggestedPrice is omitted when there are no sales to estimate the item's price from
+	SuggestedPrice *domain.PriceEstimate `json:"suggested_price,omitempty"`
+}
+
+// ListCategories returns the category taxonomy with the attributes items of each category take, and the condition grades
+func (s *Server) ListCategories(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, categoriesResponse{
+		Categories: domain.Categories(),
+		Conditions: domain.Conditions,
+	})
+}
+
+type categoriesResponse struct {
+	Categories []*domain.Category `json:"categories"`
+	Conditions []domain.Condition `json:"conditions"`
+}
+
+func (s *Server) GetEstimate(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.eSvc.Estimate(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
 	}
+	writeJSON(w, http.StatusOK, resp)
 }
 
 func (s *Server) PurchaseItem(w http.ResponseWriter, r *http.Request) {
 	var item domain.Item
-	s.unmarshalItem(&item, w, r)
-	if err := s.iSvc.PurchaseItem(r.Context(), &item); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(fmt.Sprintf("500 - Something bad happened!: %s", err.Error())))
+	if !s.unmarshalItem(&item, w, r) {
+		return
+	}
+	sale, err := s.iSvc.PurchaseItem(r.Context(), &item)
+	if err != nil {
+		writeError(w, err)
+		return
 	}
+	writeJSON(w, http.StatusOK, sale)
 }
 
 func (s *Server) GetItem(w http.ResponseWriter, r *http.Request) {
 	var item domain.Item
-	s.unmarshalItem(&item, w, r)
+	if !s.unmarshalItem(&item, w, r) {
+		return
+	}
 	resp, err := s.iSvc.GetItem(r.Context(), item.ID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(fmt.Sprintf("500 - Something bad happened!: %s", err.Error())))
+		writeError(w, err)
+		return
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	// The item is shown without its seller's reputation rather than not at all
+	rep, err := s.rSvc.Reputation(r.Context(), resp.SellerID)
+	if err != nil {
+		log.Printf("Failed to get the reputation of seller (%s): %s", resp.SellerID, err.Error())
+	}
+	writeJSON(w, http.StatusOK, itemResponse{Item: resp, SellerReputation: rep})
+}
+
+// itemResponse is the item's fields with its seller's reputation next to them
+type itemResponse struct {
+	*domain.Item
+	SellerReputation *domain.Reputation `json:"seller_reputation,omitempty"`
+}
+
+func (s *Server) ShipItem(w http.ResponseWriter, r *http.Request) {
+	if err := s.iSvc.ShipItem(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) ReceiveItem(w http.ResponseWriter, r *http.Request) {
+	if err := s.iSvc.ReceiveItem(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) CancelItem(w http.ResponseWriter, r *http.Request) {
+	if err := s.iSvc.CancelItem(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) DelistItem(w http.ResponseWriter, r *http.Request) {
+	if err := s.iSvc.DelistItem(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type updatePriceRequest struct {
+	Price domain.Money `json:"item_price"`
+}
+
+func (s *Server) UpdatePrice(w http.ResponseWriter, r *http.Request) {
+	var req updatePriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Errorf("json decode price: %w", domain.ErrInvalidArgument))
+		return
+	}
+	resp, err := s.iSvc.UpdatePrice(r.Context(), mux.Vars(r)["id"], req.Price)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) ListPriceChanges(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.iSvc.ListPriceChanges(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) GetItemContract(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.iSvc.GetItemContract(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
 }
 
-func (s *Server) unmarshalItem(item *domain.Item, w http.ResponseWriter, r *http.Request) {
+// unmarshalItem reads the item from the request body, writing the error response when it cannot
+func (s *Server) unmarshalItem(item *domain.Item, w http.ResponseWriter, r *http.Request) bool {
 	b, err := io.ReadAll(r.Body)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte("500 - Something bad happened!"))
+		return false
 	}
 	if err := json.Unmarshal(b, &item); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("500 - Something bad happened!"))
+		writeError(w, fmt.Errorf("json.Unmarshal item: %w: %w", domain.ErrInvalidArgument, err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError maps domain errors to a status code, falling back to 500
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidArgument):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrConflict), errors.Is(err, domain.ErrInvalidTransition):
+		status = http.StatusConflict
+	case errors.Is(err, domain.ErrUnsupported):
+		status = http.StatusNotImplemented
+	case errors.Is(err, domain.ErrInsufficientFunds):
+		status = http.StatusPaymentRequired
+	case errors.Is(err, domain.ErrRateLimited):
+		status = http.StatusTooManyRequests
 	}
+	w.WriteHeader(status)
+	w.Write([]byte(fmt.Sprintf("%d - Something bad happened!: %s", status, err.Error())))
 }
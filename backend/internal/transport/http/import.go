@@ -0,0 +1,79 @@
+package http
+
+import (
+	"backend/internal/domain"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// maxImportSize is the most bytes an import file can have
+const maxImportSize = 10 << 20
+
+// importFormats maps the content types an import file can be sent with to its format
+var importFormats = map[string]domain.ImportFormat{
+	"text/csv":             domain.ImportFormatCSV,
+	"application/x-ndjson": domain.ImportFormatJSONL,
+	"application/jsonl":    domain.ImportFormatJSONL,
+}
+
+// ImportItems takes the file as the raw body. Its format is the format query parameter, or else
+// comes from the content type.
+func (s *Server) ImportItems(w http.ResponseWriter, r *http.Request) {
+	format := domain.ImportFormat(r.URL.Query().Get("format"))
+	if len(format) == 0 {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format = importFormats[mediaType]
+	}
+	if !format.Valid() {
+		writeError(w, fmt.Errorf("format must be %s or %s, as the format parameter or content type: %w", domain.ImportFormatCSV, domain.ImportFormatJSONL, domain.ErrInvalidArgument))
+		return
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, fmt.Errorf("import file is larger than %d bytes: %w", maxImportSize, domain.ErrInvalidArgument))
+			return
+		}
+		writeError(w, fmt.Errorf("io.ReadAll import file: %w", err))
+		return
+	}
+	resp, err := s.imSvc.Import(r.Context(), format, data)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+func (s *Server) ListImports(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.imSvc.ListImports(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) GetImport(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.imSvc.GetImport(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) RetryImport(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.imSvc.RetryImport(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, resp)
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// UploadMedia takes the raw image as the request body. Its type is sniffed from its bytes, not taken from headers.
+func (s *Server) UploadMedia(w http.ResponseWriter, r *http.Request) {
+	// One byte over the limit is enough for the service to reject the upload as too large
+	data, err := io.ReadAll(io.LimitReader(r.Body, s.mSvc.MaxSize()+1))
+	if err != nil {
+		writeError(w, fmt.Errorf("io.ReadAll: %w", err))
+		return
+	}
+	resp, err := s.mSvc.Upload(r.Context(), data)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// GetMedia serves an image or thumbnail by its hash. Content under a hash never changes, so it can be cached for good.
+func (s *Server) GetMedia(w http.ResponseWriter, r *http.Request) {
+	hash := mux.Vars(r)["hash"]
+	data, contentType, err := s.mSvc.Open(r.Context(), hash)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", `"`+hash+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
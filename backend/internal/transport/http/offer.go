@@ -0,0 +1,86 @@
+package http
+
+import (
+	"backend/internal/domain"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type offerRequest struct {
+	Amount domain.Money `json:"amount"`
+	// ExpiresIn is a duration such as "24h", defaulting to 48 hours
+	ExpiresIn string `json:"expires_in,omitempty"`
+}
+
+func decodeOfferRequest(r *http.Request) (domain.Money, time.Duration, error) {
+	var req offerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return domain.Money{}, 0, fmt.Errorf("json decode offer: %w", domain.ErrInvalidArgument)
+	}
+	var ttl time.Duration
+	if len(req.ExpiresIn) > 0 {
+		var err error
+		if ttl, err = time.ParseDuration(req.ExpiresIn); err != nil {
+			return domain.Money{}, 0, fmt.Errorf("expires_in (%s) is not a duration: %w", req.ExpiresIn, domain.ErrInvalidArgument)
+		}
+	}
+	return req.Amount, ttl, nil
+}
+
+func (s *Server) MakeOffer(w http.ResponseWriter, r *http.Request) {
+	amount, ttl, err := decodeOfferRequest(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := s.oSvc.MakeOffer(r.Context(), mux.Vars(r)["id"], amount, ttl)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (s *Server) ListOffers(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.oSvc.ListOffers(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) CounterOffer(w http.ResponseWriter, r *http.Request) {
+	amount, ttl, err := decodeOfferRequest(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := s.oSvc.CounterOffer(r.Context(), mux.Vars(r)["id"], amount, ttl)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (s *Server) AcceptOffer(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.oSvc.AcceptOffer(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) RejectOffer(w http.ResponseWriter, r *http.Request) {
+	if err := s.oSvc.RejectOffer(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
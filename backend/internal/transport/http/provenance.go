@@ -0,0 +1,30 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// GetProvenance takes the NFT's token index, and the pool it is in as ?pool= when it is not the default pool
+func (s *Server) GetProvenance(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.pSvc.GetProvenance(r.Context(), r.URL.Query().Get("pool"), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) ExportProvenance(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.pSvc.ExportProvenance(r.Context(), r.URL.Query().Get("pool"), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) GetProvenancePublicKey(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.pSvc.PublicKey())
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"backend/internal/domain"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type reviewRequest struct {
+	Rating int    `json:"rating"`
+	Text   string `json:"text"`
+}
+
+// CreateReview takes the item the caller bought, whose latest completed purchase by them is reviewed
+func (s *Server) CreateReview(w http.ResponseWriter, r *http.Request) {
+	var req reviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Errorf("json decode review: %w", domain.ErrInvalidArgument))
+		return
+	}
+	resp, err := s.rSvc.CreateReview(r.Context(), mux.Vars(r)["id"], req.Rating, req.Text)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (s *Server) GetReview(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.rSvc.GetReview(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) ListSellerReviews(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.rSvc.ListSellerReviews(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) GetSellerReputation(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.rSvc.Reputation(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
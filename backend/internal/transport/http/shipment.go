@@ -0,0 +1,69 @@
+package http
+
+import (
+	"backend/internal/domain"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type shipmentRequest struct {
+	Carrier        string `json:"carrier"`
+	TrackingNumber string `json:"tracking_number"`
+}
+
+func (s *Server) SetShippingAddress(w http.ResponseWriter, r *http.Request) {
+	var addr domain.ShippingAddress
+	if err := json.NewDecoder(r.Body).Decode(&addr); err != nil {
+		writeError(w, fmt.Errorf("json decode shipping address: %w", domain.ErrInvalidArgument))
+		return
+	}
+	if err := s.shSvc.SetShippingAddress(r.Context(), mux.Vars(r)["id"], &addr); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) GetShippingAddress(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.shSvc.GetShippingAddress(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) RecordShipment(w http.ResponseWriter, r *http.Request) {
+	var req shipmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Errorf("json decode shipment: %w", domain.ErrInvalidArgument))
+		return
+	}
+	resp, err := s.shSvc.RecordShipment(r.Context(), mux.Vars(r)["id"], req.Carrier, req.TrackingNumber)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (s *Server) GetShipment(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.shSvc.GetShipment(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) ConfirmDelivery(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.shSvc.ConfirmDelivery(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,23 @@
+package http
+
+import (
+	"backend/internal/domain"
+	"net/http"
+)
+
+// Verify is public. It takes the payload of an item's QR code as ?qr=, or ?token= with an optional ?pool=.
+func (s *Server) Verify(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var resp *domain.Verification
+	var err error
+	if qr := q.Get("qr"); len(qr) > 0 {
+		resp, err = s.vSvc.VerifyCode(r.Context(), qr)
+	} else {
+		resp, err = s.vSvc.Verify(r.Context(), q.Get("pool"), q.Get("token"))
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"backend/internal/domain"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) CreateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	var sub domain.WebhookSubscription
+	if err := json.NewDecoder(r.Body).Decode(&sub); err != nil {
+		writeError(w, fmt.Errorf("json decode webhook subscription: %w", domain.ErrInvalidArgument))
+		return
+	}
+	if err := s.wSvc.CreateSubscription(r.Context(), &sub); err != nil {
+		writeError(w, err)
+		return
+	}
+	// The secret is only echoed back on creation
+	writeJSON(w, http.StatusCreated, sub)
+}
+
+func (s *Server) ListWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subs, err := s.wSvc.ListSubscriptions(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, subs)
+}
+
+func (s *Server) DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	if err := s.wSvc.DeleteSubscription(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := s.wSvc.ListDeliveries(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+func (s *Server) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := s.wSvc.Redeliver(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}